@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/carddb"
+	"github.com/gravestench/mtg/pkg/cardquery"
+)
+
+const usage = `usage: carddb [-dir <directory>] <command>
+
+commands:
+  import <bulk data file>         imports a scryfall bulk data file
+  card <name>                     shows the newest printing of a card
+  printing <set> <number>         shows a printing by set code and collector number
+  printings <name>                lists every printing of a card
+  set <set>                       lists the cards of a set
+  search <query>                  lists the cards which match a query in the
+                                  scryfall search syntax, like "t:goblin c:r mv<=2"
+`
+
+func main() {
+	dir := flag.String("dir", "card_database", "the card database directory")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*dir, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, command string, args []string) error {
+	if command == "import" {
+		db, err := carddb.ImportFile(dir, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("imported %d cards into %s\n", db.Len(), dir)
+
+		return db.Close()
+	}
+
+	db, err := carddb.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	var cards []scryfall.Card
+
+	switch command {
+	case "card":
+		card, errLookup := db.CardByName(strings.Join(args, " "))
+		if errLookup != nil {
+			return errLookup
+		}
+
+		cards = append(cards, *card)
+	case "printing":
+		if len(args) < 2 {
+			return fmt.Errorf("printing: missing collector number")
+		}
+
+		card, errLookup := db.CardByPrinting(args[0], args[1])
+		if errLookup != nil {
+			return errLookup
+		}
+
+		cards = append(cards, *card)
+	case "printings":
+		card, errLookup := db.CardByName(strings.Join(args, " "))
+		if errLookup != nil {
+			return errLookup
+		}
+
+		cards, err = db.Printings(card.OracleID)
+	case "set":
+		cards, err = db.SetCards(args[0])
+	case "search":
+		q, errParse := cardquery.Parse(strings.Join(args, " "))
+		if errParse != nil {
+			return errParse
+		}
+
+		if name, isName := q.Name(); isName {
+			cards, err = db.Search(name)
+		} else {
+			cards, err = db.Filter(q.Match)
+		}
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, card := range cards {
+		fmt.Printf("%-6s %-6s %-9s %s\n", strings.ToUpper(card.Set), card.CollectorNumber, card.Rarity, card.Name)
+	}
+
+	return nil
+}
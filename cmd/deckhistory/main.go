@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/deckhistory"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/tappedout"
+)
+
+const usage = `usage: deckhistory [-config <directory>] [-dir <directory>] <command>
+
+commands:
+  decks                         lists every deck with a history
+  versions <deck>               lists the versions of a deck
+  diff <deck> [<from> [<to>]]   shows what changed between two versions,
+                                by index, the last two versions by default
+  log <deck>                    shows what changed in every version
+
+the history directory is the one the tappedout service keeps the fetched
+decks in, unless -dir is given
+`
+
+func main() {
+	config := flag.String("config", "~/.config/mtg", "the config directory")
+	dir := flag.String("dir", "", "the deck history directory")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *dir == "" {
+		var err error
+		if *dir, err = historyDirectory(*config); err != nil {
+			fmt.Fprintf(os.Stderr, "reading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := run(deckhistory.New(*dir), flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// historyDirectory yields the history directory of the tappedout service
+// with the given config directory
+func historyDirectory(config string) (string, error) {
+	cfgManager := &configFile.Service{RootDirectory: config}
+
+	// without a config file, the service uses its default directory
+	cfg, err := cfgManager.LoadConfigWithFileName((&tappedout.Service{}).ConfigFileName())
+	if err != nil {
+		return "", err
+	}
+
+	return tappedout.HistoryDirectory(cfgManager, cfg), nil
+}
+
+func run(store *deckhistory.Store, args []string) error {
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command, args := args[0], args[1:]
+
+	if command == "decks" {
+		decks, err := store.Decks()
+		if err != nil {
+			return err
+		}
+
+		for _, deck := range decks {
+			fmt.Println(deck)
+		}
+
+		return nil
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("%s: missing deck", command)
+	}
+
+	slug := args[0]
+
+	versions, err := store.Versions(slug)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "versions":
+		for idx, v := range versions {
+			fmt.Printf("%3d  %s\n", idx, formatTime(v.Time))
+		}
+	case "diff":
+		from, to, err := versionRange(args[1:], len(versions))
+		if err != nil {
+			return err
+		}
+
+		changes, err := store.Diff(versions[from], versions[to])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s -> %s\n\n%s", formatTime(versions[from].Time), formatTime(versions[to].Time), changes)
+	case "log":
+		log, err := store.Changelog(slug)
+		if err != nil {
+			return err
+		}
+
+		// newest first, like git log
+		for idx := len(log) - 1; idx >= 0; idx-- {
+			fmt.Printf("== %s\n\n%s\n", formatTime(log[idx].To.Time), log[idx].Changes)
+		}
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	return nil
+}
+
+// versionRange yields the version indices to diff, the last two by default
+func versionRange(args []string, count int) (from, to int, err error) {
+	if count < 2 {
+		return 0, 0, fmt.Errorf("need at least two versions to diff, have %d", count)
+	}
+
+	from, to = count-2, count-1
+
+	for idx, arg := range args {
+		n, errParse := strconv.Atoi(arg)
+		if errParse != nil || n < 0 || n >= count {
+			return 0, 0, fmt.Errorf("invalid version %q, expected 0 to %d", arg, count-1)
+		}
+
+		if idx == 0 {
+			from = n
+		} else {
+			to = n
+		}
+	}
+
+	return from, to, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -13,7 +13,13 @@ import (
 	"github.com/gravestench/mtg/pkg/services/tappedout"
 )
 
+// defaultDeckURL is the deck shown when no other deck is given
+const defaultDeckURL = "https://tappedout.net/mtg-decks/a-slow-painful-death/"
+
 type bootstrap struct {
+	// deckURL is the deck to show, from any deck site or a local file
+	deckURL string
+
 	logger    *zerolog.Logger
 	renderer  raylibRenderer.Dependency
 	scryfall  scryfall.Dependency
@@ -37,6 +43,10 @@ func (s *bootstrap) DependenciesResolved() bool {
 		return false
 	}
 
+	if s.tappedout == nil {
+		return false
+	}
+
 	return true
 }
 
@@ -54,11 +64,9 @@ func (s *bootstrap) ResolveDependencies(r runtime.R) {
 }
 
 func (s *bootstrap) Init(r runtime.R) {
-	const deckURL = "https://tappedout.net/mtg-decks/a-slow-painful-death/"
-
-	list, err := s.tappedout.GetDeckList(deckURL)
+	list, err := s.tappedout.GetDeckList(s.deckURL)
 	if err != nil {
-		s.logger.Error().Msgf("getting deck list from tappedout: %v", err)
+		s.logger.Error().Msgf("getting deck list %s: %v", s.deckURL, err)
 		return
 	}
 
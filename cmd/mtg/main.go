@@ -1,25 +1,58 @@
 package main
 
 import (
+	"flag"
+
 	"github.com/faiface/mainthread"
 	"github.com/gravestench/runtime"
 
+	"github.com/gravestench/mtg/pkg/services/booster"
 	"github.com/gravestench/mtg/pkg/services/cacheManager"
+	"github.com/gravestench/mtg/pkg/services/cardScripts"
+	"github.com/gravestench/mtg/pkg/services/collection"
 	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/deckLibrary"
+	"github.com/gravestench/mtg/pkg/services/deckStats"
+	"github.com/gravestench/mtg/pkg/services/deckValidator"
+	"github.com/gravestench/mtg/pkg/services/fileWatcher"
+	"github.com/gravestench/mtg/pkg/services/gameServer"
+	"github.com/gravestench/mtg/pkg/services/lua"
+	"github.com/gravestench/mtg/pkg/services/mtgapi"
+	"github.com/gravestench/mtg/pkg/services/prices"
 	"github.com/gravestench/mtg/pkg/services/raylibRenderer"
 	"github.com/gravestench/mtg/pkg/services/scryfall"
+	"github.com/gravestench/mtg/pkg/services/setInfo"
 	"github.com/gravestench/mtg/pkg/services/tappedout"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+	"github.com/gravestench/mtg/pkg/services/webServer"
 )
 
 func main() {
+	deckURL := flag.String("deck", defaultDeckURL, "the deck to show, a url of tappedout, moxfield, archidekt, mtggoldfish or deckstats, the slug of a tappedout deck, or a deck list file")
+	flag.Parse()
+
 	rt := runtime.New("MTG")
 
 	rt.Add(&cacheManager.Service{})
 	rt.Add(&configFile.Service{RootDirectory: "~/.config/mtg"})
 	rt.Add(&raylibRenderer.Service{})
 	rt.Add(&scryfall.Service{})
+	rt.Add(&mtgapi.Service{})
+	rt.Add(&setInfo.Service{})
 	rt.Add(&tappedout.Service{})
-	rt.Add(&bootstrap{})
+	rt.Add(&webRouter.Service{})
+	rt.Add(&webServer.Service{})
+	rt.Add(&gameServer.Service{})
+	rt.Add(&deckStats.Service{})
+	rt.Add(&deckValidator.Service{})
+	rt.Add(&collection.Service{})
+	rt.Add(&deckLibrary.Service{})
+	rt.Add(&prices.Service{})
+	rt.Add(&booster.Service{})
+	rt.Add(&lua.Service{})
+	rt.Add(&fileWatcher.Service{})
+	rt.Add(&cardScripts.Service{})
+	rt.Add(&bootstrap{deckURL: *deckURL})
 
 	mainthread.Run(rt.Run)
 }
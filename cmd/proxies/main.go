@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/proxysheet"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+const usage = `usage: proxies [flags] [<deck list file>]
+
+Lays out the cards of a deck list on printable pages at their true size,
+3x3 cards to a page, as png pages and a pdf. The deck list is read from
+stdin when no file is given, in any format the decklist package can parse.
+
+flags:
+`
+
+func main() {
+	opts := options{sheet: proxysheet.DefaultOptions()}
+
+	page := flag.String("page", proxysheet.A4.Name, "the page size, a4 or letter")
+	flag.IntVar(&opts.sheet.DPI, "dpi", proxysheet.DefaultDPI, "the resolution of the pages")
+	flag.Float64Var(&opts.sheet.BleedMM, "bleed", 0, "the bleed around every card, in millimeters")
+	flag.BoolVar(&opts.sheet.CutMarks, "cutmarks", true, "draw cut marks in the page margins")
+	flag.BoolVar(&opts.sideboard, "sideboard", false, "include the sideboard")
+	flag.StringVar(&opts.out, "out", "proxies", "the output directory")
+	flag.StringVar(&opts.config, "config", "~/.config/mtg", "the config directory")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var found bool
+	if opts.sheet.Page, found = proxysheet.PageSizes[*page]; !found {
+		fmt.Fprintf(os.Stderr, "unknown page size %q\n", *page)
+		os.Exit(2)
+	}
+
+	list, err := readList(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reading deck list: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts.list = list
+
+	if err = run(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func readList(path string) (string, error) {
+	if path == "" || path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+
+	data, err := os.ReadFile(path)
+
+	return string(data), err
+}
+
+// run looks up the card images with the scryfall service, and waits for
+// the proxies to be written
+func run(opts options) error {
+	done := make(chan error, 1)
+
+	rt := runtime.New("Proxies")
+
+	// added first, so that it sees the scryfall service being initialized
+	rt.Add(&proxies{options: opts, done: done})
+	rt.Add(&configFile.Service{RootDirectory: opts.config})
+	rt.Add(&scryfall.Service{})
+
+	err := <-done
+
+	rt.Shutdown().Wait()
+
+	return err
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/proxysheet"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+type options struct {
+	list      string
+	sheet     proxysheet.Options
+	sideboard bool
+	out       string
+	config    string
+}
+
+// proxies is a service which writes the proxy pages of a deck list as soon
+// as the scryfall service is initialized
+type proxies struct {
+	logger  *zerolog.Logger
+	options options
+	done    chan<- error
+}
+
+func (s *proxies) Name() string {
+	return "Proxies"
+}
+
+func (s *proxies) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *proxies) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+func (s *proxies) Init(r runtime.R) {}
+
+// OnServiceInitialized writes the proxies once the scryfall service can be
+// used, resolving it as a dependency would not wait for its initialization
+func (s *proxies) OnServiceInitialized(args ...any) {
+	if len(args) < 1 {
+		return
+	}
+
+	if candidate, ok := args[0].(scryfall.Dependency); ok {
+		go func() { s.done <- s.write(candidate) }()
+	}
+}
+
+func (s *proxies) write(client scryfall.Dependency) error {
+	deck, err := decklist.Parse(s.options.list)
+	if err != nil {
+		s.logger.Warn().Msgf("parsing deck list: %v", err)
+	}
+
+	sections := []decklist.Section{decklist.SectionCommander, decklist.SectionCompanion, decklist.SectionMain}
+	if s.options.sideboard {
+		sections = append(sections, decklist.SectionSideboard)
+	}
+
+	printed := decklist.New()
+
+	for _, section := range sections {
+		for _, entry := range deck.Section(section) {
+			printed.Add(section, entry)
+		}
+	}
+
+	deckImages, err := client.GetDeckImages(printed)
+	if err != nil {
+		return fmt.Errorf("getting card images: %v", err)
+	}
+
+	// every copy of a card, with every face of double-faced cards
+	cards := make([]image.Image, 0)
+
+	for _, cardImages := range deckImages {
+		for n := 0; n < cardImages.Entry.Count; n++ {
+			cards = append(cards, cardImages.Faces...)
+		}
+	}
+
+	if len(cards) == 0 {
+		return fmt.Errorf("no card images found")
+	}
+
+	pages, err := proxysheet.Render(cards, s.options.sheet)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(s.options.out, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %v", err)
+	}
+
+	for idx, page := range pages {
+		path := filepath.Join(s.options.out, fmt.Sprintf("page-%02d.png", idx+1))
+
+		if err = writePNG(path, page); err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(s.options.out, "proxies.pdf")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating pdf: %v", err)
+	}
+
+	defer f.Close()
+
+	if err = proxysheet.WritePDF(f, pages, s.options.sheet); err != nil {
+		return fmt.Errorf("writing pdf: %v", err)
+	}
+
+	s.logger.Info().Msgf("wrote %d cards on %d pages to %s", len(cards), len(pages), s.options.out)
+
+	return f.Close()
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating page: %v", err)
+	}
+
+	defer f.Close()
+
+	if err = png.Encode(f, img); err != nil {
+		return fmt.Errorf("writing page: %v", err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/cardhash"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+const usage = `usage: recognize [flags] <photo directory>
+
+Identifies the printings of cards in photos or scans, by the perceptual
+hashes of their art, and writes them as a collection csv. Every photo must
+show a single upright card which fills the picture.
+
+The hashes are computed from the art of the printings of the local card
+database, run with -build once first. Building the index downloads the art
+of every printing, -sets limits it to some sets.
+
+flags:
+`
+
+func main() {
+	var opts options
+
+	sets := flag.String("sets", "", "the set codes to build the hash index of, separated by commas")
+	flag.BoolVar(&opts.build, "build", false, "build the hash index before recognizing the photos")
+	flag.IntVar(&opts.maxDistance, "distance", cardhash.MaxDistance, "the largest hash distance of a match, out of 128 bits")
+	flag.StringVar(&opts.out, "out", "collection.csv", "the collection csv to write")
+	flag.StringVar(&opts.config, "config", "~/.config/mtg", "the config directory")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts.dir = flag.Arg(0)
+	if opts.dir == "" && !opts.build {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *sets != "" {
+		opts.sets = strings.Split(*sets, ",")
+	}
+
+	if err := run(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run recognizes the photos with the scryfall service, and waits for the
+// collection to be written
+func run(opts options) error {
+	done := make(chan error, 1)
+
+	rt := runtime.New("Recognize")
+
+	// added first, so that it sees the scryfall service being initialized
+	rt.Add(&recognizer{options: opts, done: done})
+	rt.Add(&configFile.Service{RootDirectory: opts.config})
+	rt.Add(&scryfall.Service{})
+
+	err := <-done
+
+	rt.Shutdown().Wait()
+
+	return err
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/cardhash"
+	"github.com/gravestench/mtg/pkg/collection"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+type options struct {
+	dir         string
+	sets        []string
+	build       bool
+	maxDistance int
+	out         string
+	config      string
+}
+
+// recognizer is a service which recognizes the photos as soon as the
+// scryfall service is initialized
+type recognizer struct {
+	logger  *zerolog.Logger
+	options options
+	done    chan<- error
+}
+
+func (s *recognizer) Name() string {
+	return "Recognize"
+}
+
+func (s *recognizer) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *recognizer) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+func (s *recognizer) Init(r runtime.R) {}
+
+// OnServiceInitialized recognizes the photos once the scryfall service can
+// be used, resolving it as a dependency would not wait for its
+// initialization
+func (s *recognizer) OnServiceInitialized(args ...any) {
+	if len(args) < 1 {
+		return
+	}
+
+	if candidate, ok := args[0].(scryfall.Dependency); ok {
+		go func() { s.done <- s.recognize(candidate) }()
+	}
+}
+
+func (s *recognizer) recognize(client scryfall.Dependency) error {
+	var (
+		idx *cardhash.Index
+		err error
+	)
+
+	if s.options.build {
+		idx, err = client.BuildHashIndex(context.Background(), s.options.sets...)
+	} else {
+		idx, err = client.HashIndex()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if idx.Len() == 0 {
+		return fmt.Errorf("the hash index is empty, build it with -build")
+	}
+
+	if s.options.dir == "" {
+		s.logger.Info().Msgf("hashed %d printings", idx.Len())
+		return nil
+	}
+
+	photos, err := photoFiles(s.options.dir)
+	if err != nil {
+		return err
+	}
+
+	recognized := &collection.Collection{}
+
+	for _, path := range photos {
+		match, errMatch := s.match(idx, path)
+		if errMatch != nil {
+			s.logger.Warn().Msgf("%s: %v", filepath.Base(path), errMatch)
+			continue
+		}
+
+		s.logger.Info().Msgf("%s: %s (%s %s), distance %d",
+			filepath.Base(path), match.Name, strings.ToUpper(match.Set), match.CollectorNumber, match.Distance)
+
+		recognized.Add(collection.Item{
+			Name:            match.Name,
+			Set:             match.Set,
+			CollectorNumber: match.CollectorNumber,
+			Quantity:        1,
+		})
+	}
+
+	f, err := os.Create(s.options.out)
+	if err != nil {
+		return fmt.Errorf("creating collection csv: %v", err)
+	}
+
+	defer f.Close()
+
+	items := recognized.Items()
+
+	if err = collection.ExportCSV(f, items); err != nil {
+		return err
+	}
+
+	s.logger.Info().Msgf("recognized %d of %d photos, wrote %s", countCards(items), len(photos), s.options.out)
+
+	return f.Close()
+}
+
+// match yields the printing nearest to the art of a photo
+func (s *recognizer) match(idx *cardhash.Index, path string) (cardhash.Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cardhash.Match{}, err
+	}
+
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return cardhash.Match{}, fmt.Errorf("decoding photo: %v", err)
+	}
+
+	matches := idx.Nearest(cardhash.Compute(cardhash.Art(img)), 1, s.options.maxDistance)
+	if len(matches) < 1 {
+		return cardhash.Match{}, fmt.Errorf("no card recognized")
+	}
+
+	return matches[0], nil
+}
+
+// photoFiles yields the jpeg and png files of a directory, by name
+func photoFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading photo directory: %v", err)
+	}
+
+	photos := make([]string, 0)
+
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".jpg", ".jpeg", ".png":
+			photos = append(photos, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	sort.Strings(photos)
+
+	return photos, nil
+}
+
+func countCards(items []collection.Item) (count int) {
+	for _, item := range items {
+		count += item.Quantity
+	}
+
+	return count
+}
@@ -12,6 +12,10 @@ const (
 	FrameRight  = 656. / 743.
 	FrameBottom = 567. / 1044.
 	FrameTop    = 100. / 1044.
+
+	SetSymbolRight   = 646. / 743.
+	SetSymbolCenterY = 596. / 1044.
+	SetSymbolHeight  = 34. / 1044.
 )
 
 var (
@@ -0,0 +1,153 @@
+package booster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// Kind is a kind of booster pack
+type Kind string
+
+const (
+	// KindDraft is the classic 15 card draft booster: ten commons, three
+	// uncommons, a rare or mythic and a basic land, a foil of any rarity
+	// replaces a common in a third of the packs
+	KindDraft Kind = "draft"
+
+	// KindPlay is the 14 card play booster: six commons, a common or a
+	// special guest, three uncommons, a rare or mythic, a wildcard of any
+	// rarity, a foil of any rarity and a land
+	KindPlay Kind = "play"
+)
+
+// ParseKind yields the kind of booster with the given name
+func ParseKind(name string) (Kind, error) {
+	switch k := Kind(name); k {
+	case KindDraft, KindPlay:
+		return k, nil
+	}
+
+	return "", fmt.Errorf("unknown booster kind %q, expected %q or %q", name, KindDraft, KindPlay)
+}
+
+// playBoostersSince is the release of Murders at Karlov Manor, the first set
+// opened in play boosters instead of draft boosters
+var playBoostersSince = time.Date(2024, time.February, 9, 0, 0, 0, 0, time.UTC)
+
+// DefaultKind yields the kind of booster a set released at a date is opened
+// in, play boosters for the sets since Murders at Karlov Manor and draft
+// boosters for the sets before. Sets without a release date are new.
+func DefaultKind(released time.Time) Kind {
+	if !released.IsZero() && released.Before(playBoostersSince) {
+		return KindDraft
+	}
+
+	return KindPlay
+}
+
+// rarities, as named by scryfall
+const (
+	RarityCommon   = "common"
+	RarityUncommon = "uncommon"
+	RarityRare     = "rare"
+	RarityMythic   = "mythic"
+	RaritySpecial  = "special"
+	RarityBonus    = "bonus"
+)
+
+// rarityRank orders rarities for bots, higher is better
+var rarityRank = map[string]int{
+	RarityCommon:   0,
+	RarityUncommon: 1,
+	RarityRare:     2,
+	RaritySpecial:  2,
+	RarityBonus:    2,
+	RarityMythic:   3,
+}
+
+// Weight is the relative chance of a rarity in a slot
+type Weight struct {
+	Rarity string
+	Weight float64
+}
+
+// Slot is a part of a pack
+type Slot struct {
+	Name  string
+	Count int
+
+	// Rarities are the chances of each rarity, rarities without cards in
+	// the pool are left out
+	Rarities []Weight
+
+	// Lands slots hold basic lands, and common nonbasic lands if
+	// CommonLands is set. They ignore Rarities.
+	Lands       bool
+	CommonLands bool
+
+	// Foil is the chance of the cards of the slot being foil
+	Foil float64
+
+	// Alternative replaces the slot in a pack, with a chance of
+	// AlternativeChance
+	Alternative       *Slot
+	AlternativeChance float64
+}
+
+// the mythic rare chance of a rare slot, a mythic in about every eighth pack
+const mythicWeight = 1.0 / 8
+
+var rareSlot = []Weight{{RarityRare, 1 - mythicWeight}, {RarityMythic, mythicWeight}}
+
+// Layouts are the slots of every kind of pack, in the order the cards are in
+// the pack
+var Layouts = map[Kind][]Slot{
+	KindDraft: {
+		{Name: "common", Count: 9, Rarities: []Weight{{RarityCommon, 1}}},
+		{Name: "common", Count: 1, Rarities: []Weight{{RarityCommon, 1}}, AlternativeChance: 1.0 / 3,
+			Alternative: &Slot{Name: "foil", Count: 1, Foil: 1, Rarities: []Weight{
+				{RarityCommon, 0.70}, {RarityUncommon, 0.20}, {RarityRare, 0.085}, {RarityMythic, 0.015},
+			}},
+		},
+		{Name: "uncommon", Count: 3, Rarities: []Weight{{RarityUncommon, 1}}},
+		{Name: "rare", Count: 1, Rarities: rareSlot},
+		{Name: "land", Count: 1, Lands: true},
+	},
+	KindPlay: {
+		{Name: "common", Count: 6, Rarities: []Weight{{RarityCommon, 1}}},
+		{Name: "common or special guest", Count: 1, Rarities: []Weight{
+			{RarityCommon, 0.875}, {RaritySpecial, 0.125 / 2}, {RarityBonus, 0.125 / 2},
+		}},
+		{Name: "uncommon", Count: 3, Rarities: []Weight{{RarityUncommon, 1}}},
+		{Name: "rare", Count: 1, Rarities: rareSlot},
+		{Name: "wildcard", Count: 1, Rarities: []Weight{
+			{RarityCommon, 0.18}, {RarityUncommon, 0.58}, {RarityRare, 0.20}, {RarityMythic, 0.04},
+		}},
+		{Name: "foil", Count: 1, Foil: 1, Rarities: []Weight{
+			{RarityCommon, 0.667}, {RarityUncommon, 0.25}, {RarityRare, 0.067}, {RarityMythic, 0.016},
+		}},
+		{Name: "land", Count: 1, Lands: true, CommonLands: true, Foil: 0.2},
+	},
+}
+
+// Card is a card in a pack
+type Card struct {
+	scryfall.Card
+	Foil bool   `json:"foil"`
+	Slot string `json:"slot"`
+}
+
+// Pack is a booster pack
+type Pack []Card
+
+// Names yields the names of the cards of a pack
+func (p Pack) Names() []string {
+	names := make([]string, len(p))
+	for idx := range p {
+		names[idx] = p[idx].Name
+	}
+
+	return names
+}
@@ -0,0 +1,187 @@
+package booster
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+func testPool() *Pool {
+	var cards []scryfall.Card
+
+	add := func(count int, rarity, typeLine string) {
+		for n := 0; n < count; n++ {
+			name := fmt.Sprintf("%s %s %d", rarity, typeLine, n)
+			cards = append(cards, scryfall.Card{
+				ID: name, Name: name, Rarity: rarity, TypeLine: typeLine, Booster: true,
+			})
+		}
+	}
+
+	add(40, RarityCommon, "Creature")
+	add(2, RarityCommon, "Land")
+	add(20, RarityUncommon, "Instant")
+	add(10, RarityRare, "Sorcery")
+	add(4, RarityMythic, "Planeswalker")
+	add(5, RarityCommon, "Basic Land")
+
+	// not in boosters
+	cards = append(cards, scryfall.Card{ID: "promo", Name: "promo", Rarity: RarityMythic})
+
+	return NewPool(cards)
+}
+
+func TestPacks(t *testing.T) {
+	pool := testPool()
+
+	if size := pool.Size(); size[RarityCommon] != 42 || size[RarityMythic] != 4 {
+		t.Fatalf("unexpected pool size %v", size)
+	}
+
+	for kind, expectedSize := range map[Kind]int{KindDraft: 15, KindPlay: 14} {
+		g, err := New(pool, kind, 1)
+		if err != nil {
+			t.Fatalf("%s: creating generator: %v", kind, err)
+		}
+
+		foils := 0
+
+		for _, pack := range g.Packs(300) {
+			if len(pack) != expectedSize {
+				t.Fatalf("%s: expected %d cards, got %d", kind, expectedSize, len(pack))
+			}
+
+			rares, seen := 0, make(map[string]bool)
+
+			for _, card := range pack {
+				if card.Name == "promo" {
+					t.Fatalf("%s: opened a card which is not in boosters", kind)
+				}
+
+				if card.Slot == "rare" {
+					rares++
+				}
+
+				if card.Foil {
+					foils++
+				}
+
+				if !card.Foil && card.Slot != "land" {
+					if seen[card.Name] {
+						t.Fatalf("%s: %q repeats in a pack", kind, card.Name)
+					}
+
+					seen[card.Name] = true
+				}
+			}
+
+			if rares != 1 {
+				t.Fatalf("%s: expected a single rare slot, got %d", kind, rares)
+			}
+		}
+
+		// a foil in a third of the draft boosters, in every play booster
+		if kind == KindDraft && (foils < 70 || foils > 130) {
+			t.Fatalf("%s: unexpected number of foils %d in 300 packs", kind, foils)
+		}
+
+		if kind == KindPlay && foils < 300 {
+			t.Fatalf("%s: expected a foil in every pack, got %d foils", kind, foils)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	open := func(seed int64) []Pack {
+		g, err := New(testPool(), KindPlay, seed)
+		if err != nil {
+			t.Fatalf("creating generator: %v", err)
+		}
+
+		return g.Sealed()
+	}
+
+	a, b, c := open(7), open(7), open(8)
+
+	if len(a) != SealedPacks {
+		t.Fatalf("expected %d sealed packs, got %d", SealedPacks, len(a))
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("expected the same seed to open the same packs")
+	}
+
+	if reflect.DeepEqual(a, c) {
+		t.Fatalf("expected another seed to open other packs")
+	}
+}
+
+func TestDefaultKind(t *testing.T) {
+	tests := map[string]Kind{
+		"2009-07-17": KindDraft,
+		"2024-02-08": KindDraft,
+		"2024-02-09": KindPlay,
+		"2025-06-13": KindPlay,
+	}
+
+	for released, expected := range tests {
+		date, _ := time.Parse(time.DateOnly, released)
+
+		if kind := DefaultKind(date); kind != expected {
+			t.Errorf("expected a set released %s to open %s boosters, got %s", released, expected, kind)
+		}
+	}
+
+	if kind := DefaultKind(time.Time{}); kind != KindPlay {
+		t.Errorf("expected an unreleased set to open play boosters, got %s", kind)
+	}
+}
+
+func TestDraft(t *testing.T) {
+	g, _ := New(testPool(), KindDraft, 42)
+	opened, _ := New(testPool(), KindDraft, 42)
+	firstPacks := opened.Packs(DraftPlayers)
+
+	var secondPick Pack
+
+	// the player in the second seat sees what is left of the first pack
+	// of the first seat after its first pick
+	player := PickerFunc(func(seat int, pack Pack, picked Pack) int {
+		if len(picked) == 1 {
+			secondPick = append(Pack(nil), pack...)
+		}
+
+		return 0
+	})
+
+	result, err := NewDraft(g, nil, player).Run()
+	if err != nil {
+		t.Fatalf("drafting: %v", err)
+	}
+
+	for seat, pool := range result.Pools {
+		if len(pool) != DraftRounds*15 {
+			t.Fatalf("seat %d: expected %d cards, got %d", seat, DraftRounds*15, len(pool))
+		}
+	}
+
+	if len(result.Picks) != DraftPlayers*DraftRounds*15 {
+		t.Fatalf("expected every card to be picked, got %d picks", len(result.Picks))
+	}
+
+	firstPick := result.Pools[0][0]
+	expected := make(Pack, 0)
+
+	for _, card := range firstPacks[0] {
+		if card.Name != firstPick.Name || card.Foil != firstPick.Foil {
+			expected = append(expected, card)
+		}
+	}
+
+	if !reflect.DeepEqual(secondPick.Names(), expected.Names()) {
+		t.Fatalf("expected the pack passed from the first seat\n%v\ngot\n%v", expected.Names(), secondPick.Names())
+	}
+}
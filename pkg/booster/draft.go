@@ -0,0 +1,151 @@
+package booster
+
+import (
+	"fmt"
+)
+
+// the size of a draft pod, and the number of packs every player opens
+const (
+	DraftPlayers = 8
+	DraftRounds  = 3
+)
+
+// Picker picks cards during a draft, it is either a player or a bot
+type Picker interface {
+	// Pick yields the index of the card to pick from a pack, picked are
+	// the cards picked by the seat so far
+	Pick(seat int, pack Pack, picked Pack) int
+}
+
+// PickerFunc is a function which picks cards
+type PickerFunc func(seat int, pack Pack, picked Pack) int
+
+func (f PickerFunc) Pick(seat int, pack Pack, picked Pack) int {
+	return f(seat, pack, picked)
+}
+
+// Bot picks the card of the highest rarity, and prefers the colors of its
+// earlier picks between cards of the same rarity
+var Bot Picker = PickerFunc(botPick)
+
+func botPick(_ int, pack Pack, picked Pack) int {
+	colors := make(map[string]int)
+
+	for _, card := range picked {
+		for _, color := range card.Colors {
+			colors[string(color)]++
+		}
+	}
+
+	best, bestScore := 0, -1
+
+	for idx, card := range pack {
+		score := rarityRank[card.Rarity] * 1000
+
+		for _, color := range card.Colors {
+			score += colors[string(color)]
+		}
+
+		if score > bestScore {
+			best, bestScore = idx, score
+		}
+	}
+
+	return best
+}
+
+// Pick is a card picked during a draft
+type Pick struct {
+	Round int  `json:"round"`
+	Pick  int  `json:"pick"`
+	Seat  int  `json:"seat"`
+	Card  Card `json:"card"`
+}
+
+// DraftResult is the outcome of a draft
+type DraftResult struct {
+	// Pools are the picked cards of every seat
+	Pools []Pack `json:"pools"`
+
+	// Picks are every pick, in order
+	Picks []Pick `json:"picks"`
+}
+
+// Draft is a booster draft. Every seat opens a pack each round, picks a
+// card and passes the rest of the pack, to the left in the first and last
+// round and to the right in the second.
+type Draft struct {
+	generator *Generator
+	seats     []Picker
+	rounds    int
+}
+
+// NewDraft yields a draft of a full pod, seats which are not taken by
+// pickers are taken by bots
+func NewDraft(g *Generator, pickers ...Picker) *Draft {
+	seats := make([]Picker, max(DraftPlayers, len(pickers)))
+
+	for idx := range seats {
+		seats[idx] = Bot
+
+		if idx < len(pickers) && pickers[idx] != nil {
+			seats[idx] = pickers[idx]
+		}
+	}
+
+	return &Draft{generator: g, seats: seats, rounds: DraftRounds}
+}
+
+// Run drafts every round
+func (d *Draft) Run() (*DraftResult, error) {
+	players := len(d.seats)
+
+	result := &DraftResult{
+		Pools: make([]Pack, players),
+		Picks: make([]Pick, 0),
+	}
+
+	for round := 0; round < d.rounds; round++ {
+		packs := d.generator.Packs(players)
+
+		// passing to the left is passing to the next seat
+		direction := 1
+		if round%2 == 1 {
+			direction = players - 1
+		}
+
+		for pick := 0; ; pick++ {
+			picked := false
+
+			for seat, pack := range packs {
+				if len(pack) == 0 {
+					continue
+				}
+
+				idx := d.seats[seat].Pick(seat, pack, result.Pools[seat])
+				if idx < 0 || idx >= len(pack) {
+					return nil, fmt.Errorf("seat %d picked card %d of a pack of %d cards", seat, idx, len(pack))
+				}
+
+				card := pack[idx]
+				packs[seat] = append(append(Pack(nil), pack[:idx]...), pack[idx+1:]...)
+				result.Pools[seat] = append(result.Pools[seat], card)
+				result.Picks = append(result.Picks, Pick{Round: round, Pick: pick, Seat: seat, Card: card})
+				picked = true
+			}
+
+			if !picked {
+				break
+			}
+
+			passed := make([]Pack, players)
+			for seat := range packs {
+				passed[(seat+direction)%players] = packs[seat]
+			}
+
+			packs = passed
+		}
+	}
+
+	return result, nil
+}
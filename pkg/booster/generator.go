@@ -0,0 +1,160 @@
+package booster
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// SealedPacks is the number of packs of a sealed pool
+const SealedPacks = 6
+
+// Generator opens packs of a set. Generators with the same pool, kind and
+// seed open the same packs, in the same order.
+type Generator struct {
+	pool   *Pool
+	kind   Kind
+	layout []Slot
+	rng    *rand.Rand
+}
+
+// New yields a pack generator
+func New(pool *Pool, kind Kind, seed int64) (*Generator, error) {
+	layout, found := Layouts[kind]
+	if !found {
+		return nil, fmt.Errorf("unknown booster kind %q", kind)
+	}
+
+	if len(pool.byRarity[RarityCommon]) == 0 {
+		return nil, fmt.Errorf("can not open %s boosters without commons", kind)
+	}
+
+	return &Generator{
+		pool:   pool,
+		kind:   kind,
+		layout: layout,
+		rng:    rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Kind yields the kind of packs the generator opens
+func (g *Generator) Kind() Kind {
+	return g.kind
+}
+
+// Pack opens a pack. Cards do not repeat within a pack as long as the pool
+// has enough cards, except for foils and lands. Slots without any cards in the pool are
+// left out, like the land slot of a set without basic lands.
+func (g *Generator) Pack() Pack {
+	pack := make(Pack, 0, 15)
+	opened := make(map[string]bool)
+
+	for _, slot := range g.layout {
+		if slot.Alternative != nil && g.rng.Float64() < slot.AlternativeChance {
+			slot = *slot.Alternative
+		}
+
+		for n := 0; n < slot.Count; n++ {
+			card, found := g.pick(slot, opened)
+			if !found {
+				continue
+			}
+
+			opened[card.ID+card.Name] = true
+
+			pack = append(pack, Card{
+				Card: card,
+				Foil: slot.Foil > 0 && g.rng.Float64() < slot.Foil,
+				Slot: slot.Name,
+			})
+		}
+	}
+
+	return pack
+}
+
+// Packs opens a number of packs
+func (g *Generator) Packs(count int) []Pack {
+	packs := make([]Pack, count)
+
+	for idx := range packs {
+		packs[idx] = g.Pack()
+	}
+
+	return packs
+}
+
+// Sealed opens the packs of a sealed pool
+func (g *Generator) Sealed() []Pack {
+	return g.Packs(SealedPacks)
+}
+
+// pick picks a card for a slot which is not in the pack yet, if possible
+func (g *Generator) pick(slot Slot, opened map[string]bool) (scryfall.Card, bool) {
+	rarity, found := g.rarity(slot)
+	if !found {
+		return scryfall.Card{}, false
+	}
+
+	candidates := g.pool.candidates(slot, rarity)
+	if len(candidates) == 0 {
+		return scryfall.Card{}, false
+	}
+
+	// the foil and land slots may repeat the other slots, like real packs
+	if slot.Foil > 0 || slot.Lands {
+		return candidates[g.rng.Intn(len(candidates))], true
+	}
+
+	for _, idx := range g.rng.Perm(len(candidates)) {
+		if !opened[candidates[idx].ID+candidates[idx].Name] {
+			return candidates[idx], true
+		}
+	}
+
+	return candidates[g.rng.Intn(len(candidates))], true
+}
+
+// rarity picks the rarity of a card of a slot by weight, rarities without
+// cards in the pool are left out
+func (g *Generator) rarity(slot Slot) (string, bool) {
+	if slot.Lands {
+		return "", true
+	}
+
+	total := 0.0
+
+	for _, w := range slot.Rarities {
+		if len(g.pool.byRarity[w.Rarity]) > 0 {
+			total += w.Weight
+		}
+	}
+
+	if total == 0 {
+		return "", false
+	}
+
+	roll := g.rng.Float64() * total
+
+	for _, w := range slot.Rarities {
+		if len(g.pool.byRarity[w.Rarity]) == 0 {
+			continue
+		}
+
+		if roll < w.Weight {
+			return w.Rarity, true
+		}
+
+		roll -= w.Weight
+	}
+
+	// rounding errors land on the last rarity of the pool
+	for idx := len(slot.Rarities) - 1; idx >= 0; idx-- {
+		if len(g.pool.byRarity[slot.Rarities[idx].Rarity]) > 0 {
+			return slot.Rarities[idx].Rarity, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,77 @@
+package booster
+
+import (
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// Pool is the cards of a set which packs are made of
+type Pool struct {
+	byRarity    map[string][]scryfall.Card
+	basicLands  []scryfall.Card
+	commonLands []scryfall.Card
+}
+
+// NewPool sorts the cards of a set by rarity. When any card is marked as
+// found in boosters, the cards which are not are left out, like promos and
+// the cards of collector boosters.
+func NewPool(cards []scryfall.Card) *Pool {
+	boosterOnly := false
+
+	for _, card := range cards {
+		if card.Booster {
+			boosterOnly = true
+			break
+		}
+	}
+
+	p := &Pool{byRarity: make(map[string][]scryfall.Card)}
+
+	for _, card := range cards {
+		if boosterOnly && !card.Booster {
+			continue
+		}
+
+		isLand := strings.Contains(card.TypeLine, "Land")
+
+		switch {
+		case strings.Contains(card.TypeLine, "Basic Land"):
+			p.basicLands = append(p.basicLands, card)
+
+			continue
+		case isLand && card.Rarity == RarityCommon:
+			p.commonLands = append(p.commonLands, card)
+		}
+
+		p.byRarity[card.Rarity] = append(p.byRarity[card.Rarity], card)
+	}
+
+	return p
+}
+
+// Size yields the number of cards of every rarity, basic lands excluded
+func (p *Pool) Size() map[string]int {
+	size := make(map[string]int)
+
+	for rarity, cards := range p.byRarity {
+		size[rarity] = len(cards)
+	}
+
+	return size
+}
+
+// candidates yields the cards a slot can hold
+func (p *Pool) candidates(slot Slot, rarity string) []scryfall.Card {
+	if !slot.Lands {
+		return p.byRarity[rarity]
+	}
+
+	lands := p.basicLands
+
+	if slot.CommonLands {
+		lands = append(append([]scryfall.Card(nil), lands...), p.commonLands...)
+	}
+
+	return lands
+}
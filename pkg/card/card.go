@@ -34,6 +34,9 @@ func New(name string, cost map[models.Mana]int, power, toughness int, effects mo
 type Card struct {
 	Name string
 	ManaCost
+
+	// Set is the code of the set of the card, like "M10"
+	Set         string
 	IsPermanent bool
 
 	State CardState
@@ -49,8 +52,9 @@ type Card struct {
 	subTypes  []string
 
 	Graphics struct {
-		Template image.Image
-		Artwork  image.Image
+		Template  image.Image
+		Artwork   image.Image
+		SetSymbol image.Image
 	}
 }
 
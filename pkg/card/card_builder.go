@@ -1,9 +1,16 @@
 package card
 
 import (
+	"github.com/gravestench/mtg/pkg/catalog"
 	"github.com/gravestench/mtg/pkg/models"
 )
 
+// SubTypeValidator rejects unknown subtypes, like the catalog of the mtgapi
+// service or catalog.Snapshot()
+type SubTypeValidator interface {
+	ValidateSubtypes(subtypes ...string) error
+}
+
 func Builder() *CardBuilder {
 	return &CardBuilder{
 		name:        "Name",
@@ -19,6 +26,7 @@ func Builder() *CardBuilder {
 
 type CardBuilder struct {
 	name        string
+	set         string
 	manaCost    ManaCost
 	isPermanent bool
 	effects     models.EffectFlag
@@ -32,6 +40,7 @@ type CardBuilder struct {
 func (c *CardBuilder) Build() *Card {
 	return &Card{
 		Name:        c.name,
+		Set:         c.set,
 		ManaCost:    c.manaCost,
 		IsPermanent: c.isPermanent,
 		State: CardState{
@@ -49,12 +58,28 @@ func (c *CardBuilder) Build() *Card {
 	}
 }
 
+// BuildValidated builds the card if every subtype is known to a validator
+func (c *CardBuilder) BuildValidated(v SubTypeValidator) (*Card, error) {
+	if err := v.ValidateSubtypes(c.subTypes...); err != nil {
+		return nil, err
+	}
+
+	return c.Build(), nil
+}
+
 func (c *CardBuilder) Name(s string) *CardBuilder {
 	c.name = s
 
 	return c
 }
 
+// Set sets the code of the set of the card, which has the set symbol
+func (c *CardBuilder) Set(code string) *CardBuilder {
+	c.set = code
+
+	return c
+}
+
 func (c *CardBuilder) ManaCost(m map[models.Mana]int) *CardBuilder {
 	if m != nil {
 		c.manaCost = m
@@ -105,3 +130,17 @@ func (c *CardBuilder) SubTypes(s []string) *CardBuilder {
 
 	return c
 }
+
+// TypeLine sets the type and subtypes of a parsed type line, see
+// catalog.Catalog.ParseTypeLine. Types the game does not model keep the
+// type which was set before.
+func (c *CardBuilder) TypeLine(t catalog.TypeLine) *CardBuilder {
+	if superType, found := t.SuperType(); found {
+		c.superType = superType
+	}
+
+	c.isPermanent = t.IsPermanent()
+	c.subTypes = append([]string(nil), t.Subtypes...)
+
+	return c
+}
@@ -52,6 +52,42 @@ func (c *Card) SetArtwork(img image.Image) {
 	c.Graphics.Artwork = img
 }
 
+// SetSymbol yields the symbol of the set of the card, which is nil for cards
+// without a set
+func (c *Card) SetSymbol() image.Image {
+	return c.Graphics.SetSymbol
+}
+
+// SetSetSymbol sets the symbol of the set of the card, which is drawn at the
+// right of the type line of the composite image
+func (c *Card) SetSetSymbol(img image.Image) {
+	c.Graphics.SetSymbol = img
+}
+
+// SetIconProvider draws the icons of the sets, like the set info service
+type SetIconProvider interface {
+	SetIcon(code string, height int) (image.Image, error)
+}
+
+// LoadSetSymbol sets the symbol of the set of the card to its icon, drawn at
+// the height of the type line of the template. Cards without a set have no
+// symbol.
+func (c *Card) LoadSetSymbol(icons SetIconProvider) error {
+	if c.Set == "" {
+		c.SetSetSymbol(nil)
+		return nil
+	}
+
+	icon, err := icons.SetIcon(c.Set, setSymbolHeight(c.Template().Bounds()))
+	if err != nil {
+		return err
+	}
+
+	c.SetSetSymbol(icon)
+
+	return nil
+}
+
 func (c *Card) CompositeCardImage() image.Image {
 	artwork, template := c.Artwork(), c.Template()
 	// Create a new RGBA image with the same size as your input images
@@ -88,5 +124,49 @@ func (c *Card) CompositeCardImage() image.Image {
 	// Composite the second image onto the result image, taking into account transparency
 	draw.Draw(result, templateBounds, template, image.Point{}, draw.Over)
 
+	if symbol := c.SetSymbol(); symbol != nil {
+		drawSetSymbol(result, symbol)
+	}
+
 	return result
 }
+
+// drawSetSymbol draws a set symbol at the right of the type line, scaled to
+// the height of the type line
+func drawSetSymbol(dst *image.RGBA, symbol image.Image) {
+	bounds, symbolBounds := dst.Bounds(), symbol.Bounds()
+	if symbolBounds.Empty() {
+		return
+	}
+
+	height := setSymbolHeight(bounds)
+	width := symbolBounds.Dx() * height / symbolBounds.Dy()
+
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	right := int(float64(bounds.Dx()) * card_templates.SetSymbolRight)
+	top := int(float64(bounds.Dy())*card_templates.SetSymbolCenterY) - height/2
+
+	scaled := image.NewRGBA(image.Rect(right-width, top, right, top+height))
+
+	// nearest neighbour, set symbols are rasterized close to the size they
+	// are drawn at
+	for y := scaled.Rect.Min.Y; y < scaled.Rect.Max.Y; y++ {
+		for x := scaled.Rect.Min.X; x < scaled.Rect.Max.X; x++ {
+			scaled.Set(x, y, symbol.At(
+				symbolBounds.Min.X+(x-scaled.Rect.Min.X)*symbolBounds.Dx()/width,
+				symbolBounds.Min.Y+(y-scaled.Rect.Min.Y)*symbolBounds.Dy()/height,
+			))
+		}
+	}
+
+	draw.Draw(dst, scaled.Rect, scaled, scaled.Rect.Min, draw.Over)
+}
+
+// setSymbolHeight is the height of the set symbol on a card, which is the
+// height of the text of the type line
+func setSymbolHeight(bounds image.Rectangle) int {
+	return int(float64(bounds.Dy()) * card_templates.SetSymbolHeight)
+}
@@ -0,0 +1,81 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/gravestench/mtg/data/card_templates"
+)
+
+// icons draws every set icon as a black square, and remembers what it drew
+type icons struct {
+	code   string
+	height int
+}
+
+func (i *icons) SetIcon(code string, height int) (image.Image, error) {
+	i.code, i.height = code, height
+
+	icon := image.NewRGBA(image.Rect(0, 0, height, height))
+	draw.Draw(icon, icon.Rect, image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	return icon, nil
+}
+
+func TestSetSymbol(t *testing.T) {
+	// a white template the size of the real ones, and no artwork
+	template := image.NewRGBA(image.Rect(0, 0, 743, 1044))
+	draw.Draw(template, template.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	c := Builder().Name("Lightning Bolt").Set("M10").Build()
+	c.SetTemplate(template)
+	c.SetArtwork(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+
+	provider := &icons{}
+
+	if err := c.LoadSetSymbol(provider); err != nil {
+		t.Fatal(err)
+	}
+
+	height := int(1044 * card_templates.SetSymbolHeight)
+	if provider.code != "M10" || provider.height != height {
+		t.Fatalf("expected the icon of M10 %d pixels high, got %s %d pixels high", height, provider.code, provider.height)
+	}
+
+	img := c.CompositeCardImage()
+
+	// the box of the symbol, at the right of the type line
+	right := int(743 * card_templates.SetSymbolRight)
+	top := int(1044*card_templates.SetSymbolCenterY) - height/2
+	box := image.Rect(right-height, top, right, top+height)
+
+	tests := []struct {
+		x, y   int
+		symbol bool
+	}{
+		{box.Min.X, box.Min.Y, true},
+		{box.Max.X - 1, box.Max.Y - 1, true},
+		{(box.Min.X + box.Max.X) / 2, int(1044 * card_templates.SetSymbolCenterY), true},
+		{box.Min.X - 1, box.Min.Y, false},
+		{box.Max.X, box.Min.Y, false},
+		{box.Min.X, box.Min.Y - 1, false},
+		{box.Max.X - 1, box.Max.Y, false},
+	}
+
+	for _, test := range tests {
+		r, _, _, _ := img.At(test.x, test.y).RGBA()
+		if symbol := r == 0; symbol != test.symbol {
+			t.Errorf("expected %d,%d to be in the symbol: %v", test.x, test.y, test.symbol)
+		}
+	}
+
+	// cards without a set have no symbol
+	c = Builder().Build()
+	c.SetSetSymbol(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+
+	if err := c.LoadSetSymbol(provider); err != nil || c.SetSymbol() != nil {
+		t.Fatalf("expected no symbol without a set, got %v (%v)", c.SetSymbol(), err)
+	}
+}
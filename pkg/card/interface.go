@@ -51,5 +51,8 @@ type hasGraphics interface {
 	Artwork() image.Image
 	SetArtwork(image.Image)
 
+	SetSymbol() image.Image
+	SetSetSymbol(image.Image)
+
 	CompositeCardImage() image.Image
 }
@@ -0,0 +1,380 @@
+package carddb
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// the files of a card database directory
+const (
+	cardsFileName = "cards.jsonl"
+	indexFileName = "index.gob"
+)
+
+// ErrNotFound is yielded when no card matches a lookup
+var ErrNotFound = errors.New("card not found")
+
+// DB is a card database imported from scryfall bulk data. The cards are
+// stored as json lines on disk and read when they are looked up, only the
+// indices are kept in memory.
+//
+// Every card can be looked up by scryfall ID. The other indices only hold
+// english cards, the printings of other languages share their names, set
+// codes and collector numbers.
+type DB struct {
+	dir   string
+	mux   sync.Mutex
+	file  *os.File
+	index *index
+}
+
+// index maps keys to the positions of cards in the cards file. Lists of
+// cards are sorted by release, newest first. Names holds the full name of
+// every card, by name key. ByLanguage holds the printings of the other
+// languages, by printing and language.
+type index struct {
+	Spans      []span
+	ByID       map[string]int
+	ByName     map[string][]int
+	Names      map[string]string
+	ByOracleID map[string][]int
+	ByPrinting map[string]int
+	ByLanguage map[string]int
+	BySet      map[string][]int
+}
+
+// span is where a card is in the cards file
+type span struct {
+	Offset int64
+	Length int32
+}
+
+func newIndex() *index {
+	return &index{
+		ByID:       make(map[string]int),
+		ByName:     make(map[string][]int),
+		Names:      make(map[string]string),
+		ByOracleID: make(map[string][]int),
+		ByPrinting: make(map[string]int),
+		ByLanguage: make(map[string]int),
+		BySet:      make(map[string][]int),
+	}
+}
+
+// Exists tells if a directory holds a card database
+func Exists(dir string) bool {
+	_, errCards := os.Stat(filepath.Join(dir, cardsFileName))
+	_, errIndex := os.Stat(filepath.Join(dir, indexFileName))
+
+	return errCards == nil && errIndex == nil
+}
+
+// Open opens the card database in a directory
+func Open(dir string) (*DB, error) {
+	f, err := os.Open(filepath.Join(dir, indexFileName))
+	if err != nil {
+		return nil, fmt.Errorf("opening card index: %v", err)
+	}
+
+	defer f.Close()
+
+	idx := newIndex()
+	if err = gob.NewDecoder(f).Decode(idx); err != nil {
+		return nil, fmt.Errorf("decoding card index: %v", err)
+	}
+
+	cards, err := os.Open(filepath.Join(dir, cardsFileName))
+	if err != nil {
+		return nil, fmt.Errorf("opening cards: %v", err)
+	}
+
+	return &DB{dir: dir, file: cards, index: idx}, nil
+}
+
+// Close closes the cards file
+func (db *DB) Close() error {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+
+	return db.file.Close()
+}
+
+// Len yields the number of cards in the database
+func (db *DB) Len() int {
+	return len(db.index.Spans)
+}
+
+// CardByID yields the card with a scryfall ID
+func (db *DB) CardByID(id string) (*scryfall.Card, error) {
+	pos, found := db.index.ByID[strings.ToLower(id)]
+	if !found {
+		return nil, fmt.Errorf("%w: scryfall id %q", ErrNotFound, id)
+	}
+
+	return db.read(pos)
+}
+
+// CardByName yields the newest printing of the card with a name. Cards with
+// several faces can be named by their full name, like "Delver of Secrets //
+// Insectile Aberration", or by any face.
+func (db *DB) CardByName(name string) (*scryfall.Card, error) {
+	positions := db.index.ByName[nameKey(name)]
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	return db.read(positions[0])
+}
+
+// LegalitiesByName yields the legality of the card with a name by format
+// key, like {"brawl": "legal"}, as in the json of scryfall. Unlike the cards,
+// these hold every format, also those the scryfall client does not know.
+func (db *DB) LegalitiesByName(name string) (map[string]string, error) {
+	positions := db.index.ByName[nameKey(name)]
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	data, err := db.readData(positions[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLegalities(data)
+}
+
+// CardsByName yields every printing of the card with a name, newest first
+func (db *DB) CardsByName(name string) ([]scryfall.Card, error) {
+	return db.readAll(db.index.ByName[nameKey(name)])
+}
+
+// CardByPrinting yields the card with a set code and collector number
+func (db *DB) CardByPrinting(set, collectorNumber string) (*scryfall.Card, error) {
+	pos, found := db.index.ByPrinting[printingKey(set, collectorNumber)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, strings.ToUpper(set), collectorNumber)
+	}
+
+	return db.read(pos)
+}
+
+// CardByPrintingInLanguage yields the card with a set code and collector
+// number in a language, like "de" or "ja". Databases imported before
+// languages were indexed only find english cards.
+func (db *DB) CardByPrintingInLanguage(set, collectorNumber, lang string) (*scryfall.Card, error) {
+	if lang == "" || strings.EqualFold(lang, "en") {
+		return db.CardByPrinting(set, collectorNumber)
+	}
+
+	pos, found := db.index.ByLanguage[languageKey(set, collectorNumber, lang)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s %s (%s)", ErrNotFound, strings.ToUpper(set), collectorNumber, lang)
+	}
+
+	return db.read(pos)
+}
+
+// Printings yields every printing of the card with an oracle ID, newest
+// first
+func (db *DB) Printings(oracleID string) ([]scryfall.Card, error) {
+	return db.readAll(db.index.ByOracleID[strings.ToLower(oracleID)])
+}
+
+// SetCards yields every card of a set, by collector number
+func (db *DB) SetCards(set string) ([]scryfall.Card, error) {
+	return db.readAll(db.index.BySet[strings.ToLower(set)])
+}
+
+// Search yields every printing of the cards named like the query, newest
+// first. Names which equal the query are preferred, then names which
+// contain every word of the query.
+func (db *DB) Search(query string) ([]scryfall.Card, error) {
+	if positions := db.index.ByName[nameKey(query)]; len(positions) > 0 {
+		return db.readAll(positions)
+	}
+
+	words := strings.Fields(nameKey(query))
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0)
+
+	for name := range db.index.ByName {
+		if containsAll(name, words) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	positions := make([]int, 0)
+	seen := make(map[int]bool)
+
+	for _, name := range names {
+		for _, pos := range db.index.ByName[name] {
+			// the faces of a card and its full name lead to the same card
+			if !seen[pos] {
+				seen[pos] = true
+				positions = append(positions, pos)
+			}
+		}
+	}
+
+	return db.readAll(positions)
+}
+
+// Names yields the full name of every card, sorted
+func (db *DB) Names() ([]string, error) {
+	byKey := db.index.Names
+
+	if byKey == nil {
+		// imported before the names were indexed
+		byKey = make(map[string]string)
+
+		for _, positions := range db.index.ByName {
+			card, err := db.read(positions[0])
+			if err != nil {
+				return nil, err
+			}
+
+			byKey[nameKey(card.Name)] = card.Name
+		}
+	}
+
+	names := make([]string, 0, len(byKey))
+
+	for _, name := range byKey {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Filter yields every printing which matches, sorted by name and newest
+// first like Search. Every card is read from disk, so filters are slower
+// than the lookups by index.
+func (db *DB) Filter(match func(card *scryfall.Card) bool) ([]scryfall.Card, error) {
+	names := make([]string, 0, len(db.index.ByName))
+
+	for name := range db.index.ByName {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	cards := make([]scryfall.Card, 0)
+	seen := make(map[int]bool)
+
+	for _, name := range names {
+		for _, pos := range db.index.ByName[name] {
+			if seen[pos] {
+				continue
+			}
+
+			seen[pos] = true
+
+			card, err := db.read(pos)
+			if err != nil {
+				return nil, err
+			}
+
+			if match(card) {
+				cards = append(cards, *card)
+			}
+		}
+	}
+
+	return cards, nil
+}
+
+func (db *DB) read(pos int) (*scryfall.Card, error) {
+	data, err := db.readData(pos)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &scryfall.Card{}
+	if err = json.Unmarshal(data, card); err != nil {
+		return nil, fmt.Errorf("decoding card: %v", err)
+	}
+
+	return card, nil
+}
+
+// readData reads the json of a card
+func (db *DB) readData(pos int) ([]byte, error) {
+	s := db.index.Spans[pos]
+	data := make([]byte, s.Length)
+
+	db.mux.Lock()
+	_, err := db.file.ReadAt(data, s.Offset)
+	db.mux.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("reading card: %v", err)
+	}
+
+	return data, nil
+}
+
+// decodeLegalities decodes the legalities from the json of a card
+func decodeLegalities(data []byte) (map[string]string, error) {
+	var card struct {
+		Legalities map[string]string `json:"legalities"`
+	}
+
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("decoding card: %v", err)
+	}
+
+	return card.Legalities, nil
+}
+
+func (db *DB) readAll(positions []int) ([]scryfall.Card, error) {
+	cards := make([]scryfall.Card, 0, len(positions))
+
+	for _, pos := range positions {
+		card, err := db.read(pos)
+		if err != nil {
+			return nil, err
+		}
+
+		cards = append(cards, *card)
+	}
+
+	return cards, nil
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func printingKey(set, collectorNumber string) string {
+	return strings.ToLower(set) + "|" + strings.ToLower(collectorNumber)
+}
+
+func languageKey(set, collectorNumber, lang string) string {
+	return printingKey(set, collectorNumber) + "|" + strings.ToLower(lang)
+}
+
+func containsAll(s string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(s, word) {
+			return false
+		}
+	}
+
+	return true
+}
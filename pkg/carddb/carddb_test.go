@@ -0,0 +1,143 @@
+package carddb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+func testDB(t *testing.T) *DB {
+	db, err := ImportFile(t.TempDir(), "testdata/default_cards.json")
+	if err != nil {
+		t.Fatalf("importing bulk data: %v", err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func sets(cards []scryfall.Card) []string {
+	result := make([]string, len(cards))
+	for idx := range cards {
+		result[idx] = cards[idx].Set + " " + cards[idx].CollectorNumber
+	}
+
+	return result
+}
+
+func TestLookups(t *testing.T) {
+	db := testDB(t)
+
+	if db.Len() != 7 {
+		t.Fatalf("expected 7 cards, got %d", db.Len())
+	}
+
+	card, err := db.CardByName("lightning bolt")
+	if err != nil || card.Set != "2xm" {
+		t.Fatalf("expected the newest printing of bolt, got %+v (%v)", card, err)
+	}
+
+	if card, err = db.CardByPrinting("M10", "146"); err != nil || card.Lang != "en" {
+		t.Fatalf("expected the english m10 bolt, got %+v (%v)", card, err)
+	}
+
+	if card, err = db.CardByID("4D2A9A4B-1B5A-4D43-8C85-6A6F0C7B1F0E"); err != nil || card.Lang != "de" {
+		t.Fatalf("expected the german bolt by scryfall id, got %+v (%v)", card, err)
+	}
+
+	if card, err = db.CardByPrintingInLanguage("m10", "146", "DE"); err != nil || card.Lang != "de" {
+		t.Fatalf("expected the german m10 bolt, got %+v (%v)", card, err)
+	}
+
+	if _, err = db.CardByPrintingInLanguage("m10", "146", "ja"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected no japanese m10 bolt, got %v", err)
+	}
+
+	printings, err := db.Printings(card.OracleID)
+	if err != nil {
+		t.Fatalf("getting printings: %v", err)
+	}
+
+	if got := sets(printings); !reflect.DeepEqual(got, []string{"2xm 129", "m10 146", "lea 161"}) {
+		t.Fatalf("unexpected printings %v", got)
+	}
+
+	for _, name := range []string{"Delver of Secrets", "Insectile Aberration", "Delver of Secrets // Insectile Aberration"} {
+		if card, err = db.CardByName(name); err != nil || len(card.CardFaces) != 2 {
+			t.Fatalf("%s: expected the double-faced card, got %+v (%v)", name, card, err)
+		}
+	}
+
+	setCards, err := db.SetCards("m10")
+	if err != nil {
+		t.Fatalf("getting set cards: %v", err)
+	}
+
+	if got := sets(setCards); !reflect.DeepEqual(got, []string{"m10 142a", "m10 146", "m10 242"}) {
+		t.Fatalf("unexpected set cards %v", got)
+	}
+
+	// legalities hold the formats which the scryfall client does not know
+	legalities, err := db.LegalitiesByName("Lightning Bolt")
+	if err != nil || legalities["brawl"] != "not_legal" || legalities["historic"] != "legal" {
+		t.Fatalf("unexpected legalities %v (%v)", legalities, err)
+	}
+
+	if _, err = db.CardByName("Black Lotus"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected an unknown card not to be found, got %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := testDB(t)
+
+	cards, err := db.Search("Lightning Bolt")
+	if err != nil || len(cards) != 3 {
+		t.Fatalf("expected every english printing of bolt, got %d (%v)", len(cards), err)
+	}
+
+	// every word of the query, in any name of the card
+	cards, err = db.Search("secrets delver")
+	if err != nil || len(cards) != 1 || cards[0].Set != "isd" {
+		t.Fatalf("expected delver, got %v (%v)", cards, err)
+	}
+
+	cards, err = db.Filter(func(card *scryfall.Card) bool { return card.Set == "m10" })
+	if err != nil || len(cards) != 3 || cards[0].Name != "Goblin Piker" {
+		t.Fatalf("expected the english m10 cards by name, got %v (%v)", cards, err)
+	}
+
+	names, err := db.Names()
+	expected := []string{"Delver of Secrets // Insectile Aberration", "Goblin Piker", "Lightning Bolt", "Mountain"}
+	if err != nil || !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected names %v, got %v (%v)", expected, names, err)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := ImportFile(dir, "testdata/default_cards.json")
+	if err != nil {
+		t.Fatalf("importing bulk data: %v", err)
+	}
+
+	_ = db.Close()
+
+	if !Exists(dir) {
+		t.Fatalf("expected a card database in %s", dir)
+	}
+
+	if db, err = Open(dir); err != nil {
+		t.Fatalf("opening card database: %v", err)
+	}
+
+	defer db.Close()
+
+	if card, err := db.CardByName("Mountain"); err != nil || card.TypeLine != "Basic Land — Mountain" {
+		t.Fatalf("expected mountain, got %+v (%v)", card, err)
+	}
+}
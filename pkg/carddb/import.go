@@ -0,0 +1,259 @@
+package carddb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// the fields of a card which are indexed
+type indexedCard struct {
+	ID              string `json:"id"`
+	OracleID        string `json:"oracle_id"`
+	Name            string `json:"name"`
+	Lang            string `json:"lang"`
+	Set             string `json:"set"`
+	CollectorNumber string `json:"collector_number"`
+	ReleasedAt      string `json:"released_at"`
+	Faces           []struct {
+		Name     string `json:"name"`
+		OracleID string `json:"oracle_id"`
+	} `json:"card_faces"`
+}
+
+// ImportFile imports a scryfall bulk data file, see Import
+func ImportFile(dir, path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening bulk data: %v", err)
+	}
+
+	defer f.Close()
+
+	return Import(dir, f)
+}
+
+// Import reads a scryfall bulk data file into a new card database in a
+// directory, replacing the database which was there. Any of the card bulk
+// data files can be imported: oracle cards, default cards or all cards. The
+// file is read as a stream, as the largest of them take gigabytes.
+func Import(dir string, r io.Reader) (*DB, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating card database directory: %v", err)
+	}
+
+	// the database is written next to the old one, and replaces it when
+	// the import is done
+	tmpCards := filepath.Join(dir, cardsFileName+".tmp")
+	tmpIndex := filepath.Join(dir, indexFileName+".tmp")
+
+	idx, err := writeCards(tmpCards, r)
+	if err != nil {
+		_ = os.Remove(tmpCards)
+		return nil, err
+	}
+
+	if err = writeIndex(tmpIndex, idx); err != nil {
+		_ = os.Remove(tmpCards)
+		_ = os.Remove(tmpIndex)
+
+		return nil, err
+	}
+
+	if err = os.Rename(tmpCards, filepath.Join(dir, cardsFileName)); err != nil {
+		return nil, fmt.Errorf("replacing cards: %v", err)
+	}
+
+	if err = os.Rename(tmpIndex, filepath.Join(dir, indexFileName)); err != nil {
+		return nil, fmt.Errorf("replacing card index: %v", err)
+	}
+
+	return Open(dir)
+}
+
+func writeCards(path string, r io.Reader) (*index, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating cards: %v", err)
+	}
+
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	if token, errToken := dec.Token(); errToken != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("bulk data is not a json array of cards")
+	}
+
+	idx := newIndex()
+	releases := make([]string, 0)
+
+	var offset int64
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("decoding card %d: %v", len(idx.Spans)+1, err)
+		}
+
+		var card indexedCard
+		if err = json.Unmarshal(raw, &card); err != nil {
+			return nil, fmt.Errorf("decoding card %d: %v", len(idx.Spans)+1, err)
+		}
+
+		line := new(bytes.Buffer)
+		if err = json.Compact(line, raw); err != nil {
+			return nil, fmt.Errorf("compacting card %d: %v", len(idx.Spans)+1, err)
+		}
+
+		line.WriteByte('\n')
+
+		if _, err = w.Write(line.Bytes()); err != nil {
+			return nil, fmt.Errorf("writing cards: %v", err)
+		}
+
+		idx.add(card, span{Offset: offset, Length: int32(line.Len() - 1)})
+		releases = append(releases, card.ReleasedAt)
+		offset += int64(line.Len())
+	}
+
+	if err = w.Flush(); err != nil {
+		return nil, fmt.Errorf("writing cards: %v", err)
+	}
+
+	idx.sort(releases)
+
+	return idx, f.Close()
+}
+
+func writeIndex(path string, idx *index) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating card index: %v", err)
+	}
+
+	defer f.Close()
+
+	if err = gob.NewEncoder(f).Encode(idx); err != nil {
+		return fmt.Errorf("encoding card index: %v", err)
+	}
+
+	return f.Close()
+}
+
+func (idx *index) add(card indexedCard, s span) {
+	pos := len(idx.Spans)
+	idx.Spans = append(idx.Spans, s)
+	idx.ByID[strings.ToLower(card.ID)] = pos
+
+	if card.Lang != "" && card.Lang != "en" {
+		if card.Set != "" {
+			idx.ByLanguage[languageKey(card.Set, card.CollectorNumber, card.Lang)] = pos
+		}
+
+		return
+	}
+
+	oracleID := card.OracleID
+	if oracleID == "" && len(card.Faces) > 0 {
+		// reversible cards have an oracle ID on every face
+		oracleID = card.Faces[0].OracleID
+	}
+
+	if key := nameKey(card.Name); key != "" {
+		idx.Names[key] = card.Name
+	}
+
+	names := []string{card.Name}
+	if len(card.Faces) > 1 {
+		for _, face := range card.Faces {
+			names = append(names, face.Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		key := nameKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		idx.ByName[key] = append(idx.ByName[key], pos)
+	}
+
+	if oracleID != "" {
+		idx.ByOracleID[strings.ToLower(oracleID)] = append(idx.ByOracleID[strings.ToLower(oracleID)], pos)
+	}
+
+	if card.Set != "" {
+		idx.ByPrinting[printingKey(card.Set, card.CollectorNumber)] = pos
+		idx.BySet[strings.ToLower(card.Set)] = append(idx.BySet[strings.ToLower(card.Set)], pos)
+	}
+}
+
+// sort orders the lists of cards newest first, and the cards of a set by
+// collector number
+func (idx *index) sort(releases []string) {
+	newestFirst := func(positions []int) {
+		sort.SliceStable(positions, func(i, j int) bool {
+			return releases[positions[i]] > releases[positions[j]]
+		})
+	}
+
+	for _, positions := range idx.ByName {
+		newestFirst(positions)
+	}
+
+	for _, positions := range idx.ByOracleID {
+		newestFirst(positions)
+	}
+
+	numbers := make(map[int]string)
+	for key, pos := range idx.ByPrinting {
+		numbers[pos] = key[strings.Index(key, "|")+1:]
+	}
+
+	for _, positions := range idx.BySet {
+		sort.SliceStable(positions, func(i, j int) bool {
+			return lessCollectorNumber(numbers[positions[i]], numbers[positions[j]])
+		})
+	}
+}
+
+// lessCollectorNumber orders collector numbers by their number, then by
+// their suffix, like 1, 2, 2a, 10
+func lessCollectorNumber(a, b string) bool {
+	na, sa := splitCollectorNumber(a)
+	nb, sb := splitCollectorNumber(b)
+
+	if na != nb {
+		return na < nb
+	}
+
+	return sa < sb
+}
+
+func splitCollectorNumber(s string) (int, string) {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+
+	n, err := strconv.Atoi(s[:end])
+	if err != nil {
+		return 0, s
+	}
+
+	return n, s[end:]
+}
@@ -0,0 +1,104 @@
+package cardhash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// artwork draws an image of waves, different seeds give unrelated images
+func artwork(seed float64, w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			fx, fy := float64(x)/float64(w), float64(y)/float64(h)
+			v := math.Sin(fx*seed*7+fy*3) + math.Cos(fy*seed*5-fx*seed)
+			c := uint8(127 + 60*v)
+
+			img.Set(x, y, color.RGBA{R: c, G: c / 2, B: 255 - c, A: 255})
+		}
+	}
+
+	return img
+}
+
+// photo puts artwork in a card frame, a bit brighter and as a jpeg, like a
+// phone photo of the card
+func photo(t *testing.T, art image.Image) image.Image {
+	const w, h = 488, 680
+
+	card := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(card, card.Rect, image.NewUniform(color.RGBA{R: 20, G: 20, B: 20, A: 255}), image.Point{}, draw.Src)
+
+	region := image.Rect(int(ArtRegion.MinX*w), int(ArtRegion.MinY*h), int(ArtRegion.MaxX*w), int(ArtRegion.MaxY*h))
+
+	// nearest neighbour scaling of the art into the frame
+	bounds := art.Bounds()
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			sx := bounds.Min.X + (x-region.Min.X)*bounds.Dx()/region.Dx()
+			sy := bounds.Min.Y + (y-region.Min.Y)*bounds.Dy()/region.Dy()
+
+			r, g, b, _ := art.At(sx, sy).RGBA()
+			card.Set(x, y, color.RGBA{R: brighter(r), G: brighter(g), B: brighter(b), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, card, &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return img
+}
+
+func brighter(v uint32) uint8 {
+	return uint8(min(255, v>>8+15))
+}
+
+func TestRecognizePhoto(t *testing.T) {
+	idx := New()
+
+	seeds := []float64{1, 2.5, 4, 6.5}
+	for n, seed := range seeds {
+		idx.Add(Entry{ID: string(rune('a' + n)), Name: "Card", Hash: Compute(artwork(seed, 626, 457))})
+	}
+
+	path := filepath.Join(t.TempDir(), "hashes.gob")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if idx.Len() != len(seeds) || !idx.Has("c") {
+		t.Fatalf("expected %d entries after loading, got %d", len(seeds), idx.Len())
+	}
+
+	for n, seed := range seeds {
+		hash := Compute(Art(photo(t, artwork(seed, 626, 457))))
+
+		matches := idx.Nearest(hash, 2, MaxDistance)
+		if len(matches) < 1 || matches[0].ID != string(rune('a'+n)) {
+			t.Fatalf("seed %v: expected entry %c, got %+v", seed, 'a'+n, matches)
+		}
+
+		if len(matches) > 1 && matches[1].Distance-matches[0].Distance < 10 {
+			t.Fatalf("seed %v: expected a clear match, got %+v", seed, matches)
+		}
+	}
+}
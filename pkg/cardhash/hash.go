@@ -0,0 +1,177 @@
+package cardhash
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// ArtRegion is where the art is on a card of the modern frame, relative to
+// the size of the card. Older frames place the art a bit differently, the
+// hashes are tolerant enough of that.
+var ArtRegion = struct{ MinX, MinY, MaxX, MaxY float64 }{0.08, 0.11, 0.92, 0.56}
+
+// Hash is a perceptual hash of an image: the difference hash and the hash
+// of the low frequencies of its discrete cosine transform. Similar images
+// have hashes which differ in few bits.
+type Hash struct {
+	D uint64
+	P uint64
+}
+
+// Distance is the number of bits two hashes differ in, from 0 to 128
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(h.D^other.D) + bits.OnesCount64(h.P^other.P)
+}
+
+// Compute hashes an image, like the art_crop images of scryfall
+func Compute(img image.Image) Hash {
+	return Hash{D: DHash(img), P: PHash(img)}
+}
+
+// Art yields the art of a photo or scan of a whole card. The photo must
+// show the card upright and fill the picture, the perspective is not
+// corrected.
+func Art(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	art := image.Rect(
+		bounds.Min.X+int(ArtRegion.MinX*w), bounds.Min.Y+int(ArtRegion.MinY*h),
+		bounds.Min.X+int(ArtRegion.MaxX*w), bounds.Min.Y+int(ArtRegion.MaxY*h),
+	)
+
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(art)
+	}
+
+	return &cropped{Image: img, rect: art}
+}
+
+type cropped struct {
+	image.Image
+	rect image.Rectangle
+}
+
+func (c *cropped) Bounds() image.Rectangle {
+	return c.rect
+}
+
+// DHash yields the difference hash of an image: whether each pixel of a 9x8
+// grayscale thumbnail is brighter than its right neighbour
+func DHash(img image.Image) (hash uint64) {
+	const w, h = 9, 8
+
+	gray := grayscale(img, w, h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+
+			if gray[y*w+x] > gray[y*w+x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// PHash yields the hash of the 8x8 lowest frequencies of the discrete cosine
+// transform of a 32x32 grayscale thumbnail: whether each is above their
+// median
+func PHash(img image.Image) (hash uint64) {
+	const size, low = 32, 8
+
+	coefficients := dct(grayscale(img, size, size), size)
+
+	lows := make([]float64, 0, low*low)
+	for y := 0; y < low; y++ {
+		lows = append(lows, coefficients[y*size:y*size+low]...)
+	}
+
+	// the first coefficient is the average brightness, left out of the
+	// median so that it does not skew it
+	sorted := append([]float64(nil), lows[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	for _, c := range lows {
+		hash <<= 1
+
+		if c > median {
+			hash |= 1
+		}
+	}
+
+	return hash
+}
+
+// grayscale shrinks an image to a thumbnail of brightness values, every
+// thumbnail pixel is the average of the image pixels it covers
+func grayscale(img image.Image, w, h int) []float64 {
+	bounds := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		ty := (y - bounds.Min.Y) * h / bounds.Dy()
+
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			tx := (x - bounds.Min.X) * w / bounds.Dx()
+
+			r, g, b, _ := img.At(x, y).RGBA()
+			sums[ty*w+tx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			counts[ty*w+tx]++
+		}
+	}
+
+	for idx := range sums {
+		if counts[idx] > 0 {
+			sums[idx] /= float64(counts[idx])
+		}
+	}
+
+	return sums
+}
+
+// dct is the two dimensional discrete cosine transform of a square of
+// values, row by row
+func dct(values []float64, size int) []float64 {
+	cosines := make([]float64, size*size)
+	for u := 0; u < size; u++ {
+		for x := 0; x < size; x++ {
+			cosines[u*size+x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / float64(2*size))
+		}
+	}
+
+	// the rows first, then the columns of the transformed rows
+	rows := make([]float64, size*size)
+	for y := 0; y < size; y++ {
+		for u := 0; u < size; u++ {
+			var sum float64
+			for x := 0; x < size; x++ {
+				sum += values[y*size+x] * cosines[u*size+x]
+			}
+
+			rows[y*size+u] = sum
+		}
+	}
+
+	result := make([]float64, size*size)
+	for u := 0; u < size; u++ {
+		for v := 0; v < size; v++ {
+			var sum float64
+			for y := 0; y < size; y++ {
+				sum += rows[y*size+u] * cosines[v*size+y]
+			}
+
+			result[v*size+u] = sum
+		}
+	}
+
+	return result
+}
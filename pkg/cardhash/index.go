@@ -0,0 +1,149 @@
+package cardhash
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MaxDistance is the distance up to which a photo is taken to show a card.
+// Unrelated images differ in about half of the 128 bits.
+const MaxDistance = 40
+
+// Entry is the hash of the art of a printing
+type Entry struct {
+	ID              string
+	Name            string
+	Set             string
+	CollectorNumber string
+	Hash            Hash
+}
+
+// Match is an entry of the index, and how far its hash is from the hash
+// looked up
+type Match struct {
+	Entry
+	Distance int
+}
+
+// Index holds the hashes of the art of printings, by scryfall ID
+type Index struct {
+	mux     sync.Mutex
+	entries []Entry
+	ids     map[string]int
+}
+
+// New creates an empty index
+func New() *Index {
+	return &Index{ids: make(map[string]int)}
+}
+
+// Load reads an index file. A file which does not exist yet is an empty
+// index.
+func Load(path string) (*Index, error) {
+	idx := New()
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("opening hash index: %v", err)
+	}
+
+	defer f.Close()
+
+	if err = gob.NewDecoder(f).Decode(&idx.entries); err != nil {
+		return nil, fmt.Errorf("decoding hash index: %v", err)
+	}
+
+	for pos, entry := range idx.entries {
+		idx.ids[entry.ID] = pos
+	}
+
+	return idx, nil
+}
+
+// Save writes the index to a file
+func (idx *Index) Save(path string) error {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating hash index directory: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating hash index: %v", err)
+	}
+
+	defer f.Close()
+
+	if err = gob.NewEncoder(f).Encode(idx.entries); err != nil {
+		return fmt.Errorf("encoding hash index: %v", err)
+	}
+
+	return f.Close()
+}
+
+// Len yields the number of printings in the index
+func (idx *Index) Len() int {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	return len(idx.entries)
+}
+
+// Has tells if a printing is in the index, by scryfall ID
+func (idx *Index) Has(id string) bool {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	_, found := idx.ids[id]
+
+	return found
+}
+
+// Add adds the hash of a printing, replacing the hash it had
+func (idx *Index) Add(entry Entry) {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	if pos, found := idx.ids[entry.ID]; found {
+		idx.entries[pos] = entry
+		return
+	}
+
+	idx.ids[entry.ID] = len(idx.entries)
+	idx.entries = append(idx.entries, entry)
+}
+
+// Nearest yields up to limit printings with the hashes nearest to a hash,
+// the nearest first. Printings further than maxDistance are left out.
+func (idx *Index) Nearest(hash Hash, limit, maxDistance int) []Match {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	matches := make([]Match, 0)
+
+	for _, entry := range idx.entries {
+		if distance := hash.Distance(entry.Hash); distance <= maxDistance {
+			matches = append(matches, Match{Entry: entry, Distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
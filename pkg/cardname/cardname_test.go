@@ -0,0 +1,90 @@
+package cardname
+
+import (
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var testNames = []string{
+	"Lightning Bolt",
+	"Lightning Helix",
+	"Lim-Dûl's Vault",
+	"Fire // Ice",
+	"Delver of Secrets // Insectile Aberration",
+	"Tarmogoyf",
+	"Æther Vial",
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Lim-Dûl's Vault":     "lim duls vault",
+		"lim-dul's vault":     "lim duls vault",
+		"Fire/Ice":            "fire / ice",
+		"Fire // Ice":         "fire / ice",
+		"Æther Vial":          "aether vial",
+		"  Lightning   Bolt ": "lightning bolt",
+	}
+
+	for name, expected := range tests {
+		if normalized := Normalize(name); normalized != expected {
+			t.Fatalf("%q: expected %q, got %q", name, expected, normalized)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := NewResolver(testNames)
+
+	tests := map[string]string{
+		"Lim-Dul's Vault":      "Lim-Dûl's Vault",
+		"Fire/Ice":             "Fire // Ice",
+		"Ice":                  "Fire // Ice",
+		"Insectile Aberration": "Delver of Secrets // Insectile Aberration",
+		"Lightning Blot":       "Lightning Bolt",
+		"Tarmogof":             "Tarmogoyf",
+		"Aether Vial":          "Æther Vial",
+	}
+
+	for name, expected := range tests {
+		best, ok := r.Resolve(name)
+		if !ok || best.Name != expected {
+			t.Fatalf("%q: expected %q, got %+v (%v)", name, expected, best, ok)
+		}
+	}
+
+	if best, ok := r.Resolve("Lightning"); ok {
+		t.Fatalf("expected no confident match for an ambiguous name, got %+v", best)
+	}
+
+	suggestions := r.Suggest("Lightning", 5)
+	if len(suggestions) < 2 || suggestions[0].Name != "Lightning Bolt" || suggestions[1].Name != "Lightning Helix" {
+		t.Fatalf("expected both lightning cards, got %+v", suggestions)
+	}
+}
+
+func TestCorrectDeck(t *testing.T) {
+	deck, err := decklist.Parse("4 Lightning Blot\n4 Delver of Secrets\n2 Goblin Guide\n\n1 Fire/Ice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrected, corrections := NewResolver(testNames).CorrectDeck(deck)
+
+	if len(corrections) != 3 {
+		t.Fatalf("expected 3 corrections, got %+v", corrections)
+	}
+
+	main := corrected.Section(decklist.SectionMain)
+	if main[0].Name != "Lightning Bolt" || main[1].Name != "Delver of Secrets" || main[2].Name != "Goblin Guide" {
+		t.Fatalf("unexpected main deck %+v", main)
+	}
+
+	if corrections[1].Name != "Goblin Guide" || corrections[1].Corrected {
+		t.Fatalf("expected an unknown card not to be corrected, got %+v", corrections[1])
+	}
+
+	if side := corrected.Section(decklist.SectionSideboard); side[0].Name != "Fire // Ice" {
+		t.Fatalf("unexpected sideboard %+v", side)
+	}
+}
@@ -0,0 +1,69 @@
+package cardname
+
+import (
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// Correction is a name in a deck which is not the name of a card as is
+type Correction struct {
+	Section     decklist.Section `json:"section"`
+	Name        string           `json:"name"`
+	Suggestions []Suggestion     `json:"suggestions"`
+
+	// Corrected tells if the name was replaced with the first suggestion
+	Corrected bool `json:"corrected"`
+}
+
+// the number of suggestions of a correction
+const correctionSuggestions = 5
+
+// CorrectDeck resolves the names of a deck. Names the resolver is confident
+// about are corrected in the copy of the deck it yields, the other names
+// are kept and reported with suggestions, to ask the user. Cards with
+// several faces may be named by their front face.
+func (r *Resolver) CorrectDeck(deck *decklist.Deck) (*decklist.Deck, []Correction) {
+	corrected := decklist.New()
+	corrected.Name = deck.Name
+
+	corrections := make([]Correction, 0)
+
+	for _, section := range decklist.Sections {
+		for _, entry := range deck.Section(section) {
+			if r.isCardName(entry.Name) {
+				corrected.Add(section, entry)
+				continue
+			}
+
+			c := Correction{
+				Section:     section,
+				Name:        entry.Name,
+				Suggestions: r.Suggest(entry.Name, correctionSuggestions),
+			}
+
+			if best, ok := r.Resolve(entry.Name); ok {
+				entry.Name = best.Name
+				c.Corrected = true
+			}
+
+			corrected.Add(section, entry)
+			corrections = append(corrections, c)
+		}
+	}
+
+	return corrected, corrections
+}
+
+// isCardName tells if a name is the name of a card, or of its front face
+func (r *Resolver) isCardName(name string) bool {
+	for _, idx := range r.exact[Normalize(name)] {
+		full := r.names[idx]
+
+		if full == name || strings.TrimSpace(strings.Split(full, "//")[0]) == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package cardname
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize reduces a card name to what matters when people type it:
+// case, accents, apostrophes and other punctuation are dropped, and the
+// faces of split cards are separated by a single slash. "Lim-Dûl's Vault"
+// becomes "lim duls vault", and "Fire/Ice" becomes "fire / ice".
+func Normalize(name string) string {
+	words := make([]string, 0)
+	word := strings.Builder{}
+
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// the accents of decomposed letters
+		case r == 'æ' || r == 'Æ':
+			word.WriteString("ae")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+		case r == '/':
+			flush()
+
+			if len(words) > 0 && words[len(words)-1] != "/" {
+				words = append(words, "/")
+			}
+		default:
+			flush()
+		}
+	}
+
+	flush()
+
+	return strings.Join(words, " ")
+}
+
+// faces yields the names of the faces of a card with several faces, like
+// "Fire" and "Ice" for "Fire // Ice"
+func faces(name string) []string {
+	parts := strings.Split(name, "//")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	for idx := range parts {
+		parts[idx] = strings.TrimSpace(parts[idx])
+	}
+
+	return parts
+}
+
+// distance is the optimal string alignment distance of two strings: the
+// number of insertions, deletions, substitutions and transpositions of
+// adjacent letters which turn one into the other
+func distance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(a)][len(b)]
+}
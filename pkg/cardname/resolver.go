@@ -0,0 +1,153 @@
+package cardname
+
+import (
+	"sort"
+)
+
+// confidence thresholds
+const (
+	// MinConfidence is the lowest confidence of a suggestion
+	MinConfidence = 0.5
+
+	// AutoCorrectConfidence is the lowest confidence at which a name is
+	// corrected without asking
+	AutoCorrectConfidence = 0.8
+)
+
+// Suggestion is a card name which a misspelled name might mean. Confidence
+// ranges from MinConfidence to 1, where 1 is a match after normalization.
+type Suggestion struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Resolver resolves misspelled card names to the names of real cards
+type Resolver struct {
+	names []string
+
+	// the normalized names and face names, and the card names they are of
+	keys  []key
+	exact map[string][]int
+}
+
+type key struct {
+	normalized []rune
+	name       int
+}
+
+// NewResolver creates a resolver for a list of card names. Cards with
+// several faces can also be found by the name of any face.
+func NewResolver(names []string) *Resolver {
+	r := &Resolver{
+		names: names,
+		exact: make(map[string][]int),
+	}
+
+	for idx, name := range names {
+		aliases := append([]string{name}, faces(name)...)
+
+		for _, alias := range aliases {
+			normalized := Normalize(alias)
+			if normalized == "" {
+				continue
+			}
+
+			r.keys = append(r.keys, key{normalized: []rune(normalized), name: idx})
+			r.exact[normalized] = append(r.exact[normalized], idx)
+		}
+	}
+
+	return r
+}
+
+// Len yields the number of card names the resolver knows
+func (r *Resolver) Len() int {
+	return len(r.names)
+}
+
+// Suggest yields up to limit card names which a name might mean, the most
+// likely first. Names which are equal after normalization are certain, any
+// other name is ranked by its edit distance.
+func (r *Resolver) Suggest(name string, limit int) []Suggestion {
+	normalized := Normalize(name)
+	if normalized == "" {
+		return nil
+	}
+
+	if exact, found := r.exact[normalized]; found {
+		suggestions := make([]Suggestion, 0, len(exact))
+
+		for _, idx := range exact {
+			suggestions = append(suggestions, Suggestion{Name: r.names[idx], Confidence: 1})
+		}
+
+		return truncate(suggestions, limit)
+	}
+
+	query := []rune(normalized)
+	best := make(map[int]float64)
+
+	for _, k := range r.keys {
+		longest := max(len(query), len(k.normalized))
+
+		// the distance is at least the difference in length
+		if bound := 1 - float64(abs(len(query)-len(k.normalized)))/float64(longest); bound < MinConfidence {
+			continue
+		}
+
+		confidence := 1 - float64(distance(query, k.normalized))/float64(longest)
+		if confidence < MinConfidence || confidence <= best[k.name] {
+			continue
+		}
+
+		best[k.name] = confidence
+	}
+
+	suggestions := make([]Suggestion, 0, len(best))
+
+	for idx, confidence := range best {
+		suggestions = append(suggestions, Suggestion{Name: r.names[idx], Confidence: confidence})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	return truncate(suggestions, limit)
+}
+
+// Resolve yields the card a name means, if the resolver is confident enough
+// to correct the name without asking: the best suggestion has to reach
+// AutoCorrectConfidence, and be better than the next one.
+func (r *Resolver) Resolve(name string) (Suggestion, bool) {
+	suggestions := r.Suggest(name, 2)
+	if len(suggestions) == 0 || suggestions[0].Confidence < AutoCorrectConfidence {
+		return Suggestion{}, false
+	}
+
+	if len(suggestions) > 1 && suggestions[1].Confidence == suggestions[0].Confidence {
+		return Suggestion{}, false
+	}
+
+	return suggestions[0], true
+}
+
+func truncate(suggestions []Suggestion, limit int) []Suggestion {
+	if limit > 0 && len(suggestions) > limit {
+		return suggestions[:limit]
+	}
+
+	return suggestions
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,236 @@
+package cardquery
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// face is the part of a card the terms look at, cards with several faces
+// have one per face
+type face struct {
+	name, typeLine, oracleText, manaCost string
+	power, toughness, loyalty            *string
+}
+
+func faces(card *scryfall.Card) []face {
+	if len(card.CardFaces) == 0 {
+		return []face{{
+			name:       card.Name,
+			typeLine:   card.TypeLine,
+			oracleText: card.OracleText,
+			manaCost:   card.ManaCost,
+			power:      card.Power,
+			toughness:  card.Toughness,
+			loyalty:    card.Loyalty,
+		}}
+	}
+
+	result := make([]face, 0, len(card.CardFaces))
+
+	for _, f := range card.CardFaces {
+		text := ""
+		if f.OracleText != nil {
+			text = *f.OracleText
+		}
+
+		result = append(result, face{
+			name:       f.Name,
+			typeLine:   f.TypeLine,
+			oracleText: text,
+			manaCost:   f.ManaCost,
+			power:      f.Power,
+			toughness:  f.Toughness,
+			loyalty:    f.Loyalty,
+		})
+	}
+
+	return result
+}
+
+// names yields the name of a card followed by the names of its faces
+func names(card *scryfall.Card) []string {
+	result := []string{card.Name}
+
+	for _, f := range card.CardFaces {
+		result = append(result, f.Name)
+	}
+
+	return result
+}
+
+func typeLines(card *scryfall.Card) []string {
+	result := []string{card.TypeLine}
+
+	for _, f := range card.CardFaces {
+		result = append(result, f.TypeLine)
+	}
+
+	return result
+}
+
+func oracleTexts(card *scryfall.Card) []string {
+	result := make([]string, 0)
+
+	for _, f := range faces(card) {
+		result = append(result, f.oracleText)
+	}
+
+	return result
+}
+
+func artists(card *scryfall.Card) []string {
+	if card.Artist == nil {
+		return nil
+	}
+
+	return []string{*card.Artist}
+}
+
+// stats yields a number like power of every face which has it, "*" and
+// friends count as 0 like scryfall does
+func stats(card *scryfall.Card, stat func(f face) *string) []float64 {
+	result := make([]float64, 0)
+
+	for _, f := range faces(card) {
+		value := stat(f)
+		if value == nil {
+			continue
+		}
+
+		number, err := strconv.ParseFloat(strings.Trim(*value, "+*"), 64)
+		if err != nil {
+			number = 0
+		}
+
+		result = append(result, number)
+	}
+
+	return result
+}
+
+// price yields the non-foil price of a card in a currency, if it has one
+func price(card *scryfall.Card, currency string) []float64 {
+	var value string
+
+	switch currency {
+	case "usd":
+		value = card.Prices.USD
+	case "eur":
+		value = card.Prices.EUR
+	case "tix":
+		value = card.Prices.Tix
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+
+	return []float64{number}
+}
+
+func legality(card *scryfall.Card, format string) (scryfall.Legality, bool) {
+	l := card.Legalities
+
+	switch format {
+	case "standard":
+		return l.Standard, true
+	case "modern":
+		return l.Modern, true
+	case "pauper":
+		return l.Pauper, true
+	case "pioneer":
+		return l.Pioneer, true
+	case "legacy":
+		return l.Legacy, true
+	case "penny":
+		return l.Penny, true
+	case "vintage":
+		return l.Vintage, true
+	case "duel":
+		return l.Duel, true
+	case "commander", "edh":
+		return l.Commander, true
+	case "future":
+		return l.Future, true
+	}
+
+	return "", false
+}
+
+func hasFinish(card *scryfall.Card, finish scryfall.Finish) bool {
+	for _, f := range card.Finishes {
+		if f == finish {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isType(card *scryfall.Card, types ...string) bool {
+	for _, line := range typeLines(card) {
+		for _, t := range types {
+			if strings.Contains(line, t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isLayout(layouts ...scryfall.Layout) func(card *scryfall.Card) bool {
+	return func(card *scryfall.Card) bool {
+		for _, layout := range layouts {
+			if card.Layout == layout {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// the properties of "is:" and "not:"
+var properties = map[string]func(card *scryfall.Card) bool{
+	"commander": func(c *scryfall.Card) bool {
+		legendaryCreature := strings.Contains(c.TypeLine, "Legendary") && isType(c, "Creature")
+
+		return legendaryCreature || strings.Contains(c.OracleText, "can be your commander")
+	},
+	"permanent": func(c *scryfall.Card) bool {
+		return isType(c, "Artifact", "Battle", "Creature", "Enchantment", "Land", "Planeswalker")
+	},
+	"spell": func(c *scryfall.Card) bool {
+		return !isType(c, "Land") && isType(c, "Artifact", "Battle", "Creature", "Enchantment",
+			"Instant", "Planeswalker", "Sorcery", "Tribal", "Kindred")
+	},
+	"historic": func(c *scryfall.Card) bool {
+		return isType(c, "Artifact", "Legendary", "Saga")
+	},
+	"vanilla": func(c *scryfall.Card) bool {
+		return isType(c, "Creature") && c.OracleText == "" && len(c.CardFaces) == 0
+	},
+	"split":     isLayout(scryfall.LayoutSplit),
+	"flip":      isLayout(scryfall.LayoutFlip),
+	"transform": isLayout(scryfall.LayoutTransform),
+	"mdfc":      isLayout(scryfall.LayoutModalDFC),
+	"meld":      isLayout(scryfall.LayoutMeld),
+	"leveler":   isLayout(scryfall.LayoutLeveler),
+	"saga":      isLayout(scryfall.LayoutSaga),
+	"adventure": isLayout(scryfall.LayoutAdventure),
+	"dfc": isLayout(scryfall.LayoutTransform, scryfall.LayoutModalDFC, scryfall.LayoutMeld,
+		scryfall.LayoutDoubleFacedToken, scryfall.LayoutDoubleSided),
+	"foil":     func(c *scryfall.Card) bool { return c.Foil || hasFinish(c, scryfall.FinishFoil) },
+	"nonfoil":  func(c *scryfall.Card) bool { return c.NonFoil || hasFinish(c, scryfall.FinishNonFoil) },
+	"etched":   func(c *scryfall.Card) bool { return hasFinish(c, scryfall.FinishEtched) },
+	"reprint":  func(c *scryfall.Card) bool { return c.Reprint },
+	"promo":    func(c *scryfall.Card) bool { return c.Promo },
+	"digital":  func(c *scryfall.Card) bool { return c.Digital },
+	"reserved": func(c *scryfall.Card) bool { return c.Reserved },
+	"fullart":  func(c *scryfall.Card) bool { return c.FullArt },
+	"booster":  func(c *scryfall.Card) bool { return c.Booster },
+}
@@ -0,0 +1,163 @@
+package cardquery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+func text(s string) *string { return &s }
+
+var testCards = []scryfall.Card{
+	{
+		Name: "Lightning Bolt", TypeLine: "Instant", ManaCost: "{R}", CMC: 1, Rarity: "common", Set: "m10",
+		OracleText: "Lightning Bolt deals 3 damage to any target.",
+		Colors:     []scryfall.Color{scryfall.ColorRed}, ColorIdentity: []scryfall.Color{scryfall.ColorRed},
+		Legalities: scryfall.Legalities{Modern: scryfall.LegalityLegal, Standard: scryfall.LegalityNotLegal},
+		Prices:     scryfall.Prices{USD: "1.50"},
+	},
+	{
+		Name: "Tarmogoyf", TypeLine: "Creature — Lhurgoyf", ManaCost: "{1}{G}", CMC: 2, Rarity: "mythic", Set: "mm2",
+		Power: text("*"), Toughness: text("1+*"),
+		Colors: []scryfall.Color{scryfall.ColorGreen}, ColorIdentity: []scryfall.Color{scryfall.ColorGreen},
+		Legalities: scryfall.Legalities{Modern: scryfall.LegalityLegal},
+		Prices:     scryfall.Prices{USD: "20.00"},
+	},
+	{
+		Name: "Niv-Mizzet, Parun", TypeLine: "Legendary Creature — Dragon Wizard", ManaCost: "{U}{U}{U}{R}{R}{R}",
+		CMC: 6, Rarity: "rare", Set: "grn", Power: text("5"), Toughness: text("5"),
+		Colors:        []scryfall.Color{scryfall.ColorBlue, scryfall.ColorRed},
+		ColorIdentity: []scryfall.Color{scryfall.ColorBlue, scryfall.ColorRed},
+		Legalities:    scryfall.Legalities{Modern: scryfall.LegalityLegal, Commander: scryfall.LegalityLegal},
+	},
+	{
+		Name: "Delver of Secrets // Insectile Aberration", Layout: scryfall.LayoutTransform, CMC: 1,
+		TypeLine: "Creature — Human Wizard // Creature — Human Insect", Rarity: "common", Set: "isd",
+		ColorIdentity: []scryfall.Color{scryfall.ColorBlue},
+		CardFaces: []scryfall.CardFace{
+			{Name: "Delver of Secrets", TypeLine: "Creature — Human Wizard", ManaCost: "{U}",
+				Power: text("1"), Toughness: text("1"), Colors: []scryfall.Color{scryfall.ColorBlue},
+				OracleText: text("At the beginning of your upkeep, look at the top card of your library.")},
+			{Name: "Insectile Aberration", TypeLine: "Creature — Human Insect",
+				Power: text("3"), Toughness: text("2"), Colors: []scryfall.Color{scryfall.ColorBlue},
+				OracleText: text("Flying")},
+		},
+		Legalities: scryfall.Legalities{Modern: scryfall.LegalityLegal, Legacy: scryfall.LegalityLegal},
+	},
+	{
+		Name: "Sol Ring", TypeLine: "Artifact", ManaCost: "{1}", CMC: 1, Rarity: "uncommon", Set: "cmr",
+		OracleText: "{T}: Add {C}{C}.",
+		Legalities: scryfall.Legalities{Commander: scryfall.LegalityLegal, Vintage: scryfall.LegalityRestricted,
+			Legacy: scryfall.LegalityBanned},
+	},
+}
+
+func TestQueryMatch(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"bolt", []string{"Lightning Bolt"}},
+		{"!\"sol ring\"", []string{"Sol Ring"}},
+		{"insectile", []string{"Delver of Secrets // Insectile Aberration"}},
+		{"c:r", []string{"Lightning Bolt", "Niv-Mizzet, Parun"}},
+		{"c=r", []string{"Lightning Bolt"}},
+		{"c:c", []string{"Sol Ring"}},
+		{"c:m", []string{"Niv-Mizzet, Parun"}},
+		{"c:izzet", []string{"Niv-Mizzet, Parun"}},
+		{"id:u", []string{"Delver of Secrets // Insectile Aberration", "Sol Ring"}},
+		{"id<=ur -c:c", []string{"Lightning Bolt", "Niv-Mizzet, Parun", "Delver of Secrets // Insectile Aberration"}},
+		{"c:u t:creature", []string{"Niv-Mizzet, Parun", "Delver of Secrets // Insectile Aberration"}},
+		{"t:legendary", []string{"Niv-Mizzet, Parun"}},
+		{"o:\"~ deals 3\"", []string{"Lightning Bolt"}},
+		{"o:/^flying$/", []string{"Delver of Secrets // Insectile Aberration"}},
+		{"mv>=2", []string{"Tarmogoyf", "Niv-Mizzet, Parun"}},
+		{"cmc:even", []string{"Tarmogoyf", "Niv-Mizzet, Parun"}},
+		{"pow>=3", []string{"Niv-Mizzet, Parun", "Delver of Secrets // Insectile Aberration"}},
+		{"pow=0 tou=1", []string{"Tarmogoyf"}},
+		{"r>=rare", []string{"Tarmogoyf", "Niv-Mizzet, Parun"}},
+		{"s:isd or e:CMR", []string{"Delver of Secrets // Insectile Aberration", "Sol Ring"}},
+		{"f:vintage", []string{"Sol Ring"}},
+		{"banned:legacy", []string{"Sol Ring"}},
+		{"f:modern -(t:creature or o:damage)", []string{}},
+		{"is:commander", []string{"Niv-Mizzet, Parun"}},
+		{"is:dfc", []string{"Delver of Secrets // Insectile Aberration"}},
+		{"not:permanent", []string{"Lightning Bolt"}},
+		{"m:rrr", []string{"Niv-Mizzet, Parun"}},
+		{"m:1g", []string{"Tarmogoyf"}},
+		{"usd<5", []string{"Lightning Bolt"}},
+	}
+
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+
+		matched := q.Filter(testCards)
+
+		if len(matched) != len(test.expected) {
+			t.Fatalf("%s: expected %v, got %d cards", test.query, test.expected, len(matched))
+		}
+
+		for idx := range matched {
+			if matched[idx].Name != test.expected[idx] {
+				t.Fatalf("%s: expected %v, got %s at %d", test.query, test.expected, matched[idx].Name, idx)
+			}
+		}
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	q, err := Parse("lightning bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name, ok := q.Name(); !ok || name != "lightning bolt" {
+		t.Fatalf("expected a name query, got %q %v", name, ok)
+	}
+
+	q, err = Parse("bolt c:r")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := q.Name(); ok {
+		t.Fatal("expected a query with keywords not to be a name query")
+	}
+}
+
+func TestManaSymbols(t *testing.T) {
+	tests := []struct {
+		cost     string
+		expected map[string]int
+	}{
+		{"{10}{R}", map[string]int{"10": 1, "R": 1}},
+		{"10rr", map[string]int{"10": 1, "R": 2}},
+		{"{2/W}{2/W}u", map[string]int{"2/W": 2, "U": 1}},
+	}
+
+	for _, test := range tests {
+		symbols := manaSymbols(test.cost)
+
+		if len(symbols) != len(test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.cost, test.expected, symbols)
+		}
+
+		for symbol, count := range test.expected {
+			if symbols[symbol] != count {
+				t.Fatalf("%s: expected %v, got %v", test.cost, test.expected, symbols)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{"", "(bolt", "bolt)", "c:purple", "foo:bar", "mv>x", "bolt or", "o:/[/", "is:"} {
+		if _, err := Parse(query); !errors.Is(err, ErrSyntax) {
+			t.Fatalf("%q: expected a syntax error, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cardquery
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// colorMask is a set of colors, one bit per color
+type colorMask uint8
+
+var colorBits = map[rune]colorMask{'w': 1, 'u': 2, 'b': 4, 'r': 8, 'g': 16}
+
+// color names, guilds, shards and wedges, like scryfall knows them
+var colorNames = map[string]string{
+	"white": "w", "blue": "u", "black": "b", "red": "r", "green": "g",
+	"azorius": "wu", "dimir": "ub", "rakdos": "br", "gruul": "rg", "selesnya": "gw",
+	"orzhov": "wb", "izzet": "ur", "golgari": "bg", "boros": "rw", "simic": "gu",
+	"bant": "gwu", "esper": "wub", "grixis": "ubr", "jund": "brg", "naya": "rgw",
+	"abzan": "wbg", "jeskai": "urw", "sultai": "bgu", "mardu": "rwb", "temur": "gur",
+	"colorless": "c", "multicolor": "m",
+}
+
+func maskOf(colors []scryfall.Color) (mask colorMask) {
+	for _, c := range colors {
+		mask |= colorBits[rune(strings.ToLower(string(c))[0])]
+	}
+
+	return mask
+}
+
+// colorTerm matches the colors or the color identity of cards. For colors
+// ":" means "at least these colors", for the color identity it means "fits
+// in a deck of these colors", like scryfall.
+type colorTerm struct {
+	identity bool
+	op       string
+	mask     colorMask
+
+	// count compares the number of colors instead, when it is not negative
+	count int
+
+	multicolor bool
+}
+
+func newColorTerm(identity bool, op, value string) (node, error) {
+	n := &colorTerm{identity: identity, op: op, count: -1}
+
+	if n.op == ":" {
+		n.op = ">="
+		if identity {
+			n.op = "<="
+		}
+	}
+
+	value = strings.ToLower(value)
+	if name, found := colorNames[value]; found {
+		value = name
+	}
+
+	if count, err := strconv.Atoi(value); err == nil {
+		n.count, n.op = count, op
+		if n.op == ":" {
+			n.op = "="
+		}
+
+		return n, nil
+	}
+
+	switch value {
+	case "c":
+		n.op = "="
+		return n, nil
+	case "m":
+		n.multicolor = true
+		return n, nil
+	}
+
+	for _, r := range value {
+		bit, found := colorBits[r]
+		if !found {
+			return nil, fmt.Errorf("unknown color %q", value)
+		}
+
+		n.mask |= bit
+	}
+
+	return n, nil
+}
+
+func (n *colorTerm) match(card *scryfall.Card) bool {
+	mask := maskOf(card.ColorIdentity)
+
+	if !n.identity {
+		mask = maskOf(card.Colors)
+
+		if card.Colors == nil {
+			for _, f := range card.CardFaces {
+				mask |= maskOf(f.Colors)
+			}
+		}
+	}
+
+	count := bits.OnesCount8(uint8(mask))
+
+	if n.multicolor {
+		return count > 1
+	}
+
+	if n.count >= 0 {
+		return compare(float64(count), n.op, float64(n.count))
+	}
+
+	switch n.op {
+	case "=":
+		return mask == n.mask
+	case "!=":
+		return mask != n.mask
+	case ">=":
+		return mask&n.mask == n.mask
+	case ">":
+		return mask&n.mask == n.mask && mask != n.mask
+	case "<=":
+		return mask&^n.mask == 0
+	case "<":
+		return mask&^n.mask == 0 && mask != n.mask
+	}
+
+	return false
+}
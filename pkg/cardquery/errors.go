@@ -0,0 +1,23 @@
+package cardquery
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSyntax is wrapped by every error of Parse
+var ErrSyntax = errors.New("invalid query")
+
+// SyntaxError is a query which can not be parsed, at a byte offset
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at %d: %s", ErrSyntax, e.Pos, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
@@ -0,0 +1,196 @@
+package cardquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenNot
+	tokenOr
+	tokenAnd
+	tokenTerm
+)
+
+// token is a part of a query. Terms are either a keyword, an operator and
+// a value, like "cmc>=3", or just a value, which is matched against the
+// card name.
+type token struct {
+	kind tokenKind
+	pos  int
+
+	keyword string
+	op      string
+	value   string
+
+	// quoted values are matched as a whole, regex values with a regular
+	// expression, exact values are exact card names
+	quoted bool
+	regex  bool
+	exact  bool
+}
+
+// operators, longest first so that ">=" is not read as ">"
+var operators = []string{">=", "<=", "!=", ":", "=", "<", ">"}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func lex(src string) ([]token, error) {
+	l := &lexer{src: src}
+	tokens := make([]token, 0)
+
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, t)
+
+		if t.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+
+	start := l.pos
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	switch l.src[l.pos] {
+	case '(':
+		l.pos++
+		return token{kind: tokenOpen, pos: start}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokenClose, pos: start}, nil
+	case '-':
+		l.pos++
+		return token{kind: tokenNot, pos: start}, nil
+	case '!':
+		l.pos++
+
+		value, quoted, err := l.value()
+		if err != nil {
+			return token{}, err
+		}
+
+		return token{kind: tokenTerm, pos: start, value: value, quoted: quoted, exact: true}, nil
+	}
+
+	t := token{kind: tokenTerm, pos: start}
+
+	// a keyword is a run of letters followed by an operator
+	end := l.pos
+	for end < len(l.src) && (unicode.IsLetter(rune(l.src[end])) || l.src[end] == '_') {
+		end++
+	}
+
+	for _, op := range operators {
+		if end > l.pos && strings.HasPrefix(l.src[end:], op) {
+			t.keyword = strings.ToLower(l.src[l.pos:end])
+			t.op = op
+			l.pos = end + len(op)
+
+			break
+		}
+	}
+
+	if t.keyword != "" && l.pos < len(l.src) && l.src[l.pos] == '/' {
+		value, err := l.regex()
+		if err != nil {
+			return token{}, err
+		}
+
+		t.value, t.regex = value, true
+
+		return t, nil
+	}
+
+	value, quoted, err := l.value()
+	if err != nil {
+		return token{}, err
+	}
+
+	t.value, t.quoted = value, quoted
+
+	if t.keyword == "" && !quoted {
+		switch strings.ToLower(value) {
+		case "or":
+			return token{kind: tokenOr, pos: start}, nil
+		case "and":
+			return token{kind: tokenAnd, pos: start}, nil
+		}
+	}
+
+	if value == "" {
+		return token{}, &SyntaxError{Pos: start, Msg: "missing value"}
+	}
+
+	return t, nil
+}
+
+// value reads a quoted string, or a word up to a space or parenthesis
+func (l *lexer) value() (string, bool, error) {
+	if l.pos < len(l.src) && l.src[l.pos] == '"' {
+		start := l.pos
+		end := strings.IndexByte(l.src[l.pos+1:], '"')
+
+		if end < 0 {
+			return "", false, &SyntaxError{Pos: start, Msg: "unterminated quote"}
+		}
+
+		value := l.src[l.pos+1 : l.pos+1+end]
+		l.pos += end + 2
+
+		return value, true, nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.src) && !unicode.IsSpace(rune(l.src[l.pos])) && l.src[l.pos] != '(' && l.src[l.pos] != ')' {
+		l.pos++
+	}
+
+	return l.src[start:l.pos], false, nil
+}
+
+// regex reads a regular expression between slashes, slashes can be escaped
+func (l *lexer) regex() (string, error) {
+	start := l.pos
+	l.pos++
+
+	var b strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch {
+		case c == '\\' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '/':
+			b.WriteByte('/')
+			l.pos += 2
+		case c == '/':
+			l.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return "", &SyntaxError{Pos: start, Msg: "unterminated regular expression"}
+}
@@ -0,0 +1,254 @@
+package cardquery
+
+import (
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// Query is a parsed scryfall search query
+type Query struct {
+	source string
+	root   node
+}
+
+// node is a part of a query which matches cards
+type node interface {
+	match(card *scryfall.Card) bool
+}
+
+type (
+	andNode []node
+	orNode  []node
+	notNode struct{ node }
+)
+
+func (n andNode) match(card *scryfall.Card) bool {
+	for _, child := range n {
+		if !child.match(card) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n orNode) match(card *scryfall.Card) bool {
+	for _, child := range n {
+		if child.match(card) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n notNode) match(card *scryfall.Card) bool {
+	return !n.node.match(card)
+}
+
+// Parse parses a query in the scryfall search syntax. Terms are combined
+// with "and" unless they are separated by "or", "-" negates a term, and
+// parentheses group terms. Words without a keyword match card names.
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: "unexpected closing parenthesis"}
+	}
+
+	if root == nil {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty query"}
+	}
+
+	return &Query{source: query, root: root}, nil
+}
+
+// String yields the query as it was parsed
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match tells if a card matches the query
+func (q *Query) Match(card *scryfall.Card) bool {
+	return q.root.match(card)
+}
+
+// Filter yields the cards which match the query
+func (q *Query) Filter(cards []scryfall.Card) []scryfall.Card {
+	result := make([]scryfall.Card, 0)
+
+	for idx := range cards {
+		if q.Match(&cards[idx]) {
+			result = append(result, cards[idx])
+		}
+	}
+
+	return result
+}
+
+// Name yields the name a query searches for, when it is nothing but words
+// without keywords. Such queries can be looked up with a name index.
+func (q *Query) Name() (string, bool) {
+	words := make([]string, 0)
+
+	var collect func(n node) bool
+	collect = func(n node) bool {
+		switch n := n.(type) {
+		case andNode:
+			for _, child := range n {
+				if !collect(child) {
+					return false
+				}
+			}
+
+			return true
+		case *nameTerm:
+			if n.exact {
+				return false
+			}
+
+			words = append(words, n.value)
+
+			return true
+		}
+
+		return false
+	}
+
+	if !collect(q.root) {
+		return "", false
+	}
+
+	return strings.Join(words, " "), true
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// or := and ("or" and)*
+func (p *parser) or() (node, error) {
+	var alternatives orNode
+
+	for {
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		if n == nil {
+			if len(alternatives) > 0 {
+				return nil, &SyntaxError{Pos: p.peek().pos, Msg: `missing term after "or"`}
+			}
+
+			return nil, nil
+		}
+
+		alternatives = append(alternatives, n)
+
+		if p.peek().kind != tokenOr {
+			break
+		}
+
+		p.advance()
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+
+	return alternatives, nil
+}
+
+// and := unary (["and"] unary)*
+func (p *parser) and() (node, error) {
+	var terms andNode
+
+	for {
+		switch p.peek().kind {
+		case tokenEOF, tokenClose, tokenOr:
+			switch len(terms) {
+			case 0:
+				return nil, nil
+			case 1:
+				return terms[0], nil
+			}
+
+			return terms, nil
+		case tokenAnd:
+			if len(terms) == 0 {
+				return nil, &SyntaxError{Pos: p.peek().pos, Msg: `missing term before "and"`}
+			}
+
+			p.advance()
+
+			continue
+		}
+
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		terms = append(terms, n)
+	}
+}
+
+// unary := "-" unary | "(" or ")" | term
+func (p *parser) unary() (node, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokenNot:
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{n}, nil
+	case tokenOpen:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if n == nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "empty parentheses"}
+		}
+
+		if p.advance().kind != tokenClose {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "unclosed parenthesis"}
+		}
+
+		return n, nil
+	case tokenTerm:
+		return newTerm(t)
+	}
+
+	return nil, &SyntaxError{Pos: t.pos, Msg: "missing term"}
+}
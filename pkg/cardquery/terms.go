@@ -0,0 +1,459 @@
+package cardquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// keywords and their aliases, like scryfall
+var keywordAliases = map[string]string{
+	"c": "c", "color": "c",
+	"id": "id", "identity": "id", "ci": "id",
+	"t": "t", "type": "t",
+	"o": "o", "oracle": "o", "name": "name",
+	"cmc": "mv", "mv": "mv", "manavalue": "mv",
+	"pow": "pow", "power": "pow",
+	"tou": "tou", "toughness": "tou",
+	"loy": "loy", "loyalty": "loy",
+	"r": "r", "rarity": "r",
+	"s": "s", "set": "s", "e": "s", "edition": "s",
+	"cn": "cn", "number": "cn",
+	"f": "f", "format": "f", "legal": "f",
+	"banned": "banned", "restricted": "restricted",
+	"is": "is", "not": "not",
+	"m": "m", "mana": "m",
+	"a": "a", "artist": "a",
+	"kw": "kw", "keyword": "kw",
+	"lang": "lang", "language": "lang",
+	"usd": "usd", "eur": "eur", "tix": "tix",
+}
+
+func newTerm(t token) (node, error) {
+	if t.keyword == "" {
+		return newNameTerm(t)
+	}
+
+	keyword, found := keywordAliases[t.keyword]
+	if !found {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown keyword %q", t.keyword)}
+	}
+
+	if t.regex && keyword != "o" && keyword != "t" && keyword != "name" && keyword != "a" {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("%q does not take regular expressions", t.keyword)}
+	}
+
+	var (
+		n   node
+		err error
+	)
+
+	switch keyword {
+	case "c", "id":
+		n, err = newColorTerm(keyword == "id", t.op, t.value)
+	case "t":
+		n, err = newTextTerm(t, typeLines)
+	case "o":
+		n, err = newTextTerm(t, oracleTexts)
+	case "name":
+		n, err = newTextTerm(t, names)
+	case "a":
+		n, err = newTextTerm(t, artists)
+	case "mv", "pow", "tou", "loy", "usd", "eur", "tix":
+		n, err = newNumberTerm(keyword, t.op, t.value)
+	case "r":
+		n, err = newRarityTerm(t.op, t.value)
+	case "s":
+		n, err = newEqualTerm(t, func(c *scryfall.Card) []string { return []string{c.Set} })
+	case "cn":
+		n, err = newEqualTerm(t, func(c *scryfall.Card) []string { return []string{c.CollectorNumber} })
+	case "lang":
+		n, err = newEqualTerm(t, func(c *scryfall.Card) []string { return []string{string(c.Lang)} })
+	case "kw":
+		n, err = newEqualTerm(t, func(c *scryfall.Card) []string { return c.Keywords })
+	case "m":
+		n, err = newManaTerm(t.op, t.value)
+	case "f":
+		n, err = newLegalityTerm(t.value, scryfall.LegalityLegal, scryfall.LegalityRestricted)
+	case "banned":
+		n, err = newLegalityTerm(t.value, scryfall.LegalityBanned)
+	case "restricted":
+		n, err = newLegalityTerm(t.value, scryfall.LegalityRestricted)
+	case "is", "not":
+		n, err = newIsTerm(t.value)
+		if err == nil && keyword == "not" {
+			n = notNode{n}
+		}
+	}
+
+	if err != nil {
+		return nil, &SyntaxError{Pos: t.pos, Msg: err.Error()}
+	}
+
+	return n, nil
+}
+
+// requireOp rejects comparisons on keywords which only take ":" or "="
+func requireOp(op string) error {
+	if op != ":" && op != "=" {
+		return fmt.Errorf("operator %q can not be used here", op)
+	}
+
+	return nil
+}
+
+// compare compares two numbers with an operator, ":" is "="
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case ":", "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+
+	return false
+}
+
+// nameTerm matches words of card names, or exact names
+type nameTerm struct {
+	value string
+	exact bool
+}
+
+func newNameTerm(t token) (node, error) {
+	return &nameTerm{value: strings.ToLower(t.value), exact: t.exact}, nil
+}
+
+func (n *nameTerm) match(card *scryfall.Card) bool {
+	for _, name := range names(card) {
+		name = strings.ToLower(name)
+
+		if n.exact && name == n.value || !n.exact && strings.Contains(name, n.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// textTerm matches text fields, like the type line or the oracle text
+type textTerm struct {
+	field func(card *scryfall.Card) []string
+	value string
+	regex *regexp.Regexp
+
+	// in oracle text, "~" stands for the name of the card
+	replaceName bool
+}
+
+func newTextTerm(t token, field func(card *scryfall.Card) []string) (node, error) {
+	if err := requireOp(t.op); err != nil {
+		return nil, err
+	}
+
+	n := &textTerm{field: field, value: strings.ToLower(t.value), replaceName: t.keyword == "o" || t.keyword == "oracle"}
+
+	if t.regex {
+		re, err := regexp.Compile("(?i)" + t.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+
+		n.regex = re
+	}
+
+	return n, nil
+}
+
+func (n *textTerm) match(card *scryfall.Card) bool {
+	value := n.value
+	if n.replaceName && strings.Contains(value, "~") {
+		value = strings.ReplaceAll(value, "~", strings.ToLower(names(card)[0]))
+	}
+
+	for _, text := range n.field(card) {
+		if n.regex != nil {
+			if n.regex.MatchString(text) {
+				return true
+			}
+
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(text), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalTerm matches fields which equal a value, like set codes
+type equalTerm struct {
+	field func(card *scryfall.Card) []string
+	value string
+}
+
+func newEqualTerm(t token, field func(card *scryfall.Card) []string) (node, error) {
+	if err := requireOp(t.op); err != nil {
+		return nil, err
+	}
+
+	return &equalTerm{field: field, value: t.value}, nil
+}
+
+func (n *equalTerm) match(card *scryfall.Card) bool {
+	for _, value := range n.field(card) {
+		if strings.EqualFold(value, n.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// numberTerm compares numbers, like the mana value or the power of a card.
+// Cards with several faces match if any face does.
+type numberTerm struct {
+	field  func(card *scryfall.Card) []float64
+	op     string
+	value  float64
+	parity string // even or odd
+}
+
+func newNumberTerm(keyword, op, value string) (node, error) {
+	n := &numberTerm{op: op}
+
+	switch keyword {
+	case "mv":
+		n.field = func(c *scryfall.Card) []float64 { return []float64{c.CMC} }
+	case "pow":
+		n.field = func(c *scryfall.Card) []float64 { return stats(c, func(f face) *string { return f.power }) }
+	case "tou":
+		n.field = func(c *scryfall.Card) []float64 { return stats(c, func(f face) *string { return f.toughness }) }
+	case "loy":
+		n.field = func(c *scryfall.Card) []float64 { return stats(c, func(f face) *string { return f.loyalty }) }
+	case "usd", "eur", "tix":
+		n.field = func(c *scryfall.Card) []float64 { return price(c, keyword) }
+	}
+
+	if keyword == "mv" && (value == "even" || value == "odd") {
+		if err := requireOp(op); err != nil {
+			return nil, err
+		}
+
+		n.parity = value
+
+		return n, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+
+	n.value = number
+
+	return n, nil
+}
+
+func (n *numberTerm) match(card *scryfall.Card) bool {
+	for _, v := range n.field(card) {
+		switch n.parity {
+		case "even":
+			if v == float64(int(v)) && int(v)%2 == 0 {
+				return true
+			}
+		case "odd":
+			if v == float64(int(v)) && int(v)%2 == 1 {
+				return true
+			}
+		default:
+			if compare(v, n.op, n.value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rarities, from lowest to highest
+var rarityRanks = map[string]int{
+	"common": 0, "c": 0,
+	"uncommon": 1, "u": 1,
+	"rare": 2, "r": 2,
+	"special": 3, "s": 3,
+	"mythic": 4, "m": 4,
+	"bonus": 5, "b": 5,
+}
+
+type rarityTerm struct {
+	op   string
+	rank int
+}
+
+func newRarityTerm(op, value string) (node, error) {
+	rank, found := rarityRanks[strings.ToLower(value)]
+	if !found {
+		return nil, fmt.Errorf("unknown rarity %q", value)
+	}
+
+	return &rarityTerm{op: op, rank: rank}, nil
+}
+
+func (n *rarityTerm) match(card *scryfall.Card) bool {
+	rank, found := rarityRanks[card.Rarity]
+	if !found {
+		return false
+	}
+
+	return compare(float64(rank), n.op, float64(n.rank))
+}
+
+// manaTerm matches mana costs which contain the symbols of a value, like
+// "m:{R}{R}" or "m:rr"
+type manaTerm struct {
+	symbols map[string]int
+	op      string
+}
+
+func newManaTerm(op, value string) (node, error) {
+	if op != ":" && op != "=" && op != ">=" {
+		return nil, fmt.Errorf("operator %q can not be used for mana costs", op)
+	}
+
+	symbols := manaSymbols(value)
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("invalid mana cost %q", value)
+	}
+
+	return &manaTerm{symbols: symbols, op: op}, nil
+}
+
+func (n *manaTerm) match(card *scryfall.Card) bool {
+	for _, f := range faces(card) {
+		cost := manaSymbols(f.manaCost)
+
+		matched := true
+
+		for symbol, count := range n.symbols {
+			if cost[symbol] < count {
+				matched = false
+				break
+			}
+		}
+
+		if matched && n.op == "=" && len(cost) != len(n.symbols) {
+			matched = false
+		}
+
+		if matched && n.op == "=" {
+			for symbol, count := range cost {
+				if n.symbols[symbol] != count {
+					matched = false
+				}
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// manaSymbols counts the symbols of a mana cost. Symbols are either in
+// braces like "{10}" and "{2/W}", or written without braces like "10rr",
+// where a generic cost is a number and every other symbol is a character.
+func manaSymbols(cost string) map[string]int {
+	symbols := make(map[string]int)
+	cost = strings.ToUpper(cost)
+
+	for idx := 0; idx < len(cost); idx++ {
+		if isDigit(cost[idx]) {
+			end := idx + 1
+			for end < len(cost) && isDigit(cost[end]) {
+				end++
+			}
+
+			symbols[cost[idx:end]]++
+			idx = end - 1
+
+			continue
+		}
+
+		if cost[idx] != '{' {
+			symbols[string(cost[idx])]++
+			continue
+		}
+
+		end := strings.IndexByte(cost[idx:], '}')
+		if end < 0 {
+			return nil
+		}
+
+		symbols[cost[idx+1:idx+end]]++
+		idx += end
+	}
+
+	return symbols
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// legalityTerm matches cards with a legality in a format
+type legalityTerm struct {
+	format  string
+	allowed []scryfall.Legality
+}
+
+func newLegalityTerm(format string, allowed ...scryfall.Legality) (node, error) {
+	if _, found := legality(&scryfall.Card{}, strings.ToLower(format)); !found {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	return &legalityTerm{format: strings.ToLower(format), allowed: allowed}, nil
+}
+
+func (n *legalityTerm) match(card *scryfall.Card) bool {
+	l, _ := legality(card, n.format)
+
+	for _, allowed := range n.allowed {
+		if l == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTerm matches cards with a property, like "is:commander"
+type isTerm func(card *scryfall.Card) bool
+
+func (n isTerm) match(card *scryfall.Card) bool {
+	return n(card)
+}
+
+func newIsTerm(value string) (node, error) {
+	is, found := properties[strings.ToLower(value)]
+	if !found {
+		return nil, fmt.Errorf("unknown property %q", value)
+	}
+
+	return isTerm(is), nil
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps fetched catalogs as json files in a directory:
+// <dir>/<catalog>.json
+type Cache struct {
+	dir string
+}
+
+// List is a cached catalog, and when it was fetched
+type List struct {
+	Fetched time.Time `json:"fetched"`
+	Values  []string  `json:"values"`
+}
+
+// Stale yields whether a list is older than a refresh interval
+func (l List) Stale(interval time.Duration, now time.Time) bool {
+	return l.Fetched.IsZero() || now.Sub(l.Fetched) > interval
+}
+
+// NewCache creates a cache in a directory, which is created when a catalog
+// is saved
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Load reads a cached catalog, a catalog which was never saved yields
+// os.ErrNotExist
+func (c *Cache) Load(name string) (List, error) {
+	var list List
+
+	data, err := os.ReadFile(c.path(name))
+	if err != nil {
+		return List{}, err
+	}
+
+	if err = json.Unmarshal(data, &list); err != nil {
+		return List{}, fmt.Errorf("decoding cached %s: %v", name, err)
+	}
+
+	return list, nil
+}
+
+// Save writes a catalog
+func (c *Cache) Save(name string, list List) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %v", name, err)
+	}
+
+	if err = os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("creating catalog directory: %v", err)
+	}
+
+	if err = os.WriteFile(c.path(name), data, 0644); err != nil {
+		return fmt.Errorf("writing cached %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func (c *Cache) path(name string) string {
+	return filepath.Join(c.dir, name+".json")
+}
@@ -0,0 +1,121 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// the names of the catalogs
+const (
+	Types      = "types"
+	Subtypes   = "subtypes"
+	Supertypes = "supertypes"
+	Formats    = "formats"
+	Sets       = "sets"
+	Keywords   = "keywords"
+)
+
+// Names is the name of every catalog
+var Names = []string{Types, Subtypes, Supertypes, Formats, Sets, Keywords}
+
+var (
+	ErrUnknownCatalog = errors.New("unknown catalog")
+	ErrUnknownSubtype = errors.New("unknown subtype")
+	ErrUnknownType    = errors.New("unknown type")
+)
+
+// the catalogs as they were when this was written, used when the catalogs
+// can not be fetched and were never cached
+//
+//go:embed snapshot.json
+var snapshotJSON []byte
+
+var (
+	snapshotOnce sync.Once
+	snapshot     *Catalog
+)
+
+// Snapshot yields the catalogs embedded in the program, which do not know
+// about the sets and cards printed after it was built
+func Snapshot() *Catalog {
+	snapshotOnce.Do(func() {
+		lists := make(map[string][]string)
+
+		// the snapshot is checked by the tests
+		_ = json.Unmarshal(snapshotJSON, &lists)
+
+		snapshot = New(lists)
+	})
+
+	return snapshot
+}
+
+// Catalog is the lists of the card types, subtypes, supertypes, formats,
+// set codes and keywords. Values are looked up regardless of case.
+type Catalog struct {
+	lists map[string][]string
+
+	// the values of each list, by their lower case
+	index map[string]map[string]string
+}
+
+// New creates a catalog of lists, by catalog name
+func New(lists map[string][]string) *Catalog {
+	c := &Catalog{
+		lists: make(map[string][]string),
+		index: make(map[string]map[string]string),
+	}
+
+	for name, values := range lists {
+		c.lists[name] = append([]string(nil), values...)
+		sort.Strings(c.lists[name])
+
+		c.index[name] = make(map[string]string, len(values))
+		for _, value := range values {
+			c.index[name][strings.ToLower(value)] = value
+		}
+	}
+
+	return c
+}
+
+// List yields the values of a catalog, sorted
+func (c *Catalog) List(name string) []string {
+	return append([]string(nil), c.lists[name]...)
+}
+
+// Has yields whether a catalog has a value, regardless of case
+func (c *Catalog) Has(name, value string) bool {
+	_, found := c.Canonical(name, value)
+	return found
+}
+
+// Canonical yields a value as it is written in a catalog, like "Goblin" for
+// "goblin"
+func (c *Catalog) Canonical(name, value string) (string, bool) {
+	canonical, found := c.index[name][strings.ToLower(strings.TrimSpace(value))]
+	return canonical, found
+}
+
+// ValidateSubtypes yields an ErrUnknownSubtype naming every subtype which
+// is not in the catalog
+func (c *Catalog) ValidateSubtypes(subtypes ...string) error {
+	unknown := make([]string, 0)
+
+	for _, subtype := range subtypes {
+		if !c.Has(Subtypes, subtype) {
+			unknown = append(unknown, subtype)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownSubtype, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/models"
+)
+
+func TestSnapshot(t *testing.T) {
+	lists := make(map[string][]string)
+	if err := json.Unmarshal(snapshotJSON, &lists); err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+
+	for _, name := range Names {
+		if len(lists[name]) == 0 {
+			t.Fatalf("the snapshot has no %s", name)
+		}
+	}
+
+	c := Snapshot()
+
+	if !c.Has(Subtypes, "goblin") || !c.Has(Sets, "m10") || !c.Has(Keywords, "flying") {
+		t.Fatal("expected the snapshot to know goblins, magic 2010 and flying")
+	}
+
+	if err := c.ValidateSubtypes("Goblin", "Time Lord", "Wizzard"); !errors.Is(err, ErrUnknownSubtype) || err.Error() != "unknown subtype: Wizzard" {
+		t.Fatalf("expected only the wizzard to be unknown, got %v", err)
+	}
+}
+
+func TestParseTypeLine(t *testing.T) {
+	c := Snapshot()
+
+	tests := []struct {
+		line, parsed string
+		superType    models.CardSuperType
+		permanent    bool
+	}{
+		{"Legendary Creature — Goblin Warrior", "Legendary Creature — Goblin Warrior", models.Creature, true},
+		{"artifact creature - construct", "Artifact Creature — Construct", models.Creature, true},
+		{"Basic Snow Land — Forest", "Basic Snow Land — Forest", models.Land, true},
+		{"Creature — Time Lord Doctor", "Creature — Time Lord Doctor", models.Creature, true},
+		{"Instant — Arcane", "Instant — Arcane", models.Instant, false},
+	}
+
+	for _, test := range tests {
+		typeLine, err := c.ParseTypeLine(test.line)
+		if err != nil {
+			t.Fatalf("%s: %v", test.line, err)
+		}
+
+		if typeLine.String() != test.parsed {
+			t.Fatalf("%s: expected %q, got %q", test.line, test.parsed, typeLine)
+		}
+
+		if superType, _ := typeLine.SuperType(); superType != test.superType || typeLine.IsPermanent() != test.permanent {
+			t.Fatalf("%s: unexpected card type %v", test.line, superType)
+		}
+	}
+
+	if _, err := c.ParseTypeLine("Creature — Goblin Wizzard"); !errors.Is(err, ErrUnknownSubtype) {
+		t.Fatalf("expected an unknown subtype, got %v", err)
+	}
+
+	if _, err := c.ParseTypeLine("Legendary Spaceship"); !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("expected an unknown type, got %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(t.TempDir() + "/catalog")
+
+	if _, err := cache.Load(Types); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no cached types, got %v", err)
+	}
+
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := cache.Save(Types, List{Fetched: now, Values: []string{"Creature"}}); err != nil {
+		t.Fatalf("saving types: %v", err)
+	}
+
+	list, err := cache.Load(Types)
+	if err != nil || len(list.Values) != 1 {
+		t.Fatalf("unexpected cached types %+v (%v)", list, err)
+	}
+
+	if list.Stale(time.Hour, now.Add(time.Minute)) || !list.Stale(time.Hour, now.Add(2*time.Hour)) {
+		t.Fatal("expected the types to be stale after an hour")
+	}
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/models"
+)
+
+// TypeLine is the type line of a card, like "Legendary Creature — Goblin
+// Warrior", split into its supertypes, types and subtypes
+type TypeLine struct {
+	Supertypes []string `json:"supertypes,omitempty"`
+	Types      []string `json:"types"`
+	Subtypes   []string `json:"subtypes,omitempty"`
+}
+
+// ParseTypeLine parses the type line of a card face, with the values written
+// as they are in the catalog. Unknown types and subtypes are errors, which
+// wrap ErrUnknownType and ErrUnknownSubtype.
+func (c *Catalog) ParseTypeLine(line string) (TypeLine, error) {
+	var t TypeLine
+
+	left, right, _ := strings.Cut(line, "—")
+	if !strings.Contains(line, "—") {
+		// type lines typed by hand tend to use a dash
+		left, right, _ = strings.Cut(line, " - ")
+	}
+
+	for _, word := range strings.Fields(left) {
+		if supertype, found := c.Canonical(Supertypes, word); found {
+			t.Supertypes = append(t.Supertypes, supertype)
+			continue
+		}
+
+		cardType, found := c.Canonical(Types, word)
+		if !found {
+			return TypeLine{}, fmt.Errorf("%w: %s", ErrUnknownType, word)
+		}
+
+		t.Types = append(t.Types, cardType)
+	}
+
+	if len(t.Types) == 0 {
+		return TypeLine{}, fmt.Errorf("%w: %q has no type", ErrUnknownType, line)
+	}
+
+	subtypes, err := c.subtypes(strings.Fields(right))
+	if err != nil {
+		return TypeLine{}, err
+	}
+
+	t.Subtypes = subtypes
+
+	return t, nil
+}
+
+// subtypes yields the subtypes of words, some of which take two words like
+// "Time Lord"
+func (c *Catalog) subtypes(words []string) ([]string, error) {
+	subtypes := make([]string, 0, len(words))
+	unknown := make([]string, 0)
+
+	for idx := 0; idx < len(words); idx++ {
+		if idx+1 < len(words) {
+			if subtype, found := c.Canonical(Subtypes, words[idx]+" "+words[idx+1]); found {
+				subtypes = append(subtypes, subtype)
+				idx++
+
+				continue
+			}
+		}
+
+		subtype, found := c.Canonical(Subtypes, words[idx])
+		if !found {
+			unknown = append(unknown, words[idx])
+			continue
+		}
+
+		subtypes = append(subtypes, subtype)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSubtype, strings.Join(unknown, ", "))
+	}
+
+	return subtypes, nil
+}
+
+// String writes the type line like it is printed on cards
+func (t TypeLine) String() string {
+	line := strings.Join(append(append([]string(nil), t.Supertypes...), t.Types...), " ")
+
+	if len(t.Subtypes) > 0 {
+		line += " — " + strings.Join(t.Subtypes, " ")
+	}
+
+	return line
+}
+
+// Has yields whether the type line has a type, like "Creature"
+func (t TypeLine) Has(cardType string) bool {
+	for _, candidate := range t.Types {
+		if strings.EqualFold(candidate, cardType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// superTypes are the card types of the game model, by the type they are
+// chosen for, in the order they are chosen
+var superTypes = []struct {
+	name      string
+	superType models.CardSuperType
+}{
+	{"Creature", models.Creature},
+	{"Artifact", models.Artifact},
+	{"Enchantment", models.Enchantment},
+	{"Land", models.Land},
+	{"Instant", models.Instant},
+	{"Sorcery", models.Sorcery},
+}
+
+// SuperType yields the card type of the game model for a type line, an
+// artifact creature is a creature. Types which the game does not model, like
+// planeswalkers, yield false.
+func (t TypeLine) SuperType() (models.CardSuperType, bool) {
+	for _, candidate := range superTypes {
+		if t.Has(candidate.name) {
+			return candidate.superType, true
+		}
+	}
+
+	return 0, false
+}
+
+// IsPermanent yields whether cards of the type line stay on the battlefield
+func (t TypeLine) IsPermanent() bool {
+	return !t.Has("Instant") && !t.Has("Sorcery")
+}
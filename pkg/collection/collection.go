@@ -0,0 +1,190 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// conditions, from best to worst
+const (
+	ConditionMint          = "mint"
+	ConditionNearMint      = "near_mint"
+	ConditionLightlyPlayed = "lightly_played"
+	ConditionModerate      = "moderately_played"
+	ConditionHeavilyPlayed = "heavily_played"
+	ConditionDamaged       = "damaged"
+)
+
+// Item is a stack of identical cards: same printing, finish, condition and
+// language. A printing is identified by set code and collector number, like
+// scryfall does.
+type Item struct {
+	Name            string `json:"name"`
+	Set             string `json:"set,omitempty"`
+	CollectorNumber string `json:"collectorNumber,omitempty"`
+	Quantity        int    `json:"quantity"`
+	Foil            bool   `json:"foil,omitempty"`
+	Etched          bool   `json:"etched,omitempty"`
+	Condition       string `json:"condition,omitempty"`
+	Language        string `json:"language,omitempty"`
+}
+
+// key identifies the stack an item belongs to
+func (i Item) key() string {
+	return strings.Join([]string{
+		strings.ToLower(i.Name),
+		strings.ToUpper(i.Set),
+		strings.ToLower(i.CollectorNumber),
+		fmt.Sprint(i.Foil, i.Etched),
+		i.Condition,
+		strings.ToLower(i.Language),
+	}, "|")
+}
+
+// Collection is every card someone owns, saved as a json file
+type Collection struct {
+	path  string
+	mux   sync.Mutex
+	items []Item
+}
+
+// Open loads a collection file. A file which does not exist yet is an empty
+// collection.
+func Open(path string) (*Collection, error) {
+	c := &Collection{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading collection: %v", err)
+	}
+
+	if err = json.Unmarshal(data, &c.items); err != nil {
+		return nil, fmt.Errorf("decoding collection: %v", err)
+	}
+
+	return c, nil
+}
+
+// Save writes the collection to its file
+func (c *Collection) Save() error {
+	c.mux.Lock()
+	data, err := json.MarshalIndent(c.items, "", "  ")
+	c.mux.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("encoding collection: %v", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("creating collection directory: %v", err)
+	}
+
+	if err = os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("writing collection: %v", err)
+	}
+
+	return nil
+}
+
+// Items yields every stack of cards, sorted by name
+func (c *Collection) Items() []Item {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	items := append([]Item(nil), c.items...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+// Add adds cards to the collection, identical cards are stacked
+func (c *Collection) Add(items ...Item) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, item := range items {
+		if item.Quantity < 1 {
+			continue
+		}
+
+		item.Set = strings.ToUpper(item.Set)
+
+		stacked := false
+
+		for idx := range c.items {
+			if c.items[idx].key() == item.key() {
+				c.items[idx].Quantity += item.Quantity
+				stacked = true
+
+				break
+			}
+		}
+
+		if !stacked {
+			c.items = append(c.items, item)
+		}
+	}
+}
+
+// Remove removes up to quantity cards of a stack, and yields how many were
+// removed
+func (c *Collection) Remove(item Item, quantity int) int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	item.Set = strings.ToUpper(item.Set)
+
+	for idx := range c.items {
+		if c.items[idx].key() != item.key() {
+			continue
+		}
+
+		removed := min(quantity, c.items[idx].Quantity)
+		c.items[idx].Quantity -= removed
+
+		if c.items[idx].Quantity == 0 {
+			c.items = append(c.items[:idx], c.items[idx+1:]...)
+		}
+
+		return removed
+	}
+
+	return 0
+}
+
+// Owned yields how many copies of a card are in the collection. If set is
+// not empty, only that printing counts.
+func (c *Collection) Owned(name, set, collectorNumber string) (count int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, item := range c.items {
+		if !strings.EqualFold(item.Name, name) {
+			continue
+		}
+
+		if set != "" && !strings.EqualFold(item.Set, set) {
+			continue
+		}
+
+		if collectorNumber != "" && !strings.EqualFold(item.CollectorNumber, collectorNumber) {
+			continue
+		}
+
+		count += item.Quantity
+	}
+
+	return count
+}
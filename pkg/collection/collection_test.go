@@ -0,0 +1,153 @@
+package collection
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func TestImportCSV(t *testing.T) {
+	tests := map[string]string{
+		"moxfield": `"Count","Tradelist Count","Name","Edition","Condition","Language","Foil","Tags","Last Modified","Collector Number","Alter","Proxy","Purchase Price"
+"2","0","Lightning Bolt","m10","Near Mint","English","foil","","2023-07-01 12:00:00.000000","146","False","False",""`,
+		"deckbox": `Count,Tradelist Count,Name,Edition,Edition Code,Card Number,Condition,Language,Foil,Signed,Artist Proof,Altered Art,Misprint,Promo,Textless,My Price
+2,0,Lightning Bolt,Magic 2010,M10,146,Near Mint,English,foil,,,,,,,`,
+		"archidekt": `Quantity,Name,Finish,Condition,Date Added,Language,Purchase Price,Tags,Edition Name,Edition Code,Multiverse Id,Scryfall ID,MTGO ID,Collector Number
+2,Lightning Bolt,Foil,NM,2023-07-01,English,,,Magic 2010,m10,191089,,,146`,
+	}
+
+	expected := []Item{{
+		Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146", Quantity: 2,
+		Foil: true, Condition: ConditionNearMint, Language: "English",
+	}}
+
+	for site, data := range tests {
+		items, err := ImportCSV(data, nil)
+		if err != nil {
+			t.Fatalf("%s: importing: %v", site, err)
+		}
+
+		if !reflect.DeepEqual(items, expected) {
+			t.Fatalf("%s: expected %+v, got %+v", site, expected, items)
+		}
+	}
+}
+
+func TestImportCSVSetNames(t *testing.T) {
+	// an older Deckbox export has set names, but no set codes
+	data := `Count,Name,Edition,Card Number
+1,Lightning Bolt,Limited Edition Alpha,161
+1,Shock,Mirage,
+1,Counterspell,Unknown Set,`
+
+	sets := func(codeOrName string) (string, bool) {
+		code, found := map[string]string{"limited edition alpha": "lea", "mirage": "mir"}[strings.ToLower(codeOrName)]
+		return code, found
+	}
+
+	items, err := ImportCSV(data, sets)
+	if err != nil {
+		t.Fatalf("importing: %v", err)
+	}
+
+	for idx, expected := range []string{"LEA", "MIR", ""} {
+		if items[idx].Set != expected {
+			t.Errorf("%s: expected set %q, got %q", items[idx].Name, expected, items[idx].Set)
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	items := []Item{
+		{Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146", Quantity: 2, Foil: true, Condition: ConditionNearMint},
+		{Name: "Delver of Secrets", Set: "ISD", CollectorNumber: "51", Quantity: 1, Etched: true, Language: "German"},
+		{Name: "Mountain, Forest", Quantity: 20},
+	}
+
+	var buf strings.Builder
+	if err := ExportCSV(&buf, items); err != nil {
+		t.Fatalf("exporting: %v", err)
+	}
+
+	imported, err := ImportCSV(buf.String(), nil)
+	if err != nil {
+		t.Fatalf("importing the export: %v", err)
+	}
+
+	if !reflect.DeepEqual(imported, items) {
+		t.Fatalf("expected %+v, got %+v", items, imported)
+	}
+}
+
+func TestImportCSVReportsRowErrors(t *testing.T) {
+	items, err := ImportCSV("Count,Name\n2,Lightning Bolt\nmany,Shock\n1,", nil)
+
+	var errs decklist.Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Line != 3 {
+		t.Fatalf("expected errors on lines 3 and 4, got %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("expected the valid row to be imported")
+	}
+}
+
+func TestMissingCards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collection.json")
+
+	c, err := Open(path)
+	if err != nil {
+		t.Fatalf("opening collection: %v", err)
+	}
+
+	c.Add(
+		Item{Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", Quantity: 2},
+		Item{Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146", Quantity: 1},
+		Item{Name: "Lightning Bolt", Set: "2XM", CollectorNumber: "129", Quantity: 1},
+		Item{Name: "Mountain", Quantity: 20},
+	)
+
+	if err = c.Save(); err != nil {
+		t.Fatalf("saving collection: %v", err)
+	}
+
+	if c, err = Open(path); err != nil {
+		t.Fatalf("reopening collection: %v", err)
+	}
+
+	if len(c.Items()) != 3 {
+		t.Fatalf("expected identical cards to stack, got %+v", c.Items())
+	}
+
+	// two printings of one card, which are needed on their own
+	burn := decklist.New()
+	burn.Add(decklist.SectionMain, decklist.Entry{Count: 3, Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146"})
+	burn.Add(decklist.SectionMain, decklist.Entry{Count: 2, Name: "Lightning Bolt", Set: "2XM", CollectorNumber: "129"})
+	burn.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Goblin Guide"})
+	burn.Add(decklist.SectionMain, decklist.Entry{Count: 20, Name: "Mountain"})
+
+	sligh := decklist.New()
+	sligh.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "goblin guide"})
+
+	others := map[string]*decklist.Deck{"burn": burn, "sligh": sligh}
+
+	expected := []MissingCard{
+		{Name: "Lightning Bolt", Needed: 5, Owned: 4, Missing: 1, UsedBy: []string{}},
+		{Name: "Goblin Guide", Needed: 4, Owned: 0, Missing: 4, UsedBy: []string{"sligh"}},
+	}
+
+	if missing := c.Missing(burn, false, others); !reflect.DeepEqual(missing, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, missing)
+	}
+
+	// the three m10 bolts are enough, the 2xm bolt is not
+	expected[0] = MissingCard{Name: "Lightning Bolt", Set: "2XM", CollectorNumber: "129", Needed: 2, Owned: 1, Missing: 1, UsedBy: []string{}}
+
+	if missing := c.Missing(burn, true, others); !reflect.DeepEqual(missing, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, missing)
+	}
+}
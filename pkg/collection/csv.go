@@ -0,0 +1,220 @@
+package collection
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// the column names used by the collection exports of Moxfield, Deckbox and
+// Archidekt, the first matching alias of a column wins
+var (
+	csvCountColumns     = []string{"count", "quantity", "qty"}
+	csvNameColumns      = []string{"name", "card name"}
+	csvSetCodeColumns   = []string{"edition code", "set code"}
+	csvSetColumns       = []string{"edition", "set", "edition name", "set name"}
+	csvCollectorColumns = []string{"collector number", "card number", "number"}
+	csvFoilColumns      = []string{"foil", "finish"}
+	csvConditionColumns = []string{"condition"}
+	csvLanguageColumns  = []string{"language", "lang"}
+)
+
+// SetLookup resolves the set column of an export, which holds a set code
+// like "MIR" or the name of a set like "Mirage", to a set code. It yields
+// false for sets it does not know.
+type SetLookup func(codeOrName string) (code string, found bool)
+
+// maxSetCodeLength tells set codes from set names when there is no
+// SetLookup, which is a guess: short set names pass for set codes
+const maxSetCodeLength = 6
+
+var conditions = map[string]string{
+	"mint": ConditionMint, "m": ConditionMint,
+	"near mint": ConditionNearMint, "nm": ConditionNearMint, "nm/m": ConditionNearMint,
+	"lightly played": ConditionLightlyPlayed, "lp": ConditionLightlyPlayed,
+	"good (lightly played)": ConditionLightlyPlayed, "slightly played": ConditionLightlyPlayed,
+	"sp": ConditionLightlyPlayed, "excellent": ConditionLightlyPlayed, "ex": ConditionLightlyPlayed,
+	"moderately played": ConditionModerate, "mp": ConditionModerate, "played": ConditionModerate,
+	"good": ConditionModerate, "gd": ConditionModerate,
+	"heavily played": ConditionHeavilyPlayed, "hp": ConditionHeavilyPlayed, "poor": ConditionHeavilyPlayed,
+	"damaged": ConditionDamaged, "dmg": ConditionDamaged,
+}
+
+// ImportCSV reads the csv export of a Moxfield, Deckbox or Archidekt
+// collection. Like deck lists, rows which can not be read are reported as
+// decklist.Errors, and every other row is imported.
+//
+// The set code column is read when the export has one. Otherwise the set
+// column holds a set code (Moxfield) or the name of a set (Deckbox), which
+// sets resolves to a set code. Cards of sets which it does not know have no
+// set. If sets is nil, short values without spaces are taken as set codes.
+func ImportCSV(data string, sets SetLookup) ([]Item, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, decklist.Errors{{Line: 1, Err: fmt.Errorf("reading csv header: %v", err)}}
+	}
+
+	find := func(aliases []string) int {
+		for _, alias := range aliases {
+			for idx, column := range header {
+				if strings.ToLower(strings.TrimSpace(column)) == alias {
+					return idx
+				}
+			}
+		}
+
+		return -1
+	}
+
+	cols := struct{ count, name, setCode, set, collector, foil, condition, language int }{
+		find(csvCountColumns), find(csvNameColumns), find(csvSetCodeColumns), find(csvSetColumns),
+		find(csvCollectorColumns), find(csvFoilColumns), find(csvConditionColumns), find(csvLanguageColumns),
+	}
+
+	if cols.name < 0 {
+		return nil, decklist.Errors{{Line: 1, Err: fmt.Errorf("%w: name", decklist.ErrMissingColumn)}}
+	}
+
+	var (
+		items []Item
+		errs  decklist.Errors
+	)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if err != nil {
+			errs = append(errs, &decklist.LineError{Line: line, Err: err})
+			continue
+		}
+
+		field := func(idx int) string {
+			if idx < 0 || idx >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[idx])
+		}
+
+		item := Item{
+			Name:            field(cols.name),
+			Quantity:        1,
+			CollectorNumber: field(cols.collector),
+			Condition:       normalizeCondition(field(cols.condition)),
+			Language:        field(cols.language),
+		}
+
+		item.Set = setCode(field(cols.setCode), field(cols.set), sets)
+
+		switch strings.ToLower(field(cols.foil)) {
+		case "foil", "true", "yes", "1":
+			item.Foil = true
+		case "etched":
+			item.Etched = true
+		}
+
+		if item.Name == "" {
+			errs = append(errs, &decklist.LineError{Line: line, Text: strings.Join(record, ","), Err: decklist.ErrMissingName})
+			continue
+		}
+
+		if count := field(cols.count); count != "" {
+			n, errAtoi := strconv.Atoi(count)
+			if errAtoi != nil || n < 1 {
+				errs = append(errs, &decklist.LineError{Line: line, Text: strings.Join(record, ","), Err: decklist.ErrInvalidCount})
+				continue
+			}
+
+			item.Quantity = n
+		}
+
+		items = append(items, item)
+	}
+
+	if len(errs) > 0 {
+		return items, errs
+	}
+
+	return items, nil
+}
+
+// setCode yields the set code of a row, from its set code column or else
+// from its set column
+func setCode(code, set string, sets SetLookup) string {
+	switch {
+	case code != "":
+		return strings.ToUpper(code)
+	case set == "":
+		return ""
+	case sets != nil:
+		code, _ = sets(set)
+		return strings.ToUpper(code)
+	case len(set) <= maxSetCodeLength && !strings.Contains(set, " "):
+		return strings.ToUpper(set)
+	}
+
+	return ""
+}
+
+// csvHeader is the header of exported collections, in the columns of
+// Moxfield which ImportCSV reads back
+var csvHeader = []string{"Count", "Name", "Edition", "Collector Number", "Foil", "Condition", "Language"}
+
+// ExportCSV writes items as a collection csv, which Moxfield and ImportCSV
+// can import
+func ExportCSV(w io.Writer, items []Item) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("writing csv header: %v", err)
+	}
+
+	for _, item := range items {
+		finish := ""
+
+		switch {
+		case item.Etched:
+			finish = "etched"
+		case item.Foil:
+			finish = "foil"
+		}
+
+		record := []string{
+			strconv.Itoa(item.Quantity), item.Name, strings.ToLower(item.Set), item.CollectorNumber,
+			finish, item.Condition, item.Language,
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("writing csv row: %v", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// normalizeCondition maps the condition names and abbreviations of the
+// different sites to the Condition constants. Unknown conditions are kept.
+func normalizeCondition(condition string) string {
+	if normalized, found := conditions[strings.ToLower(condition)]; found {
+		return normalized
+	}
+
+	return condition
+}
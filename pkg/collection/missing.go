@@ -0,0 +1,110 @@
+package collection
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// MissingCard is a card a deck needs more copies of than the collection has.
+// The printing is set when the deck names one and exact printings are asked
+// for.
+type MissingCard struct {
+	Name            string `json:"name"`
+	Set             string `json:"set,omitempty"`
+	CollectorNumber string `json:"collectorNumber,omitempty"`
+	Needed          int    `json:"needed"`
+	Owned           int    `json:"owned"`
+	Missing         int    `json:"missing"`
+
+	// UsedBy lists the other decks which use the card too
+	UsedBy []string `json:"usedBy,omitempty"`
+}
+
+// Missing yields the cards of a deck which are not in the collection, or
+// not in the needed quantity. The maybe board is left out. With exact
+// printings, every printing the deck names is needed on its own, and only
+// the copies of that printing count.
+//
+// Every other deck which uses a missing card is listed, decks are by name.
+func (c *Collection) Missing(deck *decklist.Deck, exactPrintings bool, others map[string]*decklist.Deck) []MissingCard {
+	needs := make(map[string]*MissingCard)
+	order := make([]string, 0)
+
+	for _, section := range decklist.Sections {
+		if section == decklist.SectionMaybe {
+			continue
+		}
+
+		for _, entry := range deck.Section(section) {
+			need := MissingCard{Name: entry.Name}
+
+			if exactPrintings && entry.Set != "" {
+				need.Set, need.CollectorNumber = strings.ToUpper(entry.Set), entry.CollectorNumber
+			}
+
+			key := strings.ToLower(need.Name) + "|" + need.Set + "|" + strings.ToLower(need.CollectorNumber)
+
+			n, found := needs[key]
+			if !found {
+				n = &need
+				needs[key] = n
+				order = append(order, key)
+			}
+
+			n.Needed += entry.Count
+		}
+	}
+
+	missing := make([]MissingCard, 0)
+
+	for _, key := range order {
+		n := needs[key]
+
+		if n.Owned = c.Owned(n.Name, n.Set, n.CollectorNumber); n.Owned >= n.Needed {
+			continue
+		}
+
+		n.Missing = n.Needed - n.Owned
+		n.UsedBy = usedBy(n.Name, deck, others)
+
+		missing = append(missing, *n)
+	}
+
+	return missing
+}
+
+func usedBy(name string, deck *decklist.Deck, others map[string]*decklist.Deck) []string {
+	decks := make([]string, 0)
+
+	for deckName, other := range others {
+		if other == deck {
+			continue
+		}
+
+		if uses(other, name) {
+			decks = append(decks, deckName)
+		}
+	}
+
+	sort.Strings(decks)
+
+	return decks
+}
+
+func uses(deck *decklist.Deck, name string) bool {
+	for _, section := range decklist.Sections {
+		if section == decklist.SectionMaybe {
+			continue
+		}
+
+		for _, entry := range deck.Section(section) {
+			if strings.EqualFold(entry.Name, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package deckhistory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// timeLayout names version files so that they sort by time
+const timeLayout = "2006-01-02T15-04-05.000000000Z"
+
+const versionExtension = ".txt"
+
+var ErrNoVersions = errors.New("deck has no versions")
+
+// Store keeps every version of a deck list, as a timestamped file in a
+// directory per deck: <root>/<slug>/<time>.txt
+type Store struct {
+	root string
+}
+
+// Version is a single stored version of a deck list
+type Version struct {
+	Slug string    `json:"slug"`
+	Time time.Time `json:"time"`
+	Path string    `json:"path"`
+}
+
+// ChangelogEntry is what changed in a deck, from one version to the next
+type ChangelogEntry struct {
+	From    Version          `json:"from"`
+	To      Version          `json:"to"`
+	Changes decklist.Changes `json:"changes"`
+}
+
+// New creates a store in the root directory
+func New(root string) *Store {
+	return &Store{root: root}
+}
+
+// Save stores a new version of a deck list. Nothing is stored if the list is
+// the same as the latest version, in which case the latest version is
+// returned and saved is false.
+func (s *Store) Save(slug, list string, at time.Time) (v Version, saved bool, err error) {
+	latest, err := s.Latest(slug)
+
+	switch {
+	case errors.Is(err, ErrNoVersions):
+	case err != nil:
+		return Version{}, false, err
+	default:
+		previous, errRead := os.ReadFile(latest.Path)
+		if errRead != nil {
+			return Version{}, false, fmt.Errorf("reading latest version: %v", errRead)
+		}
+
+		if string(previous) == list {
+			return latest, false, nil
+		}
+	}
+
+	dir := s.deckDirectory(slug)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return Version{}, false, fmt.Errorf("creating deck history directory: %v", err)
+	}
+
+	at = at.UTC()
+	v = Version{Slug: slug, Time: at, Path: filepath.Join(dir, at.Format(timeLayout)+versionExtension)}
+
+	if err = os.WriteFile(v.Path, []byte(list), 0644); err != nil {
+		return Version{}, false, fmt.Errorf("writing deck version: %v", err)
+	}
+
+	return v, true, nil
+}
+
+// Decks yields the slugs of every deck with a history
+func (s *Store) Decks() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading deck history directory: %v", err)
+	}
+
+	slugs := make([]string, 0)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			slugs = append(slugs, entry.Name())
+		}
+	}
+
+	return slugs, nil
+}
+
+// Versions yields every version of a deck, oldest first
+func (s *Store) Versions(slug string) ([]Version, error) {
+	entries, err := os.ReadDir(s.deckDirectory(slug))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNoVersions, slug)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading deck history: %v", err)
+	}
+
+	versions := make([]Version, 0)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, versionExtension) {
+			continue
+		}
+
+		at, errParse := time.Parse(timeLayout, strings.TrimSuffix(name, versionExtension))
+		if errParse != nil {
+			continue // not a version file
+		}
+
+		versions = append(versions, Version{
+			Slug: slug,
+			Time: at,
+			Path: filepath.Join(s.deckDirectory(slug), name),
+		})
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoVersions, slug)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Time.Before(versions[j].Time)
+	})
+
+	return versions, nil
+}
+
+// Latest yields the newest version of a deck
+func (s *Store) Latest(slug string) (Version, error) {
+	versions, err := s.Versions(slug)
+	if err != nil {
+		return Version{}, err
+	}
+
+	return versions[len(versions)-1], nil
+}
+
+// Load parses a version of a deck. Lines which can not be parsed are left
+// out, like with decklist.Parse.
+func (s *Store) Load(v Version) (*decklist.Deck, error) {
+	data, err := os.ReadFile(v.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading deck version: %v", err)
+	}
+
+	deck, err := decklist.Parse(string(data))
+
+	var lineErrors decklist.Errors
+	if errors.As(err, &lineErrors) {
+		return deck, nil
+	}
+
+	return deck, err
+}
+
+// Diff yields the changes between two versions of a deck
+func (s *Store) Diff(from, to Version) (decklist.Changes, error) {
+	before, err := s.Load(from)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.Load(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return decklist.Diff(before, after), nil
+}
+
+// Changelog yields the changes between every pair of consecutive versions of
+// a deck, oldest first
+func (s *Store) Changelog(slug string) ([]ChangelogEntry, error) {
+	versions, err := s.Versions(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	log := make([]ChangelogEntry, 0, len(versions)-1)
+
+	for idx := 1; idx < len(versions); idx++ {
+		changes, errDiff := s.Diff(versions[idx-1], versions[idx])
+		if errDiff != nil {
+			return nil, errDiff
+		}
+
+		log = append(log, ChangelogEntry{From: versions[idx-1], To: versions[idx], Changes: changes})
+	}
+
+	return log, nil
+}
+
+func (s *Store) deckDirectory(slug string) string {
+	return filepath.Join(s.root, slug)
+}
@@ -0,0 +1,61 @@
+package deckhistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func TestStoreChangelog(t *testing.T) {
+	store := New(t.TempDir())
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	versions := []string{
+		"4 Lightning Bolt\n4 Shock\n20 Mountain\n\n2 Pyroblast",
+		"4 Lightning Bolt\n4 Shock\n20 Mountain\n\n2 Pyroblast", // unchanged
+		"4 Lightning Bolt\n2 Lava Spike\n21 Mountain\n\n3 Pyroblast",
+	}
+
+	for idx, list := range versions {
+		_, saved, err := store.Save("burn", list, start.Add(time.Duration(idx)*time.Hour))
+		if err != nil {
+			t.Fatalf("saving version %d: %v", idx, err)
+		}
+
+		if unchanged := idx == 1; saved == unchanged {
+			t.Fatalf("version %d: expected saved to be %v", idx, !unchanged)
+		}
+	}
+
+	log, err := store.Changelog("burn")
+	if err != nil {
+		t.Fatalf("getting changelog: %v", err)
+	}
+
+	if len(log) != 1 {
+		t.Fatalf("expected a single changelog entry, got %d", len(log))
+	}
+
+	expected := decklist.Changes{
+		{Kind: decklist.ChangeAdded, Section: decklist.SectionMain, Name: "Lava Spike", From: 0, To: 2},
+		{Kind: decklist.ChangeCount, Section: decklist.SectionMain, Name: "Mountain", From: 20, To: 21},
+		{Kind: decklist.ChangeRemoved, Section: decklist.SectionMain, Name: "Shock", From: 4, To: 0},
+		{Kind: decklist.ChangeCount, Section: decklist.SectionSideboard, Name: "Pyroblast", From: 2, To: 3},
+	}
+
+	changes := log[0].Changes
+	if len(changes) != len(expected) {
+		t.Fatalf("expected changes:\n%v\ngot:\n%v", expected, changes)
+	}
+
+	for idx := range expected {
+		if changes[idx] != expected[idx] {
+			t.Fatalf("expected changes:\n%v\ngot:\n%v", expected, changes)
+		}
+	}
+
+	if !log[0].To.Time.Equal(start.Add(2 * time.Hour)) {
+		t.Fatalf("unexpected version time %v", log[0].To.Time)
+	}
+}
@@ -0,0 +1,346 @@
+package decklibrary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/decksource"
+)
+
+const (
+	indexFileName = "library.json"
+	deckExtension = ".txt"
+)
+
+var ErrNotFound = errors.New("deck not in library")
+
+// unsafeID matches what does not belong in the file name of a deck
+var unsafeID = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// Entry is the metadata of a deck in the library
+type Entry struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Source  string    `json:"source,omitempty"`
+	URL     string    `json:"url,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	Format  string    `json:"format,omitempty"`
+	Tags    []string  `json:"tags,omitempty"`
+	Cards   int       `json:"cards"`
+	Fetched time.Time `json:"fetched"`
+
+	// Categories are the groups of cards of the source, like "Ramp"
+	Categories []decksource.Category `json:"categories,omitempty"`
+}
+
+// HasTag yields whether the deck has a tag, tags are not case-sensitive
+func (e Entry) HasTag(tag string) bool {
+	return containsFold(e.Tags, tag)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Library keeps decks with their metadata in a directory: the deck list of
+// every deck as <root>/<id>.txt in the MTG Arena format, and the metadata of
+// every deck in <root>/library.json
+type Library struct {
+	root    string
+	mux     sync.Mutex
+	entries map[string]*Entry
+}
+
+// Open loads the library in a directory. A directory which does not exist
+// yet is an empty library, it is created when a deck is saved.
+func Open(root string) (*Library, error) {
+	l := &Library{root: root, entries: make(map[string]*Entry)}
+
+	data, err := os.ReadFile(l.indexPath())
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading deck library: %v", err)
+	}
+
+	var entries []*Entry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decoding deck library: %v", err)
+	}
+
+	for _, entry := range entries {
+		l.entries[entry.ID] = entry
+	}
+
+	return l, nil
+}
+
+// ID yields the library ID of a deck of a source, like "moxfield-izzet-delver".
+// The IDs of some sites are case-sensitive, so only the source is lowercase.
+func ID(source, id string) string {
+	clean := func(s string) string {
+		return strings.Trim(unsafeID.ReplaceAllString(s, "-"), "-")
+	}
+
+	return strings.Trim(clean(strings.ToLower(source))+"-"+clean(id), "-")
+}
+
+// Put saves a fetched deck. The name and tags of a deck which is already in
+// the library are kept, so that fetching it again only updates its cards.
+func (l *Library) Put(deck *decksource.Deck, at time.Time) (Entry, error) {
+	list, err := decklist.Export(decklist.FormatArena, deck.Deck)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	id := ID(deck.Source, deck.ID)
+
+	if err = os.MkdirAll(l.root, 0755); err != nil {
+		return Entry{}, fmt.Errorf("creating deck library directory: %v", err)
+	}
+
+	if err = os.WriteFile(l.deckPath(id), []byte(list), 0644); err != nil {
+		return Entry{}, fmt.Errorf("writing deck list: %v", err)
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry, found := l.entries[id]
+	if !found {
+		entry = &Entry{ID: id, Name: deck.Name}
+		if entry.Name == "" {
+			entry.Name = deck.ID
+		}
+
+		l.entries[id] = entry
+	}
+
+	entry.Source, entry.URL = deck.Source, deck.URL
+	entry.Author, entry.Format = deck.Author, deck.Format
+	entry.Categories = deck.Categories
+	entry.Cards = deck.Count(decklist.SectionCommander) + deck.Count(decklist.SectionMain)
+	entry.Fetched = at.UTC()
+
+	return *entry, l.save()
+}
+
+// Entries yields every deck, by name
+func (l *Library) Entries() []Entry {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !strings.EqualFold(entries[i].Name, entries[j].Name) {
+			return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+		}
+
+		return entries[i].ID < entries[j].ID
+	})
+
+	return entries
+}
+
+// Entry yields the metadata of a deck
+func (l *Library) Entry(id string) (Entry, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry, found := l.entries[id]
+	if !found {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return *entry, nil
+}
+
+// Deck loads the deck list of a deck
+func (l *Library) Deck(id string) (*decklist.Deck, error) {
+	entry, err := l.Entry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(l.deckPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("reading deck list: %v", err)
+	}
+
+	deck, err := decklist.ParseFormat(decklist.FormatArena, string(data))
+	if deck == nil {
+		return nil, err
+	}
+
+	deck.Name = entry.Name
+
+	return deck, err
+}
+
+// Search yields the decks with a card and a tag, by name. The card is
+// matched by a part of its name and an empty card or tag matches every deck.
+func (l *Library) Search(card, tag string) ([]Entry, error) {
+	card = strings.ToLower(strings.TrimSpace(card))
+
+	found := make([]Entry, 0)
+
+	for _, entry := range l.Entries() {
+		if tag != "" && !entry.HasTag(tag) {
+			continue
+		}
+
+		if card != "" {
+			deck, err := l.Deck(entry.ID)
+			if deck == nil {
+				return nil, err
+			}
+
+			if !hasCard(deck, card) {
+				continue
+			}
+		}
+
+		found = append(found, entry)
+	}
+
+	return found, nil
+}
+
+func hasCard(deck *decklist.Deck, card string) bool {
+	for _, entry := range deck.Entries() {
+		if strings.Contains(strings.ToLower(entry.Name), card) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tag adds tags to a deck
+func (l *Library) Tag(id string, tags ...string) error {
+	return l.update(id, func(entry *Entry) {
+		for _, tag := range tags {
+			tag = strings.TrimSpace(tag)
+			if tag != "" && !entry.HasTag(tag) {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+
+		sort.Strings(entry.Tags)
+	})
+}
+
+// Untag removes tags from a deck
+func (l *Library) Untag(id string, tags ...string) error {
+	return l.update(id, func(entry *Entry) {
+		kept := make([]string, 0, len(entry.Tags))
+
+		for _, t := range entry.Tags {
+			if !containsFold(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+
+		entry.Tags = kept
+	})
+}
+
+// Rename renames a deck, its ID stays the same
+func (l *Library) Rename(id, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("renaming %s: the name is empty", id)
+	}
+
+	return l.update(id, func(entry *Entry) {
+		entry.Name = name
+	})
+}
+
+// Delete removes a deck and its deck list
+func (l *Library) Delete(id string) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if _, found := l.entries[id]; !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	if err := os.Remove(l.deckPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("deleting deck list: %v", err)
+	}
+
+	delete(l.entries, id)
+
+	return l.save()
+}
+
+func (l *Library) update(id string, fn func(entry *Entry)) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry, found := l.entries[id]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	fn(entry)
+
+	return l.save()
+}
+
+// save writes the index, the lock must be held
+func (l *Library) save() error {
+	entries := make([]*Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding deck library: %v", err)
+	}
+
+	if err = os.MkdirAll(l.root, 0755); err != nil {
+		return fmt.Errorf("creating deck library directory: %v", err)
+	}
+
+	if err = os.WriteFile(l.indexPath(), data, 0644); err != nil {
+		return fmt.Errorf("writing deck library: %v", err)
+	}
+
+	return nil
+}
+
+func (l *Library) indexPath() string {
+	return filepath.Join(l.root, indexFileName)
+}
+
+func (l *Library) deckPath(id string) string {
+	return filepath.Join(l.root, id+deckExtension)
+}
@@ -0,0 +1,134 @@
+package decklibrary
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/decksource"
+)
+
+func newDeck(source, id, name string, cards ...string) *decksource.Deck {
+	deck := &decksource.Deck{Deck: decklist.New(), Source: source, ID: id, Format: "modern"}
+	deck.Name = name
+
+	for _, card := range cards {
+		deck.Add(decklist.SectionMain, decklist.Entry{Name: card, Count: 4, Set: "M10", CollectorNumber: "146"})
+	}
+
+	return deck
+}
+
+func TestLibrary(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "decks")
+
+	library, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	delver, err := library.Put(newDeck("Moxfield", "izzet-delver", "Izzet Delver", "Lightning Bolt", "Delver of Secrets"), at)
+	if err != nil {
+		t.Fatalf("saving deck: %v", err)
+	}
+
+	if delver.ID != "moxfield-izzet-delver" || delver.Cards != 8 || !delver.Fetched.Equal(at) {
+		t.Fatalf("unexpected entry %+v", delver)
+	}
+
+	burn := newDeck("TappedOut", "burn", "Burn", "Lightning Bolt", "Goblin Guide")
+	burn.Categories = []decksource.Category{
+		{Name: "Creature", Section: decklist.SectionMain, Entries: burn.Section(decklist.SectionMain)[1:]},
+		{Name: "Instant", Section: decklist.SectionMain, Entries: burn.Section(decklist.SectionMain)[:1]},
+	}
+
+	if _, err = library.Put(burn, at); err != nil {
+		t.Fatalf("saving deck: %v", err)
+	}
+
+	if err = library.Tag(delver.ID, "Tempo", "modern", "tempo"); err != nil {
+		t.Fatalf("tagging deck: %v", err)
+	}
+
+	if err = library.Rename(delver.ID, "My Delver"); err != nil {
+		t.Fatalf("renaming deck: %v", err)
+	}
+
+	// fetching a deck again keeps its name and tags
+	if delver, err = library.Put(newDeck("Moxfield", "izzet-delver", "Izzet Delver", "Lightning Bolt"), at.Add(time.Hour)); err != nil {
+		t.Fatalf("saving deck again: %v", err)
+	}
+
+	if delver.Name != "My Delver" || len(delver.Tags) != 2 || delver.Cards != 4 {
+		t.Fatalf("unexpected entry after fetching again %+v", delver)
+	}
+
+	// the library is read back from its directory
+	if library, err = Open(dir); err != nil {
+		t.Fatalf("opening library: %v", err)
+	}
+
+	if entries := library.Entries(); len(entries) != 2 || entries[0].Name != "Burn" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	if entry, _ := library.Entry("tappedout-burn"); len(entry.Categories) != 2 || entry.Categories[0].Entries[0].Name != "Goblin Guide" {
+		t.Fatalf("expected the categories of the source, got %+v", entry.Categories)
+	}
+
+	if found, _ := library.Search("bolt", ""); len(found) != 2 {
+		t.Fatalf("expected both decks with a bolt, got %+v", found)
+	}
+
+	if found, _ := library.Search("bolt", "TEMPO"); len(found) != 1 || found[0].ID != delver.ID {
+		t.Fatalf("expected the tempo deck with a bolt, got %+v", found)
+	}
+
+	if found, _ := library.Search("goblin", "tempo"); len(found) != 0 {
+		t.Fatalf("expected no tempo deck with a goblin, got %+v", found)
+	}
+
+	deck, err := library.Deck(delver.ID)
+	if err != nil || deck.Name != "My Delver" || deck.Section(decklist.SectionMain)[0].Set != "M10" {
+		t.Fatalf("unexpected deck %+v (%v)", deck, err)
+	}
+
+	if err = library.Untag(delver.ID, "Modern"); err != nil {
+		t.Fatalf("untagging deck: %v", err)
+	}
+
+	if entry, _ := library.Entry(delver.ID); len(entry.Tags) != 1 || entry.Tags[0] != "Tempo" {
+		t.Fatalf("unexpected tags %v", entry.Tags)
+	}
+
+	if err = library.Delete(delver.ID); err != nil {
+		t.Fatalf("deleting deck: %v", err)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, delver.ID+".txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the deck list to be deleted, got %v", err)
+	}
+
+	if err = library.Rename(delver.ID, "Gone"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a missing deck, got %v", err)
+	}
+}
+
+func TestID(t *testing.T) {
+	tests := map[[2]string]string{
+		{"Moxfield", "izzet-delver"}: "moxfield-izzet-delver",
+		{"Moxfield", "AbC_12"}:       "moxfield-AbC_12",
+		{"Local", "../Izzet Delver"}: "local-Izzet-Delver",
+	}
+
+	for args, expected := range tests {
+		if id := ID(args[0], args[1]); id != expected {
+			t.Errorf("%v: expected %q, got %q", args, expected, id)
+		}
+	}
+}
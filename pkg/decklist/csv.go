@@ -0,0 +1,152 @@
+package decklist
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the column names used by Moxfield, Archidekt and Deckstats, the first
+// matching alias of a column wins
+var (
+	csvCountColumns     = []string{"count", "quantity", "qty", "amount"}
+	csvNameColumns      = []string{"name", "card name", "card"}
+	csvSetColumns       = []string{"edition code", "set code", "edition", "set"}
+	csvCollectorColumns = []string{"collector number", "card number", "number"}
+	csvFoilColumns      = []string{"foil", "is foil", "finish"}
+	csvSectionColumns   = []string{"board", "section", "categories", "category"}
+)
+
+// columns is the index of each known column of a csv header, -1 if the
+// column is missing
+type columns struct {
+	count, name, set, collector, foil, section int
+}
+
+func newColumns(header []string) columns {
+	find := func(aliases []string) int {
+		for _, alias := range aliases {
+			for idx, column := range header {
+				if normalizeHeader(column) == alias {
+					return idx
+				}
+			}
+		}
+
+		return -1
+	}
+
+	return columns{
+		count:     find(csvCountColumns),
+		name:      find(csvNameColumns),
+		set:       find(csvSetColumns),
+		collector: find(csvCollectorColumns),
+		foil:      find(csvFoilColumns),
+		section:   find(csvSectionColumns),
+	}
+}
+
+// isDeck is true if the header has the columns a deck list needs
+func (c columns) isDeck() bool {
+	return c.count >= 0 && c.name >= 0
+}
+
+// parseCSV parses the csv exports of Moxfield, Archidekt and Deckstats.
+// Rows with an unknown board or category go to the main deck.
+func parseCSV(list string) (*Deck, error) {
+	deck := New()
+
+	var errs Errors
+
+	reader := csv.NewReader(strings.NewReader(list))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return deck, Errors{{Line: 1, Err: fmt.Errorf("reading csv header: %v", err)}}
+	}
+
+	cols := newColumns(header)
+	if cols.name < 0 {
+		return deck, Errors{{Line: 1, Err: fmt.Errorf("%w: name", ErrMissingColumn)}}
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+			continue
+		}
+
+		entry, section, err := cols.entry(record)
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Text: strings.Join(record, ","), Err: err})
+			continue
+		}
+
+		deck.Add(section, entry)
+	}
+
+	return deck, errs.orNil()
+}
+
+func (c columns) entry(record []string) (Entry, Section, error) {
+	field := func(idx int) string {
+		if idx < 0 || idx >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[idx])
+	}
+
+	entry := Entry{
+		Count:           1,
+		Name:            field(c.name),
+		Set:             strings.ToUpper(field(c.set)),
+		CollectorNumber: field(c.collector),
+	}
+
+	if entry.Name == "" {
+		return Entry{}, "", ErrMissingName
+	}
+
+	if count := field(c.count); count != "" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return Entry{}, "", ErrInvalidCount
+		}
+
+		entry.Count = n
+	}
+
+	switch strings.ToLower(field(c.foil)) {
+	case "foil", "true", "yes", "1":
+		entry.Foil = true
+	case "etched":
+		entry.Etched = true
+	}
+
+	section := SectionMain
+
+	// archidekt categories are a comma separated list, which can mix
+	// sections with custom categories like "Removal"
+	for _, name := range strings.Split(field(c.section), ",") {
+		if s, ok := parseSection(name); ok {
+			section = s
+			break
+		}
+	}
+
+	return entry, section, nil
+}
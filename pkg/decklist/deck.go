@@ -0,0 +1,97 @@
+package decklist
+
+// Section is a part of a deck, like the main deck or the sideboard
+type Section string
+
+const (
+	SectionMain      Section = "main"
+	SectionSideboard Section = "sideboard"
+	SectionCommander Section = "commander"
+	SectionCompanion Section = "companion"
+	SectionMaybe     Section = "maybe"
+)
+
+// Sections is every section, in the order they are usually listed
+var Sections = []Section{
+	SectionCommander,
+	SectionCompanion,
+	SectionMain,
+	SectionSideboard,
+	SectionMaybe,
+}
+
+// Entry is a single line of a deck list. Set and CollectorNumber are empty
+// if the list does not specify a printing.
+type Entry struct {
+	Count           int    `json:"count"`
+	Name            string `json:"name"`
+	Set             string `json:"set,omitempty"`
+	CollectorNumber string `json:"collectorNumber,omitempty"`
+	Foil            bool   `json:"foil,omitempty"`
+	Etched          bool   `json:"etched,omitempty"`
+}
+
+// Deck is a parsed deck list. The entries of each section are kept in the
+// order they were listed in.
+type Deck struct {
+	Name     string              `json:"name,omitempty"`
+	Sections map[Section][]Entry `json:"sections"`
+}
+
+// New creates an empty deck
+func New() *Deck {
+	return &Deck{Sections: make(map[Section][]Entry)}
+}
+
+// Add appends an entry to a section of the deck
+func (d *Deck) Add(section Section, e Entry) {
+	if d.Sections == nil {
+		d.Sections = make(map[Section][]Entry)
+	}
+
+	d.Sections[section] = append(d.Sections[section], e)
+}
+
+// Section yields the entries of a section
+func (d *Deck) Section(section Section) []Entry {
+	return d.Sections[section]
+}
+
+// Count yields the number of cards in a section
+func (d *Deck) Count(section Section) (count int) {
+	for _, e := range d.Sections[section] {
+		count += e.Count
+	}
+
+	return count
+}
+
+// Entries yields the entries of every section, in the order of Sections
+func (d *Deck) Entries() []Entry {
+	entries := make([]Entry, 0)
+
+	for _, section := range Sections {
+		entries = append(entries, d.Sections[section]...)
+	}
+
+	return entries
+}
+
+// parseSection resolves the many names which deck sites and clients use for
+// the sections of a deck, like "Sideboard", "SB" or "commanders"
+func parseSection(name string) (Section, bool) {
+	switch normalizeHeader(name) {
+	case "deck", "main", "maindeck", "mainboard", "main deck":
+		return SectionMain, true
+	case "sideboard", "side", "sb":
+		return SectionSideboard, true
+	case "commander", "commanders":
+		return SectionCommander, true
+	case "companion", "companions":
+		return SectionCompanion, true
+	case "maybe", "maybeboard", "considering":
+		return SectionMaybe, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,126 @@
+package decklist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind tells how a card changed between two versions of a deck
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeCount   ChangeKind = "changed"
+)
+
+// Change is a card which was added, removed or changed in quantity in a
+// section of a deck
+type Change struct {
+	Kind    ChangeKind `json:"kind"`
+	Section Section    `json:"section"`
+	Name    string     `json:"name"`
+	From    int        `json:"from"`
+	To      int        `json:"to"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+%d %s", c.To, c.Name)
+	case ChangeRemoved:
+		return fmt.Sprintf("-%d %s", c.From, c.Name)
+	}
+
+	return fmt.Sprintf("%+d %s (%d -> %d)", c.To-c.From, c.Name, c.From, c.To)
+}
+
+// Changes is the difference between two versions of a deck
+type Changes []Change
+
+// String formats the changes grouped by section, like
+//
+//	Deck
+//	+2 Lightning Bolt
+//	-1 Shock
+//	+1 Mountain (19 -> 20)
+func (changes Changes) String() string {
+	var sb strings.Builder
+
+	var section Section
+
+	for _, c := range changes {
+		if c.Section != section {
+			if section != "" {
+				sb.WriteString("\n")
+			}
+
+			section = c.Section
+			fmt.Fprintf(&sb, "%s\n", sectionHeaders[section])
+		}
+
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// Diff yields the cards which were added, removed or changed in quantity in
+// each section, going from one version of a deck to another. Printings are
+// ignored, a card which only changed its printing has not changed.
+func Diff(from, to *Deck) Changes {
+	changes := make(Changes, 0)
+
+	for _, section := range Sections {
+		before, after := countByName(from, section), countByName(to, section)
+
+		names := make([]string, 0)
+
+		for name := range before {
+			names = append(names, name)
+		}
+
+		for name := range after {
+			if _, found := before[name]; !found {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			c := Change{Section: section, Name: name, From: before[name], To: after[name]}
+
+			switch {
+			case c.From == c.To:
+				continue
+			case c.From == 0:
+				c.Kind = ChangeAdded
+			case c.To == 0:
+				c.Kind = ChangeRemoved
+			default:
+				c.Kind = ChangeCount
+			}
+
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+func countByName(deck *Deck, section Section) map[string]int {
+	counts := make(map[string]int)
+
+	if deck == nil {
+		return counts
+	}
+
+	for _, e := range deck.Section(section) {
+		counts[e.Name] += e.Count
+	}
+
+	return counts
+}
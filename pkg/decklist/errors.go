@@ -0,0 +1,58 @@
+package decklist
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMissingCount  = errors.New("expected a line like \"4 Lightning Bolt\"")
+	ErrInvalidCount  = errors.New("invalid card count")
+	ErrMissingName   = errors.New("missing card name")
+	ErrMissingColumn = errors.New("missing column")
+	ErrUnknownFormat = errors.New("unknown deck list format")
+)
+
+// LineError is a problem with a single line of a deck list
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	if e.Text == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("line %d: %v: %q", e.Line, e.Err, e.Text)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is every problem found while parsing a deck list. The lines which
+// could be parsed still end up in the deck.
+type Errors []*LineError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// orNil yields nil for an empty list, so that callers can compare the
+// result of a parse with nil
+func (e Errors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,197 @@
+package decklist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Export serializes a deck to a deck list format. Plain text lists do not
+// hold printings, but otherwise round-trip through Parse. MTGO only knows
+// about a main deck and a sideboard, so .dek files list every other section
+// in the sideboard, and mark its cards with their section.
+func Export(format Format, deck *Deck) (string, error) {
+	switch format {
+	case FormatText:
+		return exportText(deck, false), nil
+	case FormatArena:
+		return exportText(deck, true), nil
+	case FormatMTGO:
+		return exportMTGO(deck)
+	case FormatCSV:
+		return exportCSV(deck)
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// sectionHeaders are the headers written for each section, MTG Arena uses
+// "Deck" for the main deck
+var sectionHeaders = map[Section]string{
+	SectionCommander: "Commander",
+	SectionCompanion: "Companion",
+	SectionMain:      "Deck",
+	SectionSideboard: "Sideboard",
+	SectionMaybe:     "Maybeboard",
+}
+
+// exportText writes a list with a header for every section. The arena flavor
+// also writes the deck name and the printing of each card.
+func exportText(deck *Deck, arena bool) string {
+	var sb strings.Builder
+
+	if arena && deck.Name != "" {
+		fmt.Fprintf(&sb, "About\n%s%s\n\n", arenaDeckName, deck.Name)
+	}
+
+	for _, section := range nonEmptySections(deck) {
+		fmt.Fprintf(&sb, "%s\n", sectionHeaders[section])
+
+		for _, entry := range deck.Sections[section] {
+			sb.WriteString(formatTextLine(entry, arena))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func formatTextLine(e Entry, withPrinting bool) string {
+	line := fmt.Sprintf("%d %s", e.Count, e.Name)
+
+	if withPrinting && e.Set != "" {
+		line = fmt.Sprintf("%s (%s)", line, e.Set)
+
+		if e.CollectorNumber != "" {
+			line = fmt.Sprintf("%s %s", line, e.CollectorNumber)
+		}
+	}
+
+	switch {
+	case e.Foil:
+		line += " *F*"
+	case e.Etched:
+		line += " *E*"
+	}
+
+	return line
+}
+
+// mtgoDeck is the root element of an MTGO .dek file
+type mtgoDeck struct {
+	XMLName              xml.Name    `xml:"Deck"`
+	XSD                  string      `xml:"xmlns:xsd,attr"`
+	XSI                  string      `xml:"xmlns:xsi,attr"`
+	NetDeckID            int         `xml:"NetDeckID"`
+	PreconstructedDeckID int         `xml:"PreconstructedDeckID"`
+	Cards                []mtgoCards `xml:"Cards"`
+}
+
+// exportMTGO writes an MTGO .dek file, see Export. MTGO expects commanders
+// and companions in the sideboard.
+func exportMTGO(deck *Deck) (string, error) {
+	dek := mtgoDeck{
+		XSD: "http://www.w3.org/2001/XMLSchema",
+		XSI: "http://www.w3.org/2001/XMLSchema-instance",
+	}
+
+	for _, section := range Sections {
+		annotation, found := mtgoAnnotations[section]
+		if !found {
+			annotation = "0"
+		}
+
+		for _, entry := range deck.Sections[section] {
+			finish := ""
+
+			switch {
+			case entry.Foil:
+				finish = mtgoFinishFoil
+			case entry.Etched:
+				finish = mtgoFinishEtched
+			}
+
+			dek.Cards = append(dek.Cards, mtgoCards{
+				CatID:           "0",
+				Quantity:        strconv.Itoa(entry.Count),
+				Sideboard:       strconv.FormatBool(section != SectionMain),
+				Name:            entry.Name,
+				Annotation:      annotation,
+				Set:             entry.Set,
+				CollectorNumber: entry.CollectorNumber,
+				Finish:          finish,
+			})
+		}
+	}
+
+	data, err := xml.MarshalIndent(dek, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding .dek file: %v", err)
+	}
+
+	return xml.Header + string(data) + "\n", nil
+}
+
+// csvBoards are the Moxfield board names of each section
+var csvBoards = map[Section]string{
+	SectionCommander: "commanders",
+	SectionCompanion: "companions",
+	SectionMain:      "mainboard",
+	SectionSideboard: "sideboard",
+	SectionMaybe:     "maybeboard",
+}
+
+// exportCSV writes the columns of a Moxfield csv export which matter for a
+// deck, with a Board column for the section
+func exportCSV(deck *Deck) (string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{{"Count", "Name", "Edition", "Collector Number", "Foil", "Board"}}
+
+	for _, section := range nonEmptySections(deck) {
+		for _, e := range deck.Sections[section] {
+			foil := ""
+
+			switch {
+			case e.Foil:
+				foil = "foil"
+			case e.Etched:
+				foil = "etched"
+			}
+
+			records = append(records, []string{
+				strconv.Itoa(e.Count),
+				e.Name,
+				strings.ToLower(e.Set),
+				e.CollectorNumber,
+				foil,
+				csvBoards[section],
+			})
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("encoding csv: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+func nonEmptySections(deck *Deck) []Section {
+	sections := make([]Section, 0)
+
+	for _, section := range Sections {
+		if len(deck.Sections[section]) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
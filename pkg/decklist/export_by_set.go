@@ -0,0 +1,103 @@
+package decklist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SetLookup yields the name and release date of a set by its code, like
+// "Magic 2010" for "M10", and whether the set is known
+type SetLookup func(code string) (name string, released time.Time, found bool)
+
+const groupNoSet = "No printing"
+
+// ExportBySet writes a human readable list, where the cards of each section
+// are grouped by the set of their printing, from the oldest set to the
+// newest, with a count for every set. Sets which setOf does not know go after
+// the known sets, and cards without a printing last. Like ExportGrouped, the
+// set titles are comments so the list round-trips through Parse. If setOf is
+// nil, the sets are sorted by code.
+func ExportBySet(deck *Deck, setOf SetLookup) string {
+	var sb strings.Builder
+
+	if deck.Name != "" {
+		fmt.Fprintf(&sb, "About\n%s%s\n\n", arenaDeckName, deck.Name)
+	}
+
+	for _, section := range nonEmptySections(deck) {
+		fmt.Fprintf(&sb, "%s (%d)\n", sectionHeaders[section], deck.Count(section))
+
+		for _, group := range setGroups(deck.Sections[section], setOf) {
+			fmt.Fprintf(&sb, "// %s (%d)\n", group.title, group.count)
+
+			for _, entry := range group.entries {
+				sb.WriteString(formatTextLine(entry, true))
+				sb.WriteString("\n")
+			}
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+type setGroup struct {
+	code     string
+	title    string
+	released time.Time
+	known    bool
+	count    int
+	entries  []Entry
+}
+
+// setGroups groups entries by set, in release order
+func setGroups(entries []Entry, setOf SetLookup) []*setGroup {
+	byCode := make(map[string]*setGroup)
+	groups := make([]*setGroup, 0)
+
+	for _, entry := range entries {
+		code := strings.ToUpper(entry.Set)
+
+		group, found := byCode[code]
+		if !found {
+			group = &setGroup{code: code, title: groupNoSet}
+
+			if code != "" {
+				group.title = code
+
+				if setOf != nil {
+					if name, released, known := setOf(code); known {
+						group.title = fmt.Sprintf("%s (%s)", name, code)
+						group.released, group.known = released, true
+					}
+				}
+			}
+
+			byCode[code] = group
+			groups = append(groups, group)
+		}
+
+		group.count += entry.Count
+		group.entries = append(group.entries, entry)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+
+		switch {
+		case (a.code == "") != (b.code == ""):
+			return b.code == ""
+		case a.known != b.known:
+			return a.known
+		case !a.released.Equal(b.released):
+			return a.released.Before(b.released)
+		}
+
+		return a.code < b.code
+	})
+
+	return groups
+}
@@ -0,0 +1,98 @@
+package decklist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeLookup yields the type line of a card, like "Legendary Creature — Elf"
+type TypeLookup func(name string) string
+
+// cardGroups are the groups of a grouped list, in the order they are
+// printed. A card goes into the first group whose type is in its type line,
+// so that an "Artifact Creature" is a creature and an "Artifact Land" a land.
+var cardGroups = []struct {
+	title    string
+	cardType string
+}{
+	{"Lands", "Land"},
+	{"Creatures", "Creature"},
+	{"Planeswalkers", "Planeswalker"},
+	{"Battles", "Battle"},
+	{"Instants", "Instant"},
+	{"Sorceries", "Sorcery"},
+	{"Artifacts", "Artifact"},
+	{"Enchantments", "Enchantment"},
+}
+
+// printOrder is the order the groups are printed in, lands go last
+var printOrder = []string{
+	"Creatures", "Planeswalkers", "Battles", "Instants", "Sorceries",
+	"Artifacts", "Enchantments", "Lands", groupOther,
+}
+
+const groupOther = "Other"
+
+// ExportGrouped writes a human readable list, where the cards of each section
+// are grouped by type with a count for every group. Group titles are
+// comments, so the list still round-trips through Parse, although the cards
+// of a section are reordered. If typeOf is nil, every card is in the "Other"
+// group.
+func ExportGrouped(deck *Deck, typeOf TypeLookup) string {
+	var sb strings.Builder
+
+	if deck.Name != "" {
+		fmt.Fprintf(&sb, "About\n%s%s\n\n", arenaDeckName, deck.Name)
+	}
+
+	for _, section := range nonEmptySections(deck) {
+		fmt.Fprintf(&sb, "%s (%d)\n", sectionHeaders[section], deck.Count(section))
+
+		groups := make(map[string][]Entry)
+
+		for _, entry := range deck.Sections[section] {
+			group := groupOf(entry.Name, typeOf)
+			groups[group] = append(groups[group], entry)
+		}
+
+		for _, group := range printOrder {
+			entries := groups[group]
+			if len(entries) == 0 {
+				continue
+			}
+
+			count := 0
+			for _, entry := range entries {
+				count += entry.Count
+			}
+
+			fmt.Fprintf(&sb, "// %s (%d)\n", group, count)
+
+			for _, entry := range entries {
+				sb.WriteString(formatTextLine(entry, true))
+				sb.WriteString("\n")
+			}
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func groupOf(name string, typeOf TypeLookup) string {
+	if typeOf == nil {
+		return groupOther
+	}
+
+	// only the front face counts for double faced cards
+	typeLine, _, _ := strings.Cut(typeOf(name), "//")
+
+	for _, group := range cardGroups {
+		if strings.Contains(typeLine, group.cardType) {
+			return group.title
+		}
+	}
+
+	return groupOther
+}
@@ -0,0 +1,193 @@
+package decklist
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDeck() *Deck {
+	deck := New()
+	deck.Name = "Kenrith Burn"
+
+	deck.Add(SectionCommander, Entry{Count: 1, Name: "Kenrith, the Returned King", Set: "ELD", CollectorNumber: "303"})
+	deck.Add(SectionCompanion, Entry{Count: 1, Name: "Lurrus of the Dream-Den", Set: "IKO", CollectorNumber: "226", Etched: true})
+	deck.Add(SectionMain, Entry{Count: 4, Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146", Foil: true})
+	deck.Add(SectionMain, Entry{Count: 2, Name: "Fire // Ice", Set: "MH2", CollectorNumber: "290"})
+	deck.Add(SectionMain, Entry{Count: 4, Name: "Goblin Guide", Set: "ZEN", CollectorNumber: "126"})
+	deck.Add(SectionMain, Entry{Count: 20, Name: "Mountain"})
+	deck.Add(SectionSideboard, Entry{Count: 3, Name: "Smash to Smithereens", Set: "SOM", CollectorNumber: "107"})
+	deck.Add(SectionMaybe, Entry{Count: 1, Name: "Fireblast", Set: "VIS", CollectorNumber: "79"})
+
+	return deck
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatArena, FormatCSV} {
+		original := testDeck()
+
+		list, err := Export(format, original)
+		if err != nil {
+			t.Fatalf("%s: exporting: %v", format, err)
+		}
+
+		parsed, err := Parse(list)
+		if err != nil {
+			t.Fatalf("%s: parsing export: %v\n%s", format, err, list)
+		}
+
+		if format == FormatCSV {
+			// csv files do not hold a deck name
+			parsed.Name = original.Name
+		}
+
+		if !reflect.DeepEqual(parsed, original) {
+			t.Fatalf("%s: deck changed in round trip:\n%+v\n%+v", format, parsed, original)
+		}
+	}
+}
+
+func TestExportTextRoundTrip(t *testing.T) {
+	original := testDeck()
+
+	list, err := Export(FormatText, original)
+	if err != nil {
+		t.Fatalf("exporting: %v", err)
+	}
+
+	parsed, err := ParseFormat(FormatText, list)
+	if err != nil {
+		t.Fatalf("parsing export: %v\n%s", err, list)
+	}
+
+	for _, section := range Sections {
+		for idx, entry := range original.Sections[section] {
+			entry.Set, entry.CollectorNumber = "", ""
+
+			if parsed.Sections[section][idx] != entry {
+				t.Fatalf("%s: expected %+v, got %+v", section, entry, parsed.Sections[section][idx])
+			}
+		}
+	}
+}
+
+func TestExportMTGORoundTrip(t *testing.T) {
+	original := testDeck()
+
+	list, err := Export(FormatMTGO, original)
+	if err != nil {
+		t.Fatalf("exporting: %v", err)
+	}
+
+	parsed, err := Parse(list)
+	if err != nil {
+		t.Fatalf("parsing export: %v\n%s", err, list)
+	}
+
+	for _, section := range Sections {
+		if len(original.Sections[section]) == 0 {
+			t.Fatalf("expected the test deck to have a %s section", section)
+		}
+
+		if !reflect.DeepEqual(parsed.Sections[section], original.Sections[section]) {
+			t.Fatalf("%s changed in round trip:\n%s", section, list)
+		}
+	}
+
+	// MTGO itself only knows the main deck and the sideboard
+	main := strings.Count(list, `Sideboard="false"`)
+	if main != len(original.Sections[SectionMain]) {
+		t.Fatalf("expected only the main deck outside of the sideboard:\n%s", list)
+	}
+}
+
+func TestExportGroupedRoundTrip(t *testing.T) {
+	types := map[string]string{
+		"Kenrith, the Returned King": "Legendary Creature — Human Noble",
+		"Lurrus of the Dream-Den":    "Legendary Creature — Cat Nightmare",
+		"Lightning Bolt":             "Instant",
+		"Fire // Ice":                "Instant // Instant",
+		"Goblin Guide":               "Creature — Goblin Scout",
+		"Mountain":                   "Basic Land — Mountain",
+		"Smash to Smithereens":       "Instant",
+		"Fireblast":                  "Instant",
+	}
+
+	original := testDeck()
+
+	list := ExportGrouped(original, func(name string) string { return types[name] })
+
+	parsed, err := Parse(list)
+	if err != nil {
+		t.Fatalf("parsing export: %v\n%s", err, list)
+	}
+
+	if parsed.Name != original.Name {
+		t.Fatalf("expected deck name %q, got %q", original.Name, parsed.Name)
+	}
+
+	for _, section := range Sections {
+		if !reflect.DeepEqual(sorted(parsed.Sections[section]), sorted(original.Sections[section])) {
+			t.Fatalf("%s changed in round trip:\n%s", section, list)
+		}
+	}
+}
+
+func TestExportBySet(t *testing.T) {
+	sets := map[string]struct {
+		name     string
+		released string
+	}{
+		"M10": {"Magic 2010", "2009-07-17"},
+		"ZEN": {"Zendikar", "2009-10-02"},
+		"MH2": {"Modern Horizons 2", "2021-06-18"},
+	}
+
+	setOf := func(code string) (string, time.Time, bool) {
+		set, found := sets[code]
+		released, _ := time.Parse(time.DateOnly, set.released)
+
+		return set.name, released, found
+	}
+
+	original := testDeck()
+	original.Add(SectionMain, Entry{Count: 1, Name: "Searing Spear", Set: "M11", CollectorNumber: "159"})
+
+	list := ExportBySet(original, setOf)
+
+	main := list[strings.Index(list, "Deck ("):strings.Index(list, "Sideboard")]
+	expected := []string{"// Magic 2010 (M10) (4)", "// Zendikar (ZEN) (4)", "// Modern Horizons 2 (MH2) (2)", "// M11 (1)", "// No printing (20)"}
+
+	last := -1
+	for _, title := range expected {
+		position := strings.Index(main, title)
+		if position < last {
+			t.Fatalf("expected %q after the sets before it:\n%s", title, list)
+		}
+
+		last = position
+	}
+
+	parsed, err := Parse(list)
+	if err != nil {
+		t.Fatalf("parsing export: %v\n%s", err, list)
+	}
+
+	for _, section := range Sections {
+		if !reflect.DeepEqual(sorted(parsed.Sections[section]), sorted(original.Sections[section])) {
+			t.Fatalf("%s changed in round trip:\n%s", section, list)
+		}
+	}
+}
+
+func sorted(entries []Entry) []Entry {
+	result := append([]Entry(nil), entries...)
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,143 @@
+package decklist
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	mtgoElementDeck  = "Deck"
+	mtgoElementCards = "Cards"
+
+	mtgoFinishFoil   = "foil"
+	mtgoFinishEtched = "etched"
+)
+
+// mtgoAnnotations are the annotations which mark the sections MTGO does not
+// know. MTGO itself only writes "0", and lists those cards in the sideboard.
+var mtgoAnnotations = map[Section]string{
+	SectionCommander: "1",
+	SectionCompanion: "2",
+	SectionMaybe:     "3",
+}
+
+// mtgoCards is a <Cards> element of an MTGO .dek file, like
+// <Cards CatID="1" Quantity="4" Sideboard="false" Name="Lightning Bolt" />
+//
+// The printing is not part of the format, the catalog ids of MTGO are not
+// known to us, so exports keep it in attributes of their own.
+type mtgoCards struct {
+	CatID           string `xml:"CatID,attr"`
+	Quantity        string `xml:"Quantity,attr"`
+	Sideboard       string `xml:"Sideboard,attr"`
+	Name            string `xml:"Name,attr"`
+	Annotation      string `xml:"Annotation,attr"`
+	Set             string `xml:"Set,attr,omitempty"`
+	CollectorNumber string `xml:"CollectorNumber,attr,omitempty"`
+	Finish          string `xml:"Finish,attr,omitempty"`
+}
+
+// parseMTGO parses an MTGO .dek file. MTGO has no commander, companion or
+// maybe sections, those cards are part of the sideboard unless their
+// annotation marks their section.
+func parseMTGO(list string) (*Deck, error) {
+	deck := New()
+
+	var errs Errors
+
+	decoder := xml.NewDecoder(strings.NewReader(list))
+	foundDeck := false
+
+	for {
+		line, _ := decoder.InputPos()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: fmt.Errorf("reading xml: %v", err)})
+			break
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case mtgoElementDeck:
+			foundDeck = true
+			continue
+		case mtgoElementCards:
+		default:
+			continue
+		}
+
+		var cards mtgoCards
+		if err = decoder.DecodeElement(&cards, &start); err != nil {
+			errs = append(errs, &LineError{Line: line, Err: fmt.Errorf("reading xml: %v", err)})
+			break
+		}
+
+		entry, err := cards.entry()
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Text: cards.Name, Err: err})
+			continue
+		}
+
+		deck.Add(cards.section(), entry)
+	}
+
+	if !foundDeck && len(errs) == 0 {
+		errs = append(errs, &LineError{Line: 1, Err: fmt.Errorf("no <%s> element", mtgoElementDeck)})
+	}
+
+	return deck, errs.orNil()
+}
+
+func (c mtgoCards) entry() (Entry, error) {
+	if strings.TrimSpace(c.Name) == "" {
+		return Entry{}, ErrMissingName
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(c.Quantity))
+	if err != nil || count < 1 {
+		return Entry{}, ErrInvalidCount
+	}
+
+	entry := Entry{
+		Count:           count,
+		Name:            strings.TrimSpace(c.Name),
+		Set:             strings.TrimSpace(c.Set),
+		CollectorNumber: strings.TrimSpace(c.CollectorNumber),
+	}
+
+	switch strings.ToLower(c.Finish) {
+	case mtgoFinishFoil:
+		entry.Foil = true
+	case mtgoFinishEtched:
+		entry.Etched = true
+	}
+
+	return entry, nil
+}
+
+func (c mtgoCards) section() Section {
+	for section, annotation := range mtgoAnnotations {
+		if c.Annotation == annotation {
+			return section
+		}
+	}
+
+	if strings.EqualFold(c.Sideboard, "true") {
+		return SectionSideboard
+	}
+
+	return SectionMain
+}
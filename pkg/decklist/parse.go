@@ -0,0 +1,88 @@
+package decklist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// Format is a deck list file format
+type Format string
+
+const (
+	// FormatText is a plain list, like "4 Lightning Bolt" or "4x Lightning
+	// Bolt", with optional section headers
+	FormatText Format = "text"
+
+	// FormatArena is the MTG Arena export, which is a plain list with the
+	// printing of each card, like "4 Lightning Bolt (M10) 146"
+	FormatArena Format = "arena"
+
+	// FormatMTGO is the MTGO .dek xml file
+	FormatMTGO Format = "mtgo"
+
+	// FormatCSV is a csv export of Moxfield, Archidekt or Deckstats
+	FormatCSV Format = "csv"
+)
+
+// Parse detects the format of a deck list and parses it. If some lines can
+// not be parsed, the returned error is of type Errors and the deck holds
+// every line which could be parsed.
+func Parse(list string) (*Deck, error) {
+	return ParseFormat(Detect(list), list)
+}
+
+// ParseFormat parses a deck list of a known format
+func ParseFormat(format Format, list string) (*Deck, error) {
+	switch format {
+	case FormatText, FormatArena:
+		return parseText(list)
+	case FormatMTGO:
+		return parseMTGO(list)
+	case FormatCSV:
+		return parseCSV(list)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Detect guesses the format of a deck list
+func Detect(list string) Format {
+	trimmed := strings.TrimSpace(list)
+
+	if strings.HasPrefix(trimmed, "<") {
+		return FormatMTGO
+	}
+
+	firstLine, _, _ := strings.Cut(trimmed, "\n")
+
+	header, err := csv.NewReader(strings.NewReader(firstLine)).Read()
+	if err == nil && newColumns(header).isDeck() {
+		return FormatCSV
+	}
+
+	for _, line := range strings.Split(trimmed, "\n") {
+		if match := textLineRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil && match[3] != "" {
+			return FormatArena
+		}
+	}
+
+	return FormatText
+}
+
+// normalizeHeader lowercases a section header or csv column name, and strips
+// the decoration around it, like in "// Sideboard (15):"
+func normalizeHeader(header string) string {
+	header = strings.TrimSpace(header)
+	header = strings.TrimPrefix(header, "//")
+	header = strings.TrimSuffix(header, ":")
+	header = strings.TrimSpace(header)
+
+	if idx := strings.LastIndex(header, " ("); idx > 0 && strings.HasSuffix(header, ")") {
+		header = header[:idx]
+	}
+
+	header = strings.ReplaceAll(header, "_", " ")
+
+	return strings.ToLower(strings.TrimSpace(header))
+}
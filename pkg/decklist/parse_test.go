@@ -0,0 +1,167 @@
+package decklist
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseArena(t *testing.T) {
+	const list = `About
+Name Burn
+
+Commander
+1 Kenrith, the Returned King (ELD) 303
+
+Deck
+4 Lightning Bolt (M10) 146
+2 Fire // Ice (MH2) 290 *F*
+20 Mountain
+
+Sideboard
+3 Smash to Smithereens (SOM) 107
+`
+
+	deck, err := Parse(list)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if deck.Name != "Burn" {
+		t.Fatalf("expected deck name %q, got %q", "Burn", deck.Name)
+	}
+
+	expected := map[Section][]Entry{
+		SectionCommander: {
+			{Count: 1, Name: "Kenrith, the Returned King", Set: "ELD", CollectorNumber: "303"},
+		},
+		SectionMain: {
+			{Count: 4, Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146"},
+			{Count: 2, Name: "Fire // Ice", Set: "MH2", CollectorNumber: "290", Foil: true},
+			{Count: 20, Name: "Mountain"},
+		},
+		SectionSideboard: {
+			{Count: 3, Name: "Smash to Smithereens", Set: "SOM", CollectorNumber: "107"},
+		},
+	}
+
+	if !reflect.DeepEqual(deck.Sections, expected) {
+		t.Fatalf("unexpected sections:\n%+v\n%+v", deck.Sections, expected)
+	}
+}
+
+func TestParsePlainListUsesBlankLineForSideboard(t *testing.T) {
+	const list = `4 Lightning Bolt
+4x Goblin Guide
+SB: 1 Pyroblast
+
+2 Smash to Smithereens`
+
+	deck, err := ParseFormat(FormatText, list)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if deck.Count(SectionMain) != 8 {
+		t.Fatalf("expected 8 main deck cards, got %d", deck.Count(SectionMain))
+	}
+
+	if deck.Count(SectionSideboard) != 3 {
+		t.Fatalf("expected 3 sideboard cards, got %d", deck.Count(SectionSideboard))
+	}
+}
+
+func TestParseReportsLineErrors(t *testing.T) {
+	const list = `4 Lightning Bolt
+Goblin Guide
+0 Mountain`
+
+	deck, err := ParseFormat(FormatText, list)
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 line errors, got %v", err)
+	}
+
+	if errs[0].Line != 2 || !errors.Is(errs[0], ErrMissingCount) {
+		t.Fatalf("unexpected first error: %v", errs[0])
+	}
+
+	if errs[1].Line != 3 || !errors.Is(errs[1], ErrInvalidCount) {
+		t.Fatalf("unexpected second error: %v", errs[1])
+	}
+
+	if deck.Count(SectionMain) != 4 {
+		t.Fatalf("expected the valid lines to be parsed")
+	}
+}
+
+func TestParseMTGO(t *testing.T) {
+	const list = `<?xml version="1.0" encoding="utf-8"?>
+<Deck xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <NetDeckID>0</NetDeckID>
+  <PreconstructedDeckID>0</PreconstructedDeckID>
+  <Cards CatID="52148" Quantity="4" Sideboard="false" Name="Lightning Bolt" Annotation="0" />
+  <Cards CatID="1" Quantity="x" Sideboard="false" Name="Goblin Guide" Annotation="0" />
+  <Cards CatID="38836" Quantity="2" Sideboard="true" Name="Smash to Smithereens" Annotation="0" />
+</Deck>`
+
+	if Detect(list) != FormatMTGO {
+		t.Fatalf("expected the list to be detected as %q", FormatMTGO)
+	}
+
+	deck, err := Parse(list)
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 6 {
+		t.Fatalf("expected an error on line 6, got %v", err)
+	}
+
+	if deck.Count(SectionMain) != 4 || deck.Count(SectionSideboard) != 2 {
+		t.Fatalf("unexpected sections: %+v", deck.Sections)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := map[string]string{
+		"moxfield": `"Count","Tradelist Count","Name","Edition","Condition","Language","Foil","Collector Number","Board"
+"4","0","Lightning Bolt","m10","Near Mint","English","foil","146","mainboard"
+"1","0","Kenrith, the Returned King","eld","Near Mint","English","","303","commanders"
+"2","0","Smash to Smithereens","som","Near Mint","English","","107","sideboard"`,
+		"archidekt": `Quantity,Name,Finish,Edition Name,Edition Code,Collector Number,Categories
+4,Lightning Bolt,Foil,Magic 2010,m10,146,"Burn,Instant"
+1,"Kenrith, the Returned King",Normal,Throne of Eldraine,eld,303,Commander
+2,Smash to Smithereens,Normal,Scars of Mirrodin,som,107,Sideboard`,
+		"deckstats": `amount,card_name,is_foil,set_code,collector_number,section
+4,Lightning Bolt,1,M10,146,main
+1,"Kenrith, the Returned King",0,ELD,303,commander
+2,Smash to Smithereens,0,SOM,107,sideboard`,
+	}
+
+	expected := map[Section][]Entry{
+		SectionMain: {
+			{Count: 4, Name: "Lightning Bolt", Set: "M10", CollectorNumber: "146", Foil: true},
+		},
+		SectionCommander: {
+			{Count: 1, Name: "Kenrith, the Returned King", Set: "ELD", CollectorNumber: "303"},
+		},
+		SectionSideboard: {
+			{Count: 2, Name: "Smash to Smithereens", Set: "SOM", CollectorNumber: "107"},
+		},
+	}
+
+	for site, list := range tests {
+		if Detect(list) != FormatCSV {
+			t.Fatalf("%s: expected the list to be detected as %q", site, FormatCSV)
+		}
+
+		deck, err := Parse(list)
+		if err != nil {
+			t.Fatalf("%s: parsing: %v", site, err)
+		}
+
+		if !reflect.DeepEqual(deck.Sections, expected) {
+			t.Fatalf("%s: unexpected sections:\n%+v\n%+v", site, deck.Sections, expected)
+		}
+	}
+}
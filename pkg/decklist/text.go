@@ -0,0 +1,108 @@
+package decklist
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// textLineRegex matches "4 Lightning Bolt", "4x Lightning Bolt" and the
+// MTG Arena "4 Lightning Bolt (M10) 146", with an optional *F* (foil) or
+// *E* (etched) marker at the end
+var textLineRegex = regexp.MustCompile(
+	`^(\d+)\s*[xX]?\s+(.+?)(?:\s+\(([^()\s]+)\)(?:\s+([^\s*]+))?)?(?:\s+\*([FE])\*)?$`,
+)
+
+const (
+	sideboardPrefix = "SB:"
+	arenaAbout      = "about"
+	arenaDeckName   = "Name "
+)
+
+// parseText parses plain and MTG Arena deck lists.
+//
+// Sections are started by headers like "Sideboard" or "// Commander". A list
+// without any headers follows the common convention that the cards after the
+// first blank line are the sideboard. Lines prefixed with "SB:" always go to
+// the sideboard.
+func parseText(list string) (*Deck, error) {
+	deck := New()
+
+	var errs Errors
+
+	section := SectionMain
+	inAbout := false
+	hasHeaders := false
+
+	for idx, raw := range strings.Split(list, "\n") {
+		line := strings.TrimSpace(raw)
+		lineNumber := idx + 1
+
+		if line == "" {
+			if !hasHeaders && section == SectionMain && len(deck.Sections[SectionMain]) > 0 {
+				section = SectionSideboard
+			}
+
+			continue
+		}
+
+		if normalizeHeader(line) == arenaAbout {
+			inAbout, hasHeaders = true, true
+			continue
+		}
+
+		if s, isHeader := parseSection(line); isHeader {
+			section, inAbout, hasHeaders = s, false, true
+			continue
+		}
+
+		if inAbout {
+			if strings.HasPrefix(line, arenaDeckName) {
+				deck.Name = strings.TrimSpace(strings.TrimPrefix(line, arenaDeckName))
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		lineSection := section
+		if strings.HasPrefix(strings.ToUpper(line), sideboardPrefix) {
+			lineSection = SectionSideboard
+			line = strings.TrimSpace(line[len(sideboardPrefix):])
+		}
+
+		entry, err := parseTextLine(line)
+		if err != nil {
+			errs = append(errs, &LineError{Line: lineNumber, Text: raw, Err: err})
+			continue
+		}
+
+		deck.Add(lineSection, entry)
+	}
+
+	return deck, errs.orNil()
+}
+
+func parseTextLine(line string) (Entry, error) {
+	match := textLineRegex.FindStringSubmatch(line)
+	if match == nil {
+		return Entry{}, ErrMissingCount
+	}
+
+	count, err := strconv.Atoi(match[1])
+	if err != nil || count < 1 {
+		return Entry{}, ErrInvalidCount
+	}
+
+	return Entry{
+		Count:           count,
+		Name:            strings.TrimSpace(match[2]),
+		Set:             strings.ToUpper(match[3]),
+		CollectorNumber: match[4],
+		Foil:            match[5] == "F",
+		Etched:          match[5] == "E",
+	}, nil
+}
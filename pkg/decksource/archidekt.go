@@ -0,0 +1,148 @@
+package decksource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// archidektFormats are the names of the format numbers of archidekt
+var archidektFormats = map[int]string{
+	1:  "standard",
+	2:  "modern",
+	3:  "commander",
+	4:  "legacy",
+	5:  "vintage",
+	6:  "pauper",
+	15: "pioneer",
+	16: "historic",
+}
+
+// Archidekt fetches decks from the api of archidekt.com
+type Archidekt struct {
+	Client *http.Client
+
+	// BaseURL replaces https://archidekt.com, like a fake server in tests
+	BaseURL string
+}
+
+// archidektDeck is a deck of the archidekt api. Cards are sorted into
+// categories, some of which are not part of the deck, like the maybeboard.
+type archidektDeck struct {
+	Name   string `json:"name"`
+	Format int    `json:"deckFormat"`
+	Owner  struct {
+		Username string `json:"username"`
+	} `json:"owner"`
+	Categories []struct {
+		Name           string `json:"name"`
+		IncludedInDeck bool   `json:"includedInDeck"`
+	} `json:"categories"`
+	Cards []struct {
+		Quantity   int      `json:"quantity"`
+		Modifier   string   `json:"modifier"`
+		Categories []string `json:"categories"`
+		Card       struct {
+			CollectorNumber string `json:"collectorNumber"`
+			Edition         struct {
+				Code string `json:"editioncode"`
+			} `json:"edition"`
+			OracleCard struct {
+				Name string `json:"name"`
+			} `json:"oracleCard"`
+		} `json:"card"`
+	} `json:"cards"`
+}
+
+func (s *Archidekt) Name() string {
+	return "Archidekt"
+}
+
+func (s *Archidekt) Hosts() []string {
+	return []string{"archidekt.com"}
+}
+
+// ID yields the number of a deck, like "1234567" for
+// https://archidekt.com/decks/1234567/izzet_delver
+func (s *Archidekt) ID(uri string) (string, error) {
+	return segmentAfter(uri, "decks")
+}
+
+func (s *Archidekt) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	id, err := s.ID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := get(ctx, s.Client, fmt.Sprintf("%s/api/decks/%s/", baseURL(s.BaseURL, "https://archidekt.com"), id))
+	if err != nil {
+		return nil, err
+	}
+
+	var response archidektDeck
+	if err = json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("decoding archidekt deck: %v", err)
+	}
+
+	deck := &Deck{
+		Deck:   decklist.New(),
+		Source: s.Name(),
+		URL:    "https://archidekt.com/decks/" + id,
+		ID:     id,
+		Author: response.Owner.Username,
+		Format: archidektFormats[response.Format],
+	}
+
+	deck.Name = response.Name
+
+	excluded := make(map[string]bool)
+	for _, category := range response.Categories {
+		if !category.IncludedInDeck {
+			excluded[category.Name] = true
+		}
+	}
+
+	for _, card := range response.Cards {
+		section := decklist.SectionMain
+
+		// the first category of a card is the one it is listed in
+		if len(card.Categories) > 0 {
+			if parsed, isSection := sectionOf(card.Categories[0]); isSection {
+				section = parsed
+			} else if excluded[card.Categories[0]] {
+				section = decklist.SectionMaybe
+			}
+		}
+
+		deck.Add(section, decklist.Entry{
+			Count:           card.Quantity,
+			Name:            card.Card.OracleCard.Name,
+			Set:             strings.ToUpper(card.Card.Edition.Code),
+			CollectorNumber: card.Card.CollectorNumber,
+			Foil:            card.Modifier == "Foil",
+			Etched:          card.Modifier == "Etched",
+		})
+	}
+
+	return deck, nil
+}
+
+// sectionOf resolves the categories which are sections of a deck
+func sectionOf(category string) (decklist.Section, bool) {
+	switch strings.ToLower(category) {
+	case "commander":
+		return decklist.SectionCommander, true
+	case "companion":
+		return decklist.SectionCompanion, true
+	case "sideboard":
+		return decklist.SectionSideboard, true
+	case "maybeboard":
+		return decklist.SectionMaybe, true
+	}
+
+	return "", false
+}
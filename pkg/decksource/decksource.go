@@ -0,0 +1,344 @@
+package decksource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var (
+	// ErrUnsupported is yielded for the urls of sites no source knows
+	ErrUnsupported = errors.New("unsupported deck source")
+
+	// ErrNotFound is yielded when a site has no deck at a url
+	ErrNotFound = errors.New("deck not found")
+)
+
+// Deck is a deck list fetched from a source, with what the source tells
+// about it. The name of the deck is the name of the deck list.
+type Deck struct {
+	*decklist.Deck
+
+	// Source is the name of the source the deck was fetched from
+	Source string `json:"source"`
+	URL    string `json:"url"`
+
+	// ID identifies the deck, and can be used as a file name
+	ID string `json:"id"`
+
+	Author string `json:"author,omitempty"`
+	Format string `json:"format,omitempty"`
+
+	// Categories are the groups of cards the source shows, when it does
+	Categories []Category `json:"categories,omitempty"`
+}
+
+// Category is a group of the cards of a deck, like "Creature" or "Ramp"
+type Category struct {
+	Name    string           `json:"name"`
+	Section decklist.Section `json:"section"`
+	Entries []decklist.Entry `json:"entries"`
+}
+
+// Source fetches decks from a deck site, or from somewhere else. When
+// some lines of a deck list can not be parsed, Fetch yields the deck and a
+// decklist.Errors.
+type Source interface {
+	// Name is the name of the site, like "Moxfield"
+	Name() string
+
+	// Hosts are the hosts of the urls of the source, without "www.". The
+	// source of local files has the empty host.
+	Hosts() []string
+
+	// ID yields the ID of the deck at a url, without fetching it
+	ID(uri string) (string, error)
+
+	Fetch(ctx context.Context, uri string) (*Deck, error)
+}
+
+// Lister is a source which holds several decks at some urls, like the
+// decks in a directory
+type Lister interface {
+	Source
+
+	// List yields the urls of the decks at a url, none when the url is a
+	// single deck
+	List(uri string) ([]string, error)
+}
+
+// Registry picks the source of a url by its host
+type Registry struct {
+	mux     sync.Mutex
+	sources []Source
+	byHost  map[string]Source
+}
+
+// NewRegistry creates a registry of some sources
+func NewRegistry(sources ...Source) *Registry {
+	r := &Registry{byHost: make(map[string]Source)}
+
+	for _, source := range sources {
+		r.Register(source)
+	}
+
+	return r
+}
+
+// Default creates a registry of every source, the sources on the web use a
+// client
+func Default(client *http.Client) *Registry {
+	return NewRegistry(
+		&TappedOut{Client: client},
+		&Moxfield{Client: client},
+		&Archidekt{Client: client},
+		&MTGGoldfish{Client: client},
+		&Deckstats{Client: client},
+		&Local{},
+	)
+}
+
+// Register adds a source, which replaces the sources of the same hosts
+func (r *Registry) Register(source Source) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, host := range source.Hosts() {
+		if previous, found := r.byHost[host]; found {
+			r.remove(previous)
+		}
+	}
+
+	for _, host := range source.Hosts() {
+		r.byHost[host] = source
+	}
+
+	r.sources = append(r.sources, source)
+}
+
+// remove removes a source and every host of it
+func (r *Registry) remove(source Source) {
+	for host, registered := range r.byHost {
+		if registered == source {
+			delete(r.byHost, host)
+		}
+	}
+
+	for idx := range r.sources {
+		if r.sources[idx] == source {
+			r.sources = append(r.sources[:idx], r.sources[idx+1:]...)
+			return
+		}
+	}
+}
+
+// Sources yields every source, in the order they were registered
+func (r *Registry) Sources() []Source {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return append([]Source(nil), r.sources...)
+}
+
+// Source yields the source of a url. Paths which exist on disk and file
+// urls are local, urls without a scheme are taken to be https, and the
+// slugs of tappedout decks like "a-slow-painful-death" are tappedout decks.
+func (r *Registry) Source(uri string) (Source, error) {
+	host, err := hostOf(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	source, found := r.byHost[host]
+	if !found {
+		if host == "" {
+			return nil, fmt.Errorf("%w: local files", ErrUnsupported)
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, host)
+	}
+
+	return source, nil
+}
+
+// Fetch fetches the deck at a url from its source
+func (r *Registry) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	source, err := r.Source(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.Fetch(ctx, uri)
+}
+
+// FetchAll fetches every deck at a url, like the decks of a directory. The
+// decks which can not be fetched are skipped, and their errors joined.
+func (r *Registry) FetchAll(ctx context.Context, uri string) ([]*Deck, error) {
+	source, err := r.Source(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	uris := []string{uri}
+
+	if lister, ok := source.(Lister); ok {
+		listed, errList := lister.List(uri)
+		if errList != nil {
+			return nil, errList
+		}
+
+		if len(listed) > 0 {
+			uris = listed
+		}
+	}
+
+	var (
+		decks []*Deck
+		errs  []error
+	)
+
+	for _, deckURI := range uris {
+		deck, errFetch := source.Fetch(ctx, deckURI)
+		if deck != nil {
+			decks = append(decks, deck)
+		}
+
+		if errFetch != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", deckURI, errFetch))
+		}
+	}
+
+	return decks, errors.Join(errs...)
+}
+
+// hostOf yields the host of a url without "www.", or the empty host of
+// local paths
+func hostOf(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+
+	// only what has no scheme can be a path
+	u, err := url.Parse(uri)
+	if err == nil && u.Scheme != "" && strings.Contains(uri, "://") {
+		if u.Scheme == "file" {
+			return "", nil
+		}
+
+		return hostOfURL(u, uri)
+	}
+
+	if _, err = os.Stat(uri); err == nil {
+		return "", nil
+	}
+
+	if _, ok := slugOf(uri); ok {
+		return tappedOutHost, nil
+	}
+
+	u, err = url.Parse("https://" + uri)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is neither a url nor a file", ErrUnsupported, uri)
+	}
+
+	return hostOfURL(u, uri)
+}
+
+func hostOfURL(u *url.URL, uri string) (string, error) {
+	if !strings.Contains(u.Host, ".") {
+		return "", fmt.Errorf("%w: %q is neither a url nor a file", ErrUnsupported, uri)
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."), nil
+}
+
+// pathSegments yields the segments of the path of a url
+func pathSegments(uri string) []string {
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil
+	}
+
+	return strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+}
+
+// segmentAfter yields the segment of the path of a url after a segment,
+// like the ID in /decks/<id>/
+func segmentAfter(uri, segment string) (string, error) {
+	segments := pathSegments(uri)
+
+	for idx := 0; idx < len(segments)-1; idx++ {
+		if segments[idx] == segment {
+			return segments[idx+1], nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: no deck in %q", ErrUnsupported, uri)
+}
+
+// baseURL yields the url of a site, or the url it is replaced with
+func baseURL(configured, site string) string {
+	if configured == "" {
+		configured = site
+	}
+
+	return strings.TrimSuffix(configured, "/")
+}
+
+// get fetches a page or api response
+func get(ctx context.Context, client *http.Client, uri string) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting %s: %v", uri, err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, uri)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("requesting %s: %s", uri, res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", uri, err)
+	}
+
+	return data, nil
+}
+
+// submatch yields the first group of a regular expression in a page,
+// unescaped, or an empty string
+func submatch(re *regexp.Regexp, page []byte) string {
+	if match := re.FindSubmatch(page); len(match) > 1 {
+		return strings.TrimSpace(html.UnescapeString(string(match[1])))
+	}
+
+	return ""
+}
@@ -0,0 +1,254 @@
+package decksource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/fakeapi"
+)
+
+func TestFetch(t *testing.T) {
+	server, err := fakeapi.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	client := server.Client()
+
+	// every site is served by the fake server
+	registry := NewRegistry(
+		&TappedOut{Client: client, BaseURL: server.URL},
+		&Moxfield{Client: client, BaseURL: server.URL},
+		&Archidekt{Client: client, BaseURL: server.URL},
+		&MTGGoldfish{Client: client, BaseURL: server.URL},
+		&Deckstats{Client: client, BaseURL: server.URL},
+		&Local{},
+	)
+
+	dir := t.TempDir()
+	local := filepath.Join(dir, "izzet-delver.txt")
+
+	if err = os.WriteFile(local, []byte("4 Lightning Bolt\n4 Delver of Secrets\n20 Mountain\n\n2 Lightning Bolt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		uri, source, id, author, format string
+		sideboard                       int
+	}{
+		{"https://tappedout.net/mtg-decks/izzet-delver/", "TappedOut", "izzet-delver", "testplayer", "modern", 0},
+		{"izzet-delver", "TappedOut", "izzet-delver", "testplayer", "modern", 0},
+		{"https://www.moxfield.com/decks/izzet-delver", "Moxfield", "izzet-delver", "testplayer", "modern", 2},
+		{"archidekt.com/decks/1234567/izzet_delver", "Archidekt", "1234567", "testplayer", "modern", 2},
+		{"https://www.mtggoldfish.com/deck/5981234#paper", "MTGGoldfish", "5981234", "testplayer", "Modern", 2},
+		{"https://deckstats.net/decks/12345/678901-izzet-delver/en", "Deckstats", "12345-678901", "testplayer", "Modern", 2},
+		{local, "Local", "izzet-delver", "", "", 2},
+	}
+
+	for _, test := range tests {
+		deck, err := registry.Fetch(context.Background(), test.uri)
+		if err != nil {
+			t.Fatalf("%s: fetching: %v", test.uri, err)
+		}
+
+		if deck.Source != test.source || deck.ID != test.id || deck.Author != test.author || deck.Format != test.format {
+			t.Fatalf("%s: unexpected metadata %+v", test.uri, deck)
+		}
+
+		if test.source != "Local" && deck.Name != "Izzet Delver" {
+			t.Fatalf("%s: unexpected name %q", test.uri, deck.Name)
+		}
+
+		if main := deck.Count(decklist.SectionMain); main != 28 {
+			t.Fatalf("%s: expected 28 cards in the main deck, got %d", test.uri, main)
+		}
+
+		if sideboard := deck.Count(decklist.SectionSideboard); sideboard != test.sideboard {
+			t.Fatalf("%s: expected %d cards in the sideboard, got %d", test.uri, test.sideboard, sideboard)
+		}
+	}
+
+	// the maybeboard of archidekt is a category which is not in the deck
+	deck, _ := registry.Fetch(context.Background(), "https://archidekt.com/decks/1234567/")
+	if maybe := deck.Section(decklist.SectionMaybe); len(maybe) != 1 || maybe[0].Name != "Mountain" {
+		t.Fatalf("expected a mountain in the maybeboard, got %+v", maybe)
+	}
+
+	if _, err = registry.Fetch(context.Background(), "https://moxfield.com/decks/no-such-deck"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a missing deck, got %v", err)
+	}
+
+	if _, err = registry.Fetch(context.Background(), "https://example.com/decks/1"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected an unsupported site, got %v", err)
+	}
+
+	decks, err := registry.FetchAll(context.Background(), dir)
+	if err != nil || len(decks) != 1 || decks[0].Name != "izzet-delver" {
+		t.Fatalf("expected the deck of the directory, got %v (%v)", decks, err)
+	}
+}
+
+// hosted is a source of some hosts, which fetches nothing
+type hosted struct {
+	name  string
+	hosts []string
+}
+
+func (s *hosted) Name() string                                 { return s.name }
+func (s *hosted) Hosts() []string                              { return s.hosts }
+func (s *hosted) ID(string) (string, error)                    { return "", nil }
+func (s *hosted) Fetch(context.Context, string) (*Deck, error) { return nil, nil }
+
+func TestRegisterReplacesSources(t *testing.T) {
+	mirrors := &hosted{name: "mirrors", hosts: []string{"a.example.com", "b.example.com", "c.example.com"}}
+	registry := NewRegistry(mirrors)
+
+	// replacing one host of a source replaces all of them
+	a := &hosted{name: "a", hosts: []string{"a.example.com"}}
+	registry.Register(a)
+
+	if sources := registry.Sources(); len(sources) != 1 || sources[0] != a {
+		t.Fatalf("expected only the new source, got %v", sources)
+	}
+
+	if source, err := registry.Source("https://a.example.com/decks/1"); err != nil || source != a {
+		t.Fatalf("expected the new source, got %v (%v)", source, err)
+	}
+
+	for _, uri := range []string{"https://b.example.com/decks/1", "c.example.com/decks/1"} {
+		if _, err := registry.Source(uri); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("%s: expected no source, got %v", uri, err)
+		}
+	}
+
+	// registering a source again keeps all of its hosts
+	registry.Register(mirrors)
+	registry.Register(mirrors)
+
+	if sources := registry.Sources(); len(sources) != 1 || sources[0] != mirrors {
+		t.Fatalf("expected only the mirrors, got %v", sources)
+	}
+
+	for _, host := range mirrors.hosts {
+		if source, err := registry.Source(host + "/decks/1"); err != nil || source != mirrors {
+			t.Fatalf("%s: expected the mirrors, got %v (%v)", host, source, err)
+		}
+	}
+}
+
+func TestParseTappedOut(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "tappedout", "commander.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deck, err := ParseTappedOut(page)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if deck.Name != "Krenko's Goblins" || deck.Author != "goblinking" || deck.Format != "edh" {
+		t.Fatalf("unexpected metadata %q by %q in %q", deck.Name, deck.Author, deck.Format)
+	}
+
+	names := make([]string, 0)
+	for _, category := range deck.Categories {
+		names = append(names, category.Name)
+	}
+
+	if fmt.Sprint(names) != "[Commander Creature Instant Land Sideboard Maybeboard]" {
+		t.Fatalf("unexpected categories %v", names)
+	}
+
+	counts := map[decklist.Section]int{
+		decklist.SectionCommander: 1,
+		decklist.SectionMain:      15,
+		decklist.SectionSideboard: 1,
+		decklist.SectionMaybe:     2,
+	}
+
+	for section, count := range counts {
+		if got := deck.Count(section); got != count {
+			t.Fatalf("expected %d cards in the %s section, got %d", count, section, got)
+		}
+	}
+
+	// the printings come from the export, the maybeboard is not in it
+	commander := deck.Section(decklist.SectionCommander)[0]
+	if commander.Name != "Krenko, Mob Boss" || commander.Set != "M13" || commander.CollectorNumber != "139" {
+		t.Fatalf("unexpected commander %+v", commander)
+	}
+
+	if warp := deck.Categories[2].Entries[1]; warp.Name != "Chaos Warp" || !warp.Foil {
+		t.Fatalf("expected a foil chaos warp, got %+v", warp)
+	}
+
+	if maybe := deck.Section(decklist.SectionMaybe)[0]; maybe.Set != "" {
+		t.Fatalf("expected no printing for the maybeboard, got %+v", maybe)
+	}
+
+	// card links in comments and the sidebar are not part of the deck
+	page, err = os.ReadFile(filepath.Join("testdata", "tappedout", "comments.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deck, err = ParseTappedOut(page); err != nil {
+		t.Fatalf("parsing a page with comments: %v", err)
+	}
+
+	if deck.Count(decklist.SectionMain) != 20 || deck.Count(decklist.SectionSideboard) != 2 || len(deck.Categories) != 4 {
+		t.Fatalf("unexpected deck %v with %d categories", deck.Entries(), len(deck.Categories))
+	}
+
+	if guide := deck.Section(decklist.SectionMain)[0]; guide.Name != "Goblin Guide" || guide.Count != 4 || guide.Set != "ZEN" {
+		t.Fatalf("unexpected first card %+v", guide)
+	}
+
+	// pages which changed are errors, decks with missing cards come with them
+	for name, found := range map[string]bool{"changed-layout.html": false, "missing-cards.html": true} {
+		page, err = os.ReadFile(filepath.Join("testdata", "tappedout", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if deck, err = ParseTappedOut(page); !errors.Is(err, ErrPageLayout) {
+			t.Fatalf("%s: expected a layout error, got %v", name, err)
+		}
+
+		if (deck != nil) != found {
+			t.Fatalf("%s: expected a deck: %v, got %v", name, found, deck)
+		}
+	}
+}
+
+func TestFetchTappedOutWithMissingCards(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "tappedout", "missing-cards.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(page)
+	}))
+	defer server.Close()
+
+	source := &TappedOut{Client: server.Client(), BaseURL: server.URL}
+
+	deck, err := source.Fetch(context.Background(), "https://tappedout.net/mtg-decks/burn/")
+	if !errors.Is(err, ErrPageLayout) {
+		t.Fatalf("expected a layout error, got %v", err)
+	}
+
+	if deck == nil || deck.ID != "burn" || len(deck.Entries()) == 0 {
+		t.Fatalf("expected the cards which were found, got %+v", deck)
+	}
+}
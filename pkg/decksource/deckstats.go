@@ -0,0 +1,90 @@
+package decksource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var (
+	deckstatsName   = regexp.MustCompile(`(?m)^//\s*NAME:\s*(.+?)(?:\s+from\s+\S*deckstats\.net)?\s*$`)
+	deckstatsAuthor = regexp.MustCompile(`(?m)^//\s*AUTHOR:\s*(.+?)\s*$`)
+	deckstatsFormat = regexp.MustCompile(`(?m)^//\s*FORMAT:\s*(.+?)\s*$`)
+)
+
+// Deckstats fetches the text exports of the decks of deckstats.net, which
+// start with comments of the name, author and format of the deck
+type Deckstats struct {
+	Client *http.Client
+
+	// BaseURL replaces https://deckstats.net, like a fake server in tests
+	BaseURL string
+}
+
+func (s *Deckstats) Name() string {
+	return "Deckstats"
+}
+
+func (s *Deckstats) Hosts() []string {
+	return []string{"deckstats.net"}
+}
+
+// ID yields the owner and the number of a deck, like "12345-678901" for
+// https://deckstats.net/decks/12345/678901-izzet-delver/en
+func (s *Deckstats) ID(uri string) (string, error) {
+	owner, deck, err := s.path(uri)
+	if err != nil {
+		return "", err
+	}
+
+	number, _, _ := strings.Cut(deck, "-")
+
+	return owner + "-" + number, nil
+}
+
+// path yields the owner and the deck segments of the path of a deck url
+func (s *Deckstats) path(uri string) (owner, deck string, err error) {
+	segments := pathSegments(uri)
+
+	for idx := 0; idx < len(segments)-2; idx++ {
+		if segments[idx] == "decks" {
+			return segments[idx+1], segments[idx+2], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: no deck in %q", ErrUnsupported, uri)
+}
+
+func (s *Deckstats) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	owner, deckPath, err := s.path(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := s.ID(uri)
+	pageURL := fmt.Sprintf("%s/decks/%s/%s/", baseURL(s.BaseURL, "https://deckstats.net"), owner, deckPath)
+
+	list, err := get(ctx, s.Client, pageURL+"?export_txt=1")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := decklist.ParseFormat(decklist.FormatText, string(list))
+
+	deck := &Deck{
+		Deck:   parsed,
+		Source: s.Name(),
+		URL:    fmt.Sprintf("https://deckstats.net/decks/%s/%s/", owner, deckPath),
+		ID:     id,
+		Author: submatch(deckstatsAuthor, list),
+		Format: submatch(deckstatsFormat, list),
+	}
+
+	deck.Name = submatch(deckstatsName, list)
+
+	return deck, err
+}
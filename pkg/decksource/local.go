@@ -0,0 +1,93 @@
+package decksource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// localExtensions are the extensions of the deck list files in directories
+var localExtensions = map[string]bool{".txt": true, ".dec": true, ".dek": true, ".csv": true}
+
+// Local reads deck list files in any format decklist can parse, and the
+// deck list files of directories
+type Local struct{}
+
+func (s *Local) Name() string {
+	return "Local"
+}
+
+func (s *Local) Hosts() []string {
+	return []string{""}
+}
+
+// ID yields the file name of a deck without its extension
+func (s *Local) ID(uri string) (string, error) {
+	name := filepath.Base(localPath(uri))
+	return strings.TrimSuffix(name, filepath.Ext(name)), nil
+}
+
+func (s *Local) Fetch(_ context.Context, uri string) (*Deck, error) {
+	path := localPath(uri)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading deck list: %v", err)
+	}
+
+	parsed, err := decklist.Parse(string(data))
+	if parsed == nil {
+		return nil, err
+	}
+
+	id, _ := s.ID(path)
+
+	deck := &Deck{
+		Deck:   parsed,
+		Source: s.Name(),
+		URL:    path,
+		ID:     id,
+	}
+
+	// lists without a name are named after their file
+	if deck.Name == "" {
+		deck.Name = id
+	}
+
+	return deck, err
+}
+
+// List yields the deck list files of a directory, by name
+func (s *Local) List(uri string) ([]string, error) {
+	path := localPath(uri)
+
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading deck directory: %v", err)
+	}
+
+	files := make([]string, 0)
+
+	for _, entry := range entries {
+		if !entry.IsDir() && localExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func localPath(uri string) string {
+	return strings.TrimPrefix(strings.TrimSpace(uri), "file://")
+}
@@ -0,0 +1,128 @@
+package decksource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// Moxfield fetches decks from the api of moxfield.com
+type Moxfield struct {
+	Client *http.Client
+
+	// BaseURL replaces https://api.moxfield.com, like a fake server in tests
+	BaseURL string
+}
+
+// moxfieldDeck is a deck of the moxfield api, the boards hold the cards by
+// name
+type moxfieldDeck struct {
+	Name          string `json:"name"`
+	Format        string `json:"format"`
+	CreatedByUser struct {
+		UserName string `json:"userName"`
+	} `json:"createdByUser"`
+
+	Mainboard  map[string]moxfieldCard `json:"mainboard"`
+	Sideboard  map[string]moxfieldCard `json:"sideboard"`
+	Maybeboard map[string]moxfieldCard `json:"maybeboard"`
+	Commanders map[string]moxfieldCard `json:"commanders"`
+	Companions map[string]moxfieldCard `json:"companions"`
+}
+
+type moxfieldCard struct {
+	Quantity int    `json:"quantity"`
+	Finish   string `json:"finish"`
+	Card     struct {
+		Name string `json:"name"`
+		Set  string `json:"set"`
+		CN   string `json:"cn"`
+	} `json:"card"`
+}
+
+func (s *Moxfield) Name() string {
+	return "Moxfield"
+}
+
+func (s *Moxfield) Hosts() []string {
+	return []string{"moxfield.com"}
+}
+
+// ID yields the public ID of a deck, like "Ua2ZgfAkVk2a7bOqS8zqqA" for
+// https://www.moxfield.com/decks/Ua2ZgfAkVk2a7bOqS8zqqA
+func (s *Moxfield) ID(uri string) (string, error) {
+	return segmentAfter(uri, "decks")
+}
+
+func (s *Moxfield) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	id, err := s.ID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := get(ctx, s.Client, fmt.Sprintf("%s/v2/decks/all/%s", baseURL(s.BaseURL, "https://api.moxfield.com"), id))
+	if err != nil {
+		return nil, err
+	}
+
+	var response moxfieldDeck
+	if err = json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("decoding moxfield deck: %v", err)
+	}
+
+	deck := &Deck{
+		Deck:   decklist.New(),
+		Source: s.Name(),
+		URL:    "https://www.moxfield.com/decks/" + id,
+		ID:     id,
+		Author: response.CreatedByUser.UserName,
+		Format: response.Format,
+	}
+
+	deck.Name = response.Name
+
+	boards := []struct {
+		section decklist.Section
+		cards   map[string]moxfieldCard
+	}{
+		{decklist.SectionCommander, response.Commanders},
+		{decklist.SectionCompanion, response.Companions},
+		{decklist.SectionMain, response.Mainboard},
+		{decklist.SectionSideboard, response.Sideboard},
+		{decklist.SectionMaybe, response.Maybeboard},
+	}
+
+	for _, board := range boards {
+		// the boards are objects, sorted by name to keep the deck stable
+		names := make([]string, 0, len(board.cards))
+		for name := range board.cards {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			card := board.cards[name]
+
+			if card.Card.Name != "" {
+				name = card.Card.Name
+			}
+
+			deck.Add(board.section, decklist.Entry{
+				Count:           card.Quantity,
+				Name:            name,
+				Set:             strings.ToUpper(card.Card.Set),
+				CollectorNumber: card.Card.CN,
+				Foil:            card.Finish == "foil",
+				Etched:          card.Finish == "etched",
+			})
+		}
+	}
+
+	return deck, nil
+}
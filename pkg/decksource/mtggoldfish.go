@@ -0,0 +1,73 @@
+package decksource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var (
+	goldfishName   = regexp.MustCompile(`<h1 class=['"]title['"]>\s*([^<]+)`)
+	goldfishAuthor = regexp.MustCompile(`<span class=['"]author['"]>\s*by\s+([^<]+)`)
+	goldfishFormat = regexp.MustCompile(`Format:\s*([^<\n]+)`)
+)
+
+// MTGGoldfish fetches the deck downloads of mtggoldfish.com, and the deck
+// pages for the name, author and format
+type MTGGoldfish struct {
+	Client *http.Client
+
+	// BaseURL replaces https://www.mtggoldfish.com, like a fake server in
+	// tests
+	BaseURL string
+}
+
+func (s *MTGGoldfish) Name() string {
+	return "MTGGoldfish"
+}
+
+func (s *MTGGoldfish) Hosts() []string {
+	return []string{"mtggoldfish.com"}
+}
+
+// ID yields the number of a deck, like "5981234" for
+// https://www.mtggoldfish.com/deck/5981234
+func (s *MTGGoldfish) ID(uri string) (string, error) {
+	return segmentAfter(uri, "deck")
+}
+
+func (s *MTGGoldfish) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	id, err := s.ID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	base := baseURL(s.BaseURL, "https://www.mtggoldfish.com")
+
+	// the download is a plain list, with the sideboard after a blank line
+	list, err := get(ctx, s.Client, fmt.Sprintf("%s/deck/download/%s", base, id))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := decklist.ParseFormat(decklist.FormatText, string(list))
+
+	deck := &Deck{
+		Deck:   parsed,
+		Source: s.Name(),
+		URL:    "https://www.mtggoldfish.com/deck/" + id,
+		ID:     id,
+	}
+
+	// the list is usable without the page
+	if page, errPage := get(ctx, s.Client, fmt.Sprintf("%s/deck/%s", base, id)); errPage == nil {
+		deck.Name = submatch(goldfishName, page)
+		deck.Author = submatch(goldfishAuthor, page)
+		deck.Format = submatch(goldfishFormat, page)
+	}
+
+	return deck, err
+}
@@ -0,0 +1,80 @@
+package decksource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const tappedOutHost = "tappedout.net"
+
+// tappedOutSlug matches the slugs of tappedout decks, which have no scheme
+// and no dot, unlike the urls of every site
+var tappedOutSlug = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// slugOf yields the slug of a deck which is given by its slug
+func slugOf(uri string) (string, bool) {
+	slug := strings.Trim(strings.TrimSpace(uri), "/")
+	return slug, tappedOutSlug.MatchString(slug)
+}
+
+// TappedOut fetches the deck pages of tappedout.net, which are parsed by
+// ParseTappedOut
+type TappedOut struct {
+	Client *http.Client
+
+	// BaseURL replaces https://tappedout.net, like a fake server in tests
+	BaseURL string
+}
+
+func (s *TappedOut) Name() string {
+	return "TappedOut"
+}
+
+func (s *TappedOut) Hosts() []string {
+	return []string{tappedOutHost}
+}
+
+// ID yields the slug of a deck, like "a-slow-painful-death" for
+// https://tappedout.net/mtg-decks/a-slow-painful-death/ or for the slug
+// itself
+func (s *TappedOut) ID(uri string) (string, error) {
+	if slug, ok := slugOf(uri); ok {
+		return slug, nil
+	}
+
+	return segmentAfter(uri, "mtg-decks")
+}
+
+func (s *TappedOut) Fetch(ctx context.Context, uri string) (*Deck, error) {
+	slug, err := s.ID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// decks are always fetched from the configured site, by slug
+	pageURL := fmt.Sprintf("%s/mtg-decks/%s/", baseURL(s.BaseURL, "https://tappedout.net"), slug)
+
+	page, err := get(ctx, s.Client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// a deck with cards which could not be found is yielded with the error
+	deck, err := ParseTappedOut(page)
+	if err != nil {
+		err = fmt.Errorf("parsing tappedout deck %q: %w", slug, err)
+	}
+
+	if deck == nil {
+		return nil, err
+	}
+
+	deck.Source = s.Name()
+	deck.URL = fmt.Sprintf("https://tappedout.net/mtg-decks/%s/", slug)
+	deck.ID = slug
+
+	return deck, err
+}
@@ -0,0 +1,359 @@
+package decksource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// ErrPageLayout is yielded when a deck page does not look like it used to,
+// the parser has to be updated then
+var ErrPageLayout = errors.New("unexpected deck page layout")
+
+var (
+	// boardHeading matches the headings of the boards and categories, like
+	// "Creature (12)" or "Sideboard (15)"
+	boardHeading = regexp.MustCompile(`^(.+?)\s*\((\d+)\)$`)
+
+	// linkQuantity matches the quantity of the text of a card link, like
+	// "4x Lightning Bolt"
+	linkQuantity = regexp.MustCompile(`^(\d+)x\s`)
+)
+
+// ParseTappedOut parses a deck page of tappedout: the name, author and
+// format of the deck, and its cards by category. The categories are the
+// headings of the boards of the page, like "Commander", "Creature" or
+// "Sideboard", and the printings are taken from the MTG Arena export of the
+// page. Card links outside of the boards, like in comments, are not part of
+// the deck. Pages without categories yield the export. When the cards of
+// some categories do not add up to the counts of their headings, the deck is
+// yielded with an ErrPageLayout.
+func ParseTappedOut(page []byte) (*Deck, error) {
+	p := &tappedOutParser{deck: &Deck{Deck: decklist.New()}}
+
+	z := html.NewTokenizer(bytes.NewReader(page))
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if !errors.Is(z.Err(), io.EOF) {
+				return nil, fmt.Errorf("reading tappedout page: %v", z.Err())
+			}
+
+			return p.result()
+		case html.StartTagToken:
+			t := z.Token()
+
+			if err := p.start(t); err != nil {
+				return nil, err
+			}
+
+			p.enter(t)
+		case html.SelfClosingTagToken:
+			if err := p.start(z.Token()); err != nil {
+				return nil, err
+			}
+		case html.EndTagToken:
+			t := z.Token()
+
+			if err := p.end(t); err != nil {
+				return nil, err
+			}
+
+			p.leave(t)
+		case html.TextToken:
+			p.text(z.Token().Data)
+		}
+	}
+}
+
+// tappedOutParser keeps the state of the tokens read so far
+type tappedOutParser struct {
+	deck *Deck
+
+	// the text of the element which is being read
+	heading, link, textarea, title *strings.Builder
+
+	// the counts in the headings of the categories
+	counts []int
+
+	// the elements open in the board being read, empty outside of the boards
+	board []string
+
+	// the card link being read
+	card *decklist.Entry
+
+	arena     string
+	titleText string
+}
+
+// voidElements have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// enter keeps track of the elements of the boards, which are in elements
+// with the board-container class
+func (p *tappedOutParser) enter(t html.Token) {
+	if voidElements[t.Data] || (len(p.board) == 0 && !hasClass(t, "board-container")) {
+		return
+	}
+
+	p.board = append(p.board, t.Data)
+}
+
+// leave closes an element of the boards, and the elements in it which were
+// not closed, like list items without an end tag
+func (p *tappedOutParser) leave(t html.Token) {
+	for idx := len(p.board) - 1; idx >= 0; idx-- {
+		if p.board[idx] == t.Data {
+			p.board = p.board[:idx]
+			return
+		}
+	}
+}
+
+func (p *tappedOutParser) start(t html.Token) error {
+	switch t.Data {
+	case "meta":
+		if attr(t, "property") == "og:title" {
+			p.deck.Name = strings.TrimSpace(attr(t, "content"))
+		}
+	case "title":
+		p.title = &strings.Builder{}
+	case "h3":
+		if len(p.board) > 0 {
+			p.heading = &strings.Builder{}
+		}
+	case "textarea":
+		if attr(t, "id") == "mtga-textarea" {
+			p.textarea = &strings.Builder{}
+		}
+	case "a":
+		return p.startLink(t)
+	}
+
+	return nil
+}
+
+func (p *tappedOutParser) startLink(t html.Token) error {
+	href := attr(t, "href")
+
+	switch {
+	case p.deck.Author == "" && strings.HasPrefix(href, "/users/"):
+		p.deck.Author = strings.Trim(strings.TrimPrefix(href, "/users/"), "/")
+	case p.deck.Format == "" && strings.HasPrefix(href, "/mtg-decks/search/?format="):
+		format, _, _ := strings.Cut(strings.TrimPrefix(href, "/mtg-decks/search/?format="), "&")
+		p.deck.Format = format
+	}
+
+	if len(p.board) == 0 || !hasClass(t, "card-link") {
+		return nil
+	}
+
+	name := strings.TrimSpace(attr(t, "data-name"))
+	if name == "" {
+		return fmt.Errorf("%w: a card link without a data-name", ErrPageLayout)
+	}
+
+	if len(p.deck.Categories) == 0 {
+		return fmt.Errorf("%w: the card %q is not under a board heading", ErrPageLayout, name)
+	}
+
+	p.card = &decklist.Entry{Name: name}
+	p.link = &strings.Builder{}
+
+	if qty := attr(t, "data-qty"); qty != "" {
+		count, err := strconv.Atoi(qty)
+		if err != nil || count < 1 {
+			return fmt.Errorf("%w: the card %q has the quantity %q", ErrPageLayout, name, qty)
+		}
+
+		p.card.Count = count
+	}
+
+	return nil
+}
+
+func (p *tappedOutParser) end(t html.Token) error {
+	switch t.Data {
+	case "title":
+		if p.title != nil {
+			p.titleText, p.title = strings.TrimSpace(p.title.String()), nil
+		}
+	case "h3":
+		if p.heading != nil {
+			p.startCategory(strings.TrimSpace(p.heading.String()))
+			p.heading = nil
+		}
+	case "textarea":
+		if p.textarea != nil {
+			p.arena, p.textarea = p.textarea.String(), nil
+		}
+	case "a":
+		if p.card != nil {
+			return p.endLink()
+		}
+	}
+
+	return nil
+}
+
+// startCategory starts the category of a heading, the headings without a
+// count are not categories
+func (p *tappedOutParser) startCategory(heading string) {
+	match := boardHeading.FindStringSubmatch(heading)
+	if match == nil {
+		return
+	}
+
+	section, isSection := sectionOf(match[1])
+	if !isSection {
+		section = decklist.SectionMain
+	}
+
+	count, _ := strconv.Atoi(match[2])
+
+	p.deck.Categories = append(p.deck.Categories, Category{Name: match[1], Section: section})
+	p.counts = append(p.counts, count)
+}
+
+func (p *tappedOutParser) endLink() error {
+	card := p.card
+	p.card = nil
+
+	if card.Count == 0 {
+		match := linkQuantity.FindStringSubmatch(strings.TrimSpace(p.link.String()))
+		if match == nil {
+			return fmt.Errorf("%w: the card %q has no quantity", ErrPageLayout, card.Name)
+		}
+
+		card.Count, _ = strconv.Atoi(match[1])
+	}
+
+	// the cards belong to the last heading
+	category := &p.deck.Categories[len(p.deck.Categories)-1]
+	category.Entries = append(category.Entries, *card)
+
+	return nil
+}
+
+func (p *tappedOutParser) text(data string) {
+	for _, builder := range []*strings.Builder{p.heading, p.link, p.textarea, p.title} {
+		if builder != nil {
+			builder.WriteString(data)
+		}
+	}
+}
+
+// result checks the categories against the counts of their headings, and
+// builds the deck
+func (p *tappedOutParser) result() (*Deck, error) {
+	deck := p.deck
+
+	if deck.Name == "" {
+		// like "Izzet Delver (Modern MTG Deck)"
+		deck.Name, _, _ = strings.Cut(p.titleText, " (")
+	}
+
+	var printings *decklist.Deck
+
+	if strings.TrimSpace(p.arena) != "" {
+		var err error
+
+		printings, err = decklist.ParseFormat(decklist.FormatArena, p.arena)
+		if printings == nil {
+			return nil, err
+		}
+	}
+
+	if len(deck.Categories) == 0 {
+		if printings == nil {
+			return nil, fmt.Errorf("%w: no board headings and no mtga export", ErrPageLayout)
+		}
+
+		printings.Name = deck.Name
+		deck.Deck = printings
+
+		return deck, nil
+	}
+
+	for idx := range deck.Categories {
+		category := &deck.Categories[idx]
+
+		for n := range category.Entries {
+			applyPrinting(&category.Entries[n], category.Section, printings)
+			deck.Add(category.Section, category.Entries[n])
+		}
+	}
+
+	return deck, p.checkCounts()
+}
+
+// checkCounts compares the cards of the categories with the counts in
+// their headings, which differ when the cards are not found on the page
+func (p *tappedOutParser) checkCounts() error {
+	var errs []error
+
+	for idx, category := range p.deck.Categories {
+		count := 0
+		for _, entry := range category.Entries {
+			count += entry.Count
+		}
+
+		if count != p.counts[idx] {
+			errs = append(errs, fmt.Errorf("%w: %s lists %d cards, found %d", ErrPageLayout, category.Name, p.counts[idx], count))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyPrinting copies the printing of a card from the export, from the same
+// section when it is there, since the export does not have every section
+func applyPrinting(entry *decklist.Entry, section decklist.Section, printings *decklist.Deck) {
+	if printings == nil {
+		return
+	}
+
+	candidates := append(slices.Clone(printings.Section(section)), printings.Entries()...)
+
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.Name, entry.Name) {
+			entry.Set, entry.CollectorNumber = candidate.Set, candidate.CollectorNumber
+			entry.Foil, entry.Etched = candidate.Foil, candidate.Etched
+
+			return
+		}
+	}
+}
+
+func attr(t html.Token, key string) string {
+	for _, a := range t.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func hasClass(t html.Token, class string) bool {
+	for _, c := range strings.Fields(attr(t, "class")) {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,204 @@
+package deckstats
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// MaxCurveCMC is the last bucket of the mana curve, it holds every card with
+// this mana value or more
+const MaxCurveCMC = 7
+
+// Colors are the keys of pip and land production counts, C is colorless
+var Colors = []string{"W", "U", "B", "R", "G", "C"}
+
+// Types are the card types of the type breakdown
+var Types = []string{
+	"Creature", "Planeswalker", "Battle", "Instant", "Sorcery",
+	"Artifact", "Enchantment", "Land",
+}
+
+// CardLookup resolves a card by name
+type CardLookup func(name string) (*scryfall.Card, error)
+
+// CurveBucket is the number of cards with a mana value
+type CurveBucket struct {
+	CMC           int
+	Permanents    int
+	NonPermanents int
+}
+
+// Stats are the statistics of the main deck and the commanders of a deck.
+// Every count is a count of cards, so 4 copies of a card count 4 times.
+type Stats struct {
+	Cards int
+	Lands int
+
+	// Curve is the mana curve of the non-land cards, index 0 is mana value
+	// 0 and the last bucket holds everything from MaxCurveCMC
+	Curve []CurveBucket
+
+	// Pips counts the colored mana symbols in the mana costs, by color
+	Pips map[string]int
+
+	// LandProduction counts the lands which can produce each color
+	LandProduction map[string]int
+
+	// Types counts the cards of each type, an artifact creature counts as
+	// both an artifact and a creature
+	Types map[string]int
+
+	AverageCMC             float64
+	AverageCMCWithoutLands float64
+
+	// RecommendedLands is the number of lands suggested for the size and
+	// curve of the deck
+	RecommendedLands int
+
+	// Unknown lists the cards which could not be looked up
+	Unknown []string
+}
+
+// Analyze computes the statistics of a deck
+func Analyze(deck *decklist.Deck, lookup CardLookup) *Stats {
+	stats := &Stats{
+		Curve:          make([]CurveBucket, MaxCurveCMC+1),
+		Pips:           make(map[string]int),
+		LandProduction: make(map[string]int),
+		Types:          make(map[string]int),
+	}
+
+	for cmc := range stats.Curve {
+		stats.Curve[cmc].CMC = cmc
+	}
+
+	var totalCMC, totalCMCWithoutLands float64
+
+	entries := make([]decklist.Entry, 0)
+	entries = append(entries, deck.Section(decklist.SectionCommander)...)
+	entries = append(entries, deck.Section(decklist.SectionMain)...)
+
+	for _, entry := range entries {
+		card, err := lookup(entry.Name)
+		if err != nil || card == nil {
+			stats.Unknown = append(stats.Unknown, entry.Name)
+			continue
+		}
+
+		n := entry.Count
+		typeLine := frontTypeLine(card)
+		isLand := strings.Contains(typeLine, "Land")
+
+		stats.Cards += n
+		totalCMC += card.CMC * float64(n)
+
+		for _, t := range Types {
+			if strings.Contains(typeLine, t) {
+				stats.Types[t] += n
+			}
+		}
+
+		for color, count := range countPips(frontManaCost(card)) {
+			stats.Pips[color] += count * n
+		}
+
+		if isLand {
+			stats.Lands += n
+
+			for _, color := range card.ProducedMana {
+				stats.LandProduction[string(color)] += n
+			}
+
+			continue
+		}
+
+		totalCMCWithoutLands += card.CMC * float64(n)
+
+		bucket := &stats.Curve[min(int(card.CMC), MaxCurveCMC)]
+		if isPermanent(typeLine) {
+			bucket.Permanents += n
+		} else {
+			bucket.NonPermanents += n
+		}
+	}
+
+	if stats.Cards > 0 {
+		stats.AverageCMC = totalCMC / float64(stats.Cards)
+	}
+
+	if spells := stats.Cards - stats.Lands; spells > 0 {
+		stats.AverageCMCWithoutLands = totalCMCWithoutLands / float64(spells)
+	}
+
+	stats.RecommendedLands = RecommendedLands(stats.Cards, stats.AverageCMCWithoutLands)
+
+	return stats
+}
+
+// RecommendedLands suggests a land count, based on Frank Karsten's
+// regression of the land counts of successful decks. The 60 card formula is
+// scaled for other deck sizes, except for 100 card commander decks.
+func RecommendedLands(deckSize int, averageCMCWithoutLands float64) int {
+	const commanderDeckSize = 99
+
+	if deckSize == 0 {
+		return 0
+	}
+
+	if deckSize >= commanderDeckSize {
+		return int(math.Round(31.42 + 3.13*averageCMCWithoutLands))
+	}
+
+	return int(math.Round((19.59 + 1.90*averageCMCWithoutLands) * float64(deckSize) / 60))
+}
+
+func isPermanent(typeLine string) bool {
+	return !strings.Contains(typeLine, "Instant") && !strings.Contains(typeLine, "Sorcery")
+}
+
+// frontTypeLine is the type line of the front face, so that a modal double
+// faced card like a spell on the front and a land on the back is a spell
+func frontTypeLine(card *scryfall.Card) string {
+	if len(card.CardFaces) > 0 && card.CardFaces[0].TypeLine != "" {
+		return card.CardFaces[0].TypeLine
+	}
+
+	typeLine, _, _ := strings.Cut(card.TypeLine, "//")
+
+	return typeLine
+}
+
+func frontManaCost(card *scryfall.Card) string {
+	if card.ManaCost == "" && len(card.CardFaces) > 0 {
+		return card.CardFaces[0].ManaCost
+	}
+
+	manaCost, _, _ := strings.Cut(card.ManaCost, "//")
+
+	return manaCost
+}
+
+var regexManaSymbol = regexp.MustCompile(`\{([^}]+)\}`)
+
+// countPips counts the colored symbols of a mana cost. Hybrid symbols like
+// {R/G} count for both colors, phyrexian symbols like {W/P} for their color.
+func countPips(manaCost string) map[string]int {
+	pips := make(map[string]int)
+
+	for _, match := range regexManaSymbol.FindAllStringSubmatch(manaCost, -1) {
+		for _, part := range strings.Split(match[1], "/") {
+			for _, color := range Colors {
+				if part == color {
+					pips[color]++
+				}
+			}
+		}
+	}
+
+	return pips
+}
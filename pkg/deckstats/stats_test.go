@@ -0,0 +1,106 @@
+package deckstats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var testCards = map[string]*scryfall.Card{
+	"Lightning Bolt": {Name: "Lightning Bolt", TypeLine: "Instant", ManaCost: "{R}", CMC: 1},
+	"Goblin Guide":   {Name: "Goblin Guide", TypeLine: "Creature — Goblin Scout", ManaCost: "{R}", CMC: 1},
+	"Boros Charm":    {Name: "Boros Charm", TypeLine: "Instant", ManaCost: "{R}{W}", CMC: 2},
+	"Figure of Destiny": {
+		Name: "Figure of Destiny", TypeLine: "Creature — Kithkin Spirit", ManaCost: "{R/W}", CMC: 1,
+	},
+	"Emrakul, the Aeons Torn": {Name: "Emrakul, the Aeons Torn", TypeLine: "Legendary Creature — Eldrazi", ManaCost: "{15}", CMC: 15},
+	"Mountain": {
+		Name: "Mountain", TypeLine: "Basic Land — Mountain",
+		ProducedMana: []scryfall.Color{scryfall.ColorRed},
+	},
+	"Sacred Foundry": {
+		Name: "Sacred Foundry", TypeLine: "Land — Mountain Plains",
+		ProducedMana: []scryfall.Color{scryfall.ColorRed, scryfall.ColorWhite},
+	},
+}
+
+func lookup(name string) (*scryfall.Card, error) {
+	if card, found := testCards[name]; found {
+		return card, nil
+	}
+
+	return nil, errors.New("unknown card")
+}
+
+func TestAnalyze(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Lightning Bolt"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Goblin Guide"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Boros Charm"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Figure of Destiny"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Emrakul, the Aeons Torn"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 16, Name: "Mountain"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Sacred Foundry"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Made Up Card"})
+	deck.Add(decklist.SectionSideboard, decklist.Entry{Count: 15, Name: "Mountain"})
+
+	stats := Analyze(deck, lookup)
+
+	if stats.Cards != 37 || stats.Lands != 20 {
+		t.Fatalf("expected 37 cards and 20 lands, got %d and %d", stats.Cards, stats.Lands)
+	}
+
+	if len(stats.Unknown) != 1 {
+		t.Fatalf("expected one unknown card, got %v", stats.Unknown)
+	}
+
+	expectedCurve := map[int]CurveBucket{
+		1: {CMC: 1, Permanents: 8, NonPermanents: 4},
+		2: {CMC: 2, NonPermanents: 4},
+		7: {CMC: 7, Permanents: 1},
+	}
+
+	for cmc, bucket := range expectedCurve {
+		if stats.Curve[cmc] != bucket {
+			t.Fatalf("expected %+v at mana value %d, got %+v", bucket, cmc, stats.Curve[cmc])
+		}
+	}
+
+	if stats.Pips["R"] != 16 || stats.Pips["W"] != 8 {
+		t.Fatalf("unexpected pips: %v", stats.Pips)
+	}
+
+	if stats.LandProduction["R"] != 20 || stats.LandProduction["W"] != 4 {
+		t.Fatalf("unexpected land production: %v", stats.LandProduction)
+	}
+
+	if stats.Types["Creature"] != 9 || stats.Types["Instant"] != 8 || stats.Types["Land"] != 20 {
+		t.Fatalf("unexpected types: %v", stats.Types)
+	}
+
+	// (4 + 4 + 8 + 4 + 15) / 17 spells
+	if expected := 35.0 / 17; stats.AverageCMCWithoutLands != expected {
+		t.Fatalf("expected average mana value %f, got %f", expected, stats.AverageCMCWithoutLands)
+	}
+}
+
+func TestRecommendedLands(t *testing.T) {
+	tests := []struct {
+		size     int
+		average  float64
+		expected int
+	}{
+		{60, 2, 23},
+		{40, 3, 17},
+		{100, 3, 41},
+	}
+
+	for _, test := range tests {
+		if lands := RecommendedLands(test.size, test.average); lands != test.expected {
+			t.Fatalf("%d cards, average %f: expected %d lands, got %d", test.size, test.average, test.expected, lands)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package fakeapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// the recorded responses, card objects of the scryfall api and the decks
+// of the deck sites. "{{base}}" in a card stands for the url of the server.
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+const basePlaceholder = "{{base}}"
+
+// Server is a fake scryfall api and deck sites, serving recorded fixtures.
+// It answers card searches, exact and fuzzy card names, the card names and
+// keyword catalogs, the sets and their icons, the symbology, card images,
+// the catalogs of the mtg api, and the decks of tappedout, moxfield,
+// archidekt, mtggoldfish and deckstats, so that the services which use them
+// can be tested without the internet.
+// The paths of the sites do not overlap, so that one server can stand in for
+// all of them.
+type Server struct {
+	*httptest.Server
+
+	mux      sync.Mutex
+	requests []string
+	cards    []map[string]any
+}
+
+// New starts a server, which has to be closed
+func New() (*Server, error) {
+	s := &Server{}
+
+	if err := s.loadCards(); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cards/search", s.search)
+	mux.HandleFunc("/cards/named", s.named)
+	mux.HandleFunc("/catalog/card-names", s.cardNames)
+	mux.HandleFunc("/catalog/", s.file("scryfall/catalog", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/sets", s.file("scryfall", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/symbology", s.file("scryfall", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/svgs/sets/", s.file("svgs/sets", "", "image/svg+xml"))
+	mux.HandleFunc("/v1/", s.file("mtgapi", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/images/", s.image)
+	mux.HandleFunc("/mtg-decks/", s.file("tappedout", ".html", "text/html; charset=utf-8"))
+	mux.HandleFunc("/v2/decks/all/", s.file("moxfield", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/api/decks/", s.file("archidekt", ".json", "application/json; charset=utf-8"))
+	mux.HandleFunc("/deck/download/", s.file("mtggoldfish", ".txt", "text/plain; charset=utf-8"))
+	mux.HandleFunc("/deck/", s.file("mtggoldfish", ".html", "text/html; charset=utf-8"))
+	mux.HandleFunc("/decks/", s.file("deckstats", ".txt", "text/plain; charset=utf-8"))
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mux.Lock()
+		s.requests = append(s.requests, r.URL.RequestURI())
+		s.mux.Unlock()
+
+		mux.ServeHTTP(w, r)
+	}))
+
+	return s, nil
+}
+
+// Requests yields the path and query of every request so far
+func (s *Server) Requests() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) loadCards() error {
+	files, err := fs.Glob(fixtures, "fixtures/scryfall/cards/*.json")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := fixtures.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		var card map[string]any
+		if err = json.Unmarshal(data, &card); err != nil {
+			return fmt.Errorf("decoding %s: %v", file, err)
+		}
+
+		s.cards = append(s.cards, card)
+	}
+
+	sort.Slice(s.cards, func(i, j int) bool {
+		return name(s.cards[i]) < name(s.cards[j])
+	})
+
+	return nil
+}
+
+// search finds the cards with every word of the query in their names,
+// which is all the services search for
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	words := strings.Fields(strings.ToLower(r.URL.Query().Get("q")))
+	found := make([]map[string]any, 0)
+
+	for _, card := range s.cards {
+		if containsAll(strings.ToLower(name(card)), words) {
+			found = append(found, card)
+		}
+	}
+
+	if len(found) == 0 {
+		s.notFound(w, "Your query didn’t match any cards.")
+		return
+	}
+
+	s.writeJSON(w, r, map[string]any{
+		"object":      "list",
+		"total_cards": len(found),
+		"has_more":    false,
+		"data":        found,
+	})
+}
+
+// named finds a card by its exact name or front face, fuzzy names are
+// matched like exact ones
+func (s *Server) named(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("exact")
+	if query == "" {
+		query = r.URL.Query().Get("fuzzy")
+	}
+
+	for _, card := range s.cards {
+		full := name(card)
+		front := strings.Split(full, " // ")[0]
+
+		if strings.EqualFold(full, query) || strings.EqualFold(front, query) {
+			s.writeJSON(w, r, card)
+			return
+		}
+	}
+
+	s.notFound(w, fmt.Sprintf("No cards found matching “%s”", query))
+}
+
+func (s *Server) cardNames(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(s.cards))
+
+	for _, card := range s.cards {
+		names = append(names, name(card))
+	}
+
+	s.writeJSON(w, r, map[string]any{
+		"object":       "catalog",
+		"total_values": len(names),
+		"data":         names,
+	})
+}
+
+func (s *Server) image(w http.ResponseWriter, r *http.Request) {
+	data, err := fixtures.ReadFile(path.Join("fixtures/images", path.Base(r.URL.Path)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, len(data)))
+	_, _ = w.Write(data)
+}
+
+// file serves the fixtures of a site, named after the last segment of the
+// path of a request, with the placeholders replaced by the url of the server
+func (s *Server) file(site, extension, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(strings.TrimSuffix(r.URL.Path, "/"))
+
+		data, err := fixtures.ReadFile(path.Join("fixtures", site, name+extension))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		data = []byte(strings.ReplaceAll(string(data), basePlaceholder, "http://"+r.Host))
+
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(data)
+	}
+}
+
+// writeJSON writes a response, with the placeholders of the fixtures
+// replaced by the url of the server
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	base := "http://" + r.Host
+	data = []byte(strings.ReplaceAll(string(data), basePlaceholder, base))
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// notFound writes an error object, like scryfall does
+func (s *Server) notFound(w http.ResponseWriter, details string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"object":  "error",
+		"code":    "not_found",
+		"status":  http.StatusNotFound,
+		"details": details,
+	})
+}
+
+func name(card map[string]any) string {
+	n, _ := card["name"].(string)
+	return n
+}
+
+func containsAll(s string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(s, word) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,230 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ActionKind describes what an action does to the game state
+type ActionKind string
+
+const (
+	// ActionJoin seats a player with the given deck
+	ActionJoin ActionKind = "join"
+
+	// ActionStart shuffles every library and draws opening hands
+	ActionStart ActionKind = "start"
+
+	// ActionDraw draws Amount cards (at least one)
+	ActionDraw ActionKind = "draw"
+
+	// ActionMove moves Object into the To zone
+	ActionMove ActionKind = "move"
+
+	// ActionTap taps Object
+	ActionTap ActionKind = "tap"
+
+	// ActionUntap untaps Object
+	ActionUntap ActionKind = "untap"
+
+	// ActionLife changes the life total of the acting player by Amount
+	ActionLife ActionKind = "life"
+
+	// ActionShuffle shuffles the library of the acting player
+	ActionShuffle ActionKind = "shuffle"
+
+	// ActionPass ends the turn of the active player
+	ActionPass ActionKind = "pass"
+
+	// ActionActivate pays the cost of, and resolves, the activated ability
+	// of Object with the index given in Amount
+	ActionActivate ActionKind = "activate"
+)
+
+var (
+	ErrNotStarted     = errors.New("game has not started")
+	ErrAlreadyStarted = errors.New("game has already started")
+	ErrNotSeated      = errors.New("player is not seated")
+	ErrNotYourTurn    = errors.New("not the active player")
+	ErrNotYourObject  = errors.New("object is owned by another player")
+	ErrUnknownObject  = errors.New("unknown object")
+)
+
+// Action is a single entry of the game action log. Sequence numbers are
+// assigned by the state when the action is applied.
+type Action struct {
+	Seq    int        `json:"seq"`
+	Player string     `json:"player"`
+	Kind   ActionKind `json:"kind"`
+	Object int        `json:"object,omitempty"`
+	To     Zone       `json:"to,omitempty"`
+	Amount int        `json:"amount,omitempty"`
+	Deck   []string   `json:"deck,omitempty"`
+}
+
+// Apply validates the action and applies it to the state. The state is left
+// untouched when an error is returned.
+func (s *State) Apply(a Action) error {
+	if locker, ok := s.Behaviors.(sync.Locker); ok {
+		locker.Lock()
+		defer locker.Unlock()
+	}
+
+	if err := s.validate(a); err != nil {
+		return err
+	}
+
+	next := s.clone()
+	if err := next.apply(a); err != nil {
+		return err
+	}
+
+	*s = *next
+
+	return nil
+}
+
+func (s *State) apply(a Action) error {
+	s.Seq++
+
+	switch a.Kind {
+	case ActionJoin:
+		p := newPlayer(a.Player)
+		for _, name := range a.Deck {
+			p.Zones[ZoneLibrary] = append(p.Zones[ZoneLibrary], s.newObject(name, p.Name))
+		}
+
+		s.Players = append(s.Players, p)
+	case ActionStart:
+		s.Started = true
+		s.Turn = 1
+		s.ActivePlayer = s.Players[0].Name
+
+		for _, p := range s.Players {
+			s.shuffle(p)
+
+			// opening hands are not draws, they do not trigger anything
+			library := p.Zones[ZoneLibrary]
+			count := min(startingHandSize, len(library))
+			p.Zones[ZoneHand] = append(p.Zones[ZoneHand], library[:count]...)
+			p.Zones[ZoneLibrary] = library[count:]
+		}
+	case ActionDraw:
+		return s.drawWithTriggers(s.Player(a.Player), max(a.Amount, 1), 0)
+	case ActionMove:
+		obj, from := s.Object(a.Object)
+		return s.moveWithTriggers(obj, from, a.To, 0)
+	case ActionTap:
+		obj, _ := s.Object(a.Object)
+		return s.tapWithTriggers(obj, 0)
+	case ActionUntap:
+		obj, _ := s.Object(a.Object)
+		obj.Tapped = false
+	case ActionLife:
+		s.Player(a.Player).Life += a.Amount
+	case ActionShuffle:
+		s.shuffle(s.Player(a.Player))
+	case ActionActivate:
+		obj, _ := s.Object(a.Object)
+		return s.activate(obj, a.Amount)
+	case ActionPass:
+		next := s.nextPlayer()
+		s.ActivePlayer = next.Name
+		s.Turn++
+
+		for _, obj := range next.Zones[ZoneBattlefield] {
+			obj.Tapped = false
+		}
+
+		if err := s.fire(Event{Kind: EventBeginTurn, Player: next.Name}, 0); err != nil {
+			return err
+		}
+
+		return s.drawWithTriggers(next, 1, 0)
+	}
+
+	return nil
+}
+
+func (s *State) validate(a Action) error {
+	if a.Kind == ActionJoin {
+		if s.Started {
+			return ErrAlreadyStarted
+		}
+
+		if a.Player == "" {
+			return errors.New("player name is required")
+		}
+
+		if s.Player(a.Player) != nil {
+			return fmt.Errorf("player %q is already seated", a.Player)
+		}
+
+		return nil
+	}
+
+	if s.Player(a.Player) == nil {
+		return ErrNotSeated
+	}
+
+	if a.Kind == ActionStart {
+		if s.Started {
+			return ErrAlreadyStarted
+		}
+
+		return nil
+	}
+
+	if !s.Started {
+		return ErrNotStarted
+	}
+
+	switch a.Kind {
+	case ActionDraw, ActionLife, ActionShuffle:
+		return nil
+	case ActionPass:
+		if a.Player != s.ActivePlayer {
+			return ErrNotYourTurn
+		}
+
+		return nil
+	case ActionActivate:
+		return s.validateActivate(a)
+	case ActionMove, ActionTap, ActionUntap:
+		obj, zone := s.Object(a.Object)
+		if obj == nil {
+			return ErrUnknownObject
+		}
+
+		if obj.Owner != a.Player {
+			return ErrNotYourObject
+		}
+
+		if a.Kind == ActionMove {
+			if !isZone(a.To) {
+				return fmt.Errorf("unknown zone %q", a.To)
+			}
+
+			return nil
+		}
+
+		if zone != ZoneBattlefield {
+			return fmt.Errorf("can only tap or untap objects on the battlefield")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %q", a.Kind)
+}
+
+func isZone(z Zone) bool {
+	for _, zone := range Zones {
+		if zone == z {
+			return true
+		}
+	}
+
+	return false
+}
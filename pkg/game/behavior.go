@@ -0,0 +1,383 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxTriggerDepth limits how deep triggered abilities can cause other
+// triggered abilities, so that a badly written card can not loop forever
+const maxTriggerDepth = 16
+
+// EventKind is something that happens in a game which can trigger abilities
+type EventKind string
+
+const (
+	// EventEntersBattlefield triggers the abilities of the object itself
+	EventEntersBattlefield EventKind = "enters the battlefield"
+
+	// EventLeavesBattlefield triggers the abilities of the object itself
+	EventLeavesBattlefield EventKind = "leaves the battlefield"
+
+	// EventDies triggers the abilities of the object itself, when it is put
+	// into a graveyard from the battlefield
+	EventDies EventKind = "dies"
+
+	// EventTapped triggers the abilities of the object itself
+	EventTapped EventKind = "tapped"
+
+	// EventBeginTurn triggers the abilities of every object on the
+	// battlefield controlled by the player whose turn begins
+	EventBeginTurn EventKind = "begin turn"
+
+	// EventDraw triggers the abilities of every object on the battlefield
+	// controlled by the player who drew a card
+	EventDraw EventKind = "draw"
+)
+
+// StaticScope selects which objects a static effect applies to
+type StaticScope string
+
+const (
+	ScopeSelf                      StaticScope = "self"
+	ScopeCreaturesYouControl       StaticScope = "creatures you control"
+	ScopeOtherCreaturesYouControl  StaticScope = "other creatures you control"
+	ScopeCreaturesOpponentsControl StaticScope = "creatures your opponents control"
+	ScopeAllCreatures              StaticScope = "all creatures"
+	ScopeAllOtherCreatures         StaticScope = "all other creatures"
+)
+
+var (
+	ErrUnknownAbility = errors.New("unknown ability")
+	ErrCostNotPaid    = errors.New("cost can not be paid")
+)
+
+// Behaviors supplies card behavior to a game, by card name. This is how
+// scripted cards are plugged into the rules. If the behaviors also implement
+// sync.Locker, they are locked while an action is applied, so that effects
+// do not have to be safe for concurrent use.
+type Behaviors interface {
+	Behavior(name string) *Behavior
+}
+
+// Behavior is everything a card can do on its own. A card without a
+// behavior is just a named object which players move around by hand.
+type Behavior struct {
+	// Creature is true if the card has a power and toughness
+	Creature  bool
+	Power     int
+	Toughness int
+
+	Triggers  []Trigger
+	Abilities []Ability
+	Statics   []Static
+}
+
+// Trigger is a triggered ability
+type Trigger struct {
+	Event  EventKind
+	Effect Effect
+}
+
+// Ability is an activated ability
+type Ability struct {
+	Text   string
+	Cost   Cost
+	Effect Effect
+}
+
+// Cost is what has to be paid to activate an ability
+type Cost struct {
+	Tap       bool
+	Life      int
+	Sacrifice bool
+}
+
+// Static is a static effect which modifies power and toughness
+type Static struct {
+	Scope     StaticScope
+	Power     int
+	Toughness int
+}
+
+// Effect changes the game, effects must be deterministic so that replaying
+// the action log yields the same game
+type Effect func(ctx *EffectContext) error
+
+// Event is a single occurrence of an EventKind
+type Event struct {
+	Kind   EventKind
+	Object *Object
+	Player string
+}
+
+// EffectContext is handed to effects, it is the only way for an effect to
+// change the game
+type EffectContext struct {
+	state  *State
+	depth  int
+	Source *Object
+	Event  *Event
+}
+
+// Controller is the player controlling the source of the effect
+func (ctx *EffectContext) Controller() string {
+	return ctx.Source.Owner
+}
+
+// Players yields the names of all players, in turn order
+func (ctx *EffectContext) Players() []string {
+	names := make([]string, 0, len(ctx.state.Players))
+
+	for _, p := range ctx.state.Players {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+// Opponents yields the names of all players except the controller
+func (ctx *EffectContext) Opponents() []string {
+	names := make([]string, 0)
+
+	for _, name := range ctx.Players() {
+		if name != ctx.Controller() {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Life yields the life total of a player
+func (ctx *EffectContext) Life(player string) (int, error) {
+	p := ctx.state.Player(player)
+	if p == nil {
+		return 0, ErrNotSeated
+	}
+
+	return p.Life, nil
+}
+
+// ChangeLife adds (or with a negative amount, removes) life
+func (ctx *EffectContext) ChangeLife(player string, amount int) error {
+	p := ctx.state.Player(player)
+	if p == nil {
+		return ErrNotSeated
+	}
+
+	p.Life += amount
+
+	return nil
+}
+
+// Draw makes a player draw cards
+func (ctx *EffectContext) Draw(player string, count int) error {
+	p := ctx.state.Player(player)
+	if p == nil {
+		return ErrNotSeated
+	}
+
+	return ctx.state.drawWithTriggers(p, count, ctx.depth+1)
+}
+
+// Move moves an object to another zone of its owner
+func (ctx *EffectContext) Move(id int, to Zone) error {
+	obj, from := ctx.state.Object(id)
+	if obj == nil {
+		return ErrUnknownObject
+	}
+
+	if !isZone(to) {
+		return fmt.Errorf("unknown zone %q", to)
+	}
+
+	return ctx.state.moveWithTriggers(obj, from, to, ctx.depth+1)
+}
+
+// SetTapped taps or untaps an object on the battlefield
+func (ctx *EffectContext) SetTapped(id int, tapped bool) error {
+	obj, zone := ctx.state.Object(id)
+	if obj == nil || zone != ZoneBattlefield {
+		return ErrUnknownObject
+	}
+
+	if !tapped {
+		obj.Tapped = false
+		return nil
+	}
+
+	return ctx.state.tapWithTriggers(obj, ctx.depth+1)
+}
+
+func (s *State) behavior(obj *Object) *Behavior {
+	if s.Behaviors == nil || obj == nil {
+		return nil
+	}
+
+	return s.Behaviors.Behavior(obj.Name)
+}
+
+// fire resolves every triggered ability that the event triggers
+func (s *State) fire(e Event, depth int) error {
+	if depth > maxTriggerDepth {
+		return fmt.Errorf("triggered abilities nested deeper than %d", maxTriggerDepth)
+	}
+
+	var sources []*Object
+
+	switch e.Kind {
+	case EventBeginTurn, EventDraw:
+		if p := s.Player(e.Player); p != nil {
+			sources = append(sources, p.Zones[ZoneBattlefield]...)
+		}
+	default:
+		sources = append(sources, e.Object)
+	}
+
+	for _, source := range sources {
+		b := s.behavior(source)
+		if b == nil {
+			continue
+		}
+
+		for _, trigger := range b.Triggers {
+			if trigger.Event != e.Kind || trigger.Effect == nil {
+				continue
+			}
+
+			ctx := &EffectContext{state: s, depth: depth, Source: source, Event: &e}
+			if err := trigger.Effect(ctx); err != nil {
+				return fmt.Errorf("%s (%s): %v", source.Name, e.Kind, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *State) activate(obj *Object, index int) error {
+	ability := s.behavior(obj).Abilities[index]
+
+	if ability.Cost.Life > 0 {
+		s.Player(obj.Owner).Life -= ability.Cost.Life
+	}
+
+	if ability.Cost.Tap {
+		if err := s.tapWithTriggers(obj, 0); err != nil {
+			return err
+		}
+	}
+
+	if ability.Cost.Sacrifice {
+		if err := s.moveWithTriggers(obj, ZoneBattlefield, ZoneGraveyard, 0); err != nil {
+			return err
+		}
+	}
+
+	if ability.Effect == nil {
+		return nil
+	}
+
+	ctx := &EffectContext{state: s, Source: obj}
+	if err := ability.Effect(ctx); err != nil {
+		return fmt.Errorf("%s: %v", obj.Name, err)
+	}
+
+	return nil
+}
+
+func (s *State) validateActivate(a Action) error {
+	obj, zone := s.Object(a.Object)
+	if obj == nil {
+		return ErrUnknownObject
+	}
+
+	if obj.Owner != a.Player {
+		return ErrNotYourObject
+	}
+
+	if zone != ZoneBattlefield {
+		return fmt.Errorf("can only activate abilities of objects on the battlefield")
+	}
+
+	b := s.behavior(obj)
+	if b == nil || a.Amount < 0 || a.Amount >= len(b.Abilities) {
+		return ErrUnknownAbility
+	}
+
+	cost := b.Abilities[a.Amount].Cost
+
+	if cost.Tap && obj.Tapped {
+		return fmt.Errorf("%w: already tapped", ErrCostNotPaid)
+	}
+
+	if cost.Life > s.Player(a.Player).Life {
+		return fmt.Errorf("%w: not enough life", ErrCostNotPaid)
+	}
+
+	return nil
+}
+
+// Stats yields the power and toughness of an object, including the static
+// effects of every object on the battlefield. The last result is false if
+// the object is not a creature.
+func (s *State) Stats(obj *Object) (power, toughness int, isCreature bool) {
+	b := s.behavior(obj)
+	if b == nil || !b.Creature {
+		return 0, 0, false
+	}
+
+	power, toughness = b.Power, b.Toughness
+
+	_, zone := s.Object(obj.ID)
+	if zone != ZoneBattlefield {
+		return power, toughness, true
+	}
+
+	for _, p := range s.Players {
+		for _, source := range p.Zones[ZoneBattlefield] {
+			sb := s.behavior(source)
+			if sb == nil {
+				continue
+			}
+
+			for _, static := range sb.Statics {
+				if !s.staticApplies(static.Scope, source, obj) {
+					continue
+				}
+
+				power += static.Power
+				toughness += static.Toughness
+			}
+		}
+	}
+
+	return power, toughness, true
+}
+
+func (s *State) staticApplies(scope StaticScope, source, target *Object) bool {
+	if scope == "" {
+		scope = ScopeSelf
+	}
+
+	isSelf := source.ID == target.ID
+	isYours := source.Owner == target.Owner
+
+	switch scope {
+	case ScopeSelf:
+		return isSelf
+	case ScopeCreaturesYouControl:
+		return isYours
+	case ScopeOtherCreaturesYouControl:
+		return isYours && !isSelf
+	case ScopeCreaturesOpponentsControl:
+		return !isYours
+	case ScopeAllCreatures:
+		return true
+	case ScopeAllOtherCreatures:
+		return !isSelf
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+type testBehaviors map[string]*Behavior
+
+func (b testBehaviors) Behavior(name string) *Behavior {
+	return b[name]
+}
+
+func behaviorTestState(t *testing.T, deck []string) *State {
+	b := testBehaviors{
+		"Elvish Visionary": {
+			Creature: true, Power: 1, Toughness: 1,
+			Triggers: []Trigger{{Event: EventEntersBattlefield, Effect: func(ctx *EffectContext) error {
+				return ctx.Draw(ctx.Controller(), 1)
+			}}},
+		},
+		"Glorious Anthem": {
+			Statics: []Static{{Scope: ScopeCreaturesYouControl, Power: 1, Toughness: 1}},
+		},
+		"Prodigal Pyromancer": {
+			Creature: true, Power: 1, Toughness: 1,
+			Abilities: []Ability{{Text: "deal 1 damage", Cost: Cost{Tap: true}, Effect: func(ctx *EffectContext) error {
+				return ctx.ChangeLife(ctx.Opponents()[0], -1)
+			}}},
+		},
+	}
+
+	s := NewState(1, b)
+
+	for _, a := range []Action{
+		{Player: "alice", Kind: ActionJoin, Deck: deck},
+		{Player: "bob", Kind: ActionJoin, Deck: testDeck()},
+		{Player: "alice", Kind: ActionStart},
+	} {
+		if err := s.Apply(a); err != nil {
+			t.Fatalf("setting up game: %v", err)
+		}
+	}
+
+	return s
+}
+
+// find yields the first object with the given name in one of alice's zones
+func find(t *testing.T, s *State, zone Zone, name string) *Object {
+	for _, obj := range s.Player("alice").Zones[zone] {
+		if obj.Name == name {
+			return obj
+		}
+	}
+
+	t.Fatalf("no %s in %s", name, zone)
+
+	return nil
+}
+
+func TestTriggeredAbilityResolves(t *testing.T) {
+	deck := make([]string, 0)
+	for i := 0; i < 20; i++ {
+		deck = append(deck, "Elvish Visionary")
+	}
+
+	s := behaviorTestState(t, deck)
+	handSize := len(s.Player("alice").Zones[ZoneHand])
+
+	elf := find(t, s, ZoneHand, "Elvish Visionary")
+	if err := s.Apply(Action{Player: "alice", Kind: ActionMove, Object: elf.ID, To: ZoneBattlefield}); err != nil {
+		t.Fatalf("casting elf: %v", err)
+	}
+
+	// one card left the hand, one was drawn by the trigger
+	if len(s.Player("alice").Zones[ZoneHand]) != handSize {
+		t.Fatalf("expected the enters the battlefield trigger to draw a card")
+	}
+}
+
+func TestStaticEffectsModifyStats(t *testing.T) {
+	deck := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		deck = append(deck, "Glorious Anthem", "Prodigal Pyromancer")
+	}
+
+	s := behaviorTestState(t, deck)
+
+	for _, name := range []string{"Glorious Anthem", "Prodigal Pyromancer"} {
+		obj := find(t, s, ZoneHand, name)
+		if err := s.Apply(Action{Player: "alice", Kind: ActionMove, Object: obj.ID, To: ZoneBattlefield}); err != nil {
+			t.Fatalf("playing %s: %v", name, err)
+		}
+	}
+
+	pyromancer := find(t, s, ZoneBattlefield, "Prodigal Pyromancer")
+
+	if power, toughness, _ := s.Stats(pyromancer); power != 2 || toughness != 2 {
+		t.Fatalf("expected 2/2, got %d/%d", power, toughness)
+	}
+}
+
+func TestActivatedAbilityPaysCosts(t *testing.T) {
+	deck := make([]string, 0)
+	for i := 0; i < 20; i++ {
+		deck = append(deck, "Prodigal Pyromancer")
+	}
+
+	s := behaviorTestState(t, deck)
+
+	pyromancer := find(t, s, ZoneHand, "Prodigal Pyromancer")
+	if err := s.Apply(Action{Player: "alice", Kind: ActionMove, Object: pyromancer.ID, To: ZoneBattlefield}); err != nil {
+		t.Fatalf("casting pyromancer: %v", err)
+	}
+
+	activate := Action{Player: "alice", Kind: ActionActivate, Object: pyromancer.ID}
+
+	if err := s.Apply(activate); err != nil {
+		t.Fatalf("activating: %v", err)
+	}
+
+	if life := s.Player("bob").Life; life != startingLife-1 {
+		t.Fatalf("expected bob at %d life, got %d", startingLife-1, life)
+	}
+
+	if err := s.Apply(activate); !errors.Is(err, ErrCostNotPaid) {
+		t.Fatalf("expected %v, got %v", ErrCostNotPaid, err)
+	}
+}
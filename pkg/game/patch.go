@@ -0,0 +1,122 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Patch is a JSON merge patch (RFC 7386). Keys that are set to nil have been
+// removed, objects are merged recursively, and everything else (including
+// arrays) replaces the previous value.
+type Patch map[string]any
+
+// Diff creates the merge patch which turns the `from` view into the `to` view
+func Diff(from, to View) (Patch, error) {
+	a, err := toDocument(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := toDocument(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffDocuments(a, b), nil
+}
+
+// Merge applies the patch to a view, this is what clients do with the
+// updates they receive.
+func Merge(v View, p Patch) (View, error) {
+	doc, err := toDocument(v)
+	if err != nil {
+		return View{}, err
+	}
+
+	data, err := json.Marshal(mergeDocuments(doc, p))
+	if err != nil {
+		return View{}, err
+	}
+
+	var result View
+	if err = json.Unmarshal(data, &result); err != nil {
+		return View{}, err
+	}
+
+	return result, nil
+}
+
+func toDocument(v View) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]any)
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func diffDocuments(a, b map[string]any) Patch {
+	patch := make(Patch)
+
+	for key := range a {
+		if _, found := b[key]; !found {
+			patch[key] = nil
+		}
+	}
+
+	for key, valueB := range b {
+		valueA, found := a[key]
+
+		objectA, isObjectA := valueA.(map[string]any)
+		objectB, isObjectB := valueB.(map[string]any)
+
+		if found && isObjectA && isObjectB {
+			if sub := diffDocuments(objectA, objectB); len(sub) > 0 {
+				patch[key] = map[string]any(sub)
+			}
+
+			continue
+		}
+
+		if !found || !reflect.DeepEqual(valueA, valueB) {
+			patch[key] = valueB
+		}
+	}
+
+	return patch
+}
+
+func mergeDocuments(doc map[string]any, patch map[string]any) map[string]any {
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(doc, key)
+			continue
+		}
+
+		subPatch, isObject := value.(map[string]any)
+		if !isObject {
+			if p, ok := value.(Patch); ok {
+				subPatch, isObject = p, true
+			}
+		}
+
+		if isObject {
+			subDoc, _ := doc[key].(map[string]any)
+			doc[key] = mergeDocuments(subDoc, subPatch)
+			continue
+		}
+
+		doc[key] = value
+	}
+
+	return doc
+}
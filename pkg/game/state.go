@@ -0,0 +1,247 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+const (
+	startingLife     = 20
+	startingHandSize = 7
+)
+
+// Zone is a place where a card object can be during a game
+type Zone string
+
+const (
+	ZoneLibrary     Zone = "library"
+	ZoneHand        Zone = "hand"
+	ZoneBattlefield Zone = "battlefield"
+	ZoneGraveyard   Zone = "graveyard"
+	ZoneExile       Zone = "exile"
+)
+
+// Zones is the list of all zones, in display order
+var Zones = []Zone{ZoneLibrary, ZoneHand, ZoneBattlefield, ZoneGraveyard, ZoneExile}
+
+// Object is a single card instance inside a game
+type Object struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Owner  string `json:"owner"`
+	Tapped bool   `json:"tapped,omitempty"`
+
+	// Power and Toughness are only set in views, for creatures
+	Power     *int `json:"power,omitempty"`
+	Toughness *int `json:"toughness,omitempty"`
+}
+
+// Player is a seated participant of a game
+type Player struct {
+	Name  string
+	Life  int
+	Zones map[Zone][]*Object
+}
+
+// State is the authoritative state of a game. A state is only ever changed
+// by applying actions, so that replaying the action log from a new state
+// with the same seed always yields the same result.
+type State struct {
+	Seed         int64
+	Seq          int
+	Started      bool
+	Turn         int
+	ActivePlayer string
+	Players      []*Player
+
+	// Behaviors is optional, without it cards do nothing on their own
+	Behaviors Behaviors
+
+	nextObjectID int
+}
+
+// NewState creates an empty game state that uses the given seed for shuffling
+func NewState(seed int64, behaviors Behaviors) *State {
+	return &State{Seed: seed, Behaviors: behaviors}
+}
+
+// Replay creates a new state from the seed and applies every action in the log
+func Replay(seed int64, behaviors Behaviors, log []Action) (*State, error) {
+	s := NewState(seed, behaviors)
+
+	for _, action := range log {
+		if err := s.Apply(action); err != nil {
+			return nil, fmt.Errorf("replaying action %d: %v", action.Seq, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Player finds a player by name
+func (s *State) Player(name string) *Player {
+	for _, p := range s.Players {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Object finds a card object by id, along with the zone it is in
+func (s *State) Object(id int) (*Object, Zone) {
+	for _, p := range s.Players {
+		for _, zone := range Zones {
+			for _, obj := range p.Zones[zone] {
+				if obj.ID == id {
+					return obj, zone
+				}
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+func (s *State) newObject(name, owner string) *Object {
+	s.nextObjectID++
+
+	return &Object{ID: s.nextObjectID, Name: name, Owner: owner}
+}
+
+func (s *State) shuffle(p *Player) {
+	library := p.Zones[ZoneLibrary]
+
+	// derive a source from the seed, the sequence number and the seat of
+	// the player so that every shuffle is different, even those of one
+	// action, but replays are deterministic
+	seat := slices.Index(s.Players, p)
+	rng := rand.New(rand.NewSource(s.Seed + int64(s.Seq)*int64(len(s.Players)+1) + int64(seat)))
+	rng.Shuffle(len(library), func(i, j int) {
+		library[i], library[j] = library[j], library[i]
+	})
+}
+
+func (s *State) drawWithTriggers(p *Player, count, depth int) error {
+	for ; count > 0; count-- {
+		library := p.Zones[ZoneLibrary]
+		if len(library) < 1 {
+			return nil
+		}
+
+		top := library[0]
+		p.Zones[ZoneLibrary] = library[1:]
+		p.Zones[ZoneHand] = append(p.Zones[ZoneHand], top)
+
+		if err := s.fire(Event{Kind: EventDraw, Object: top, Player: p.Name}, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *State) moveWithTriggers(obj *Object, from, to Zone, depth int) error {
+	p := s.Player(obj.Owner)
+
+	for idx, candidate := range p.Zones[from] {
+		if candidate.ID == obj.ID {
+			p.Zones[from] = append(p.Zones[from][:idx:idx], p.Zones[from][idx+1:]...)
+			break
+		}
+	}
+
+	if to != ZoneBattlefield {
+		obj.Tapped = false
+	}
+
+	if to == ZoneLibrary {
+		// cards put into the library go on top
+		p.Zones[to] = append([]*Object{obj}, p.Zones[to]...)
+	} else {
+		p.Zones[to] = append(p.Zones[to], obj)
+	}
+
+	if from == to {
+		return nil
+	}
+
+	var events []EventKind
+
+	switch {
+	case to == ZoneBattlefield:
+		events = append(events, EventEntersBattlefield)
+	case from == ZoneBattlefield && to == ZoneGraveyard:
+		events = append(events, EventLeavesBattlefield, EventDies)
+	case from == ZoneBattlefield:
+		events = append(events, EventLeavesBattlefield)
+	}
+
+	for _, kind := range events {
+		if err := s.fire(Event{Kind: kind, Object: obj, Player: obj.Owner}, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *State) tapWithTriggers(obj *Object, depth int) error {
+	if obj.Tapped {
+		return nil
+	}
+
+	obj.Tapped = true
+
+	return s.fire(Event{Kind: EventTapped, Object: obj, Player: obj.Owner}, depth)
+}
+
+func (s *State) nextPlayer() *Player {
+	for idx, p := range s.Players {
+		if p.Name == s.ActivePlayer {
+			return s.Players[(idx+1)%len(s.Players)]
+		}
+	}
+
+	return nil
+}
+
+// clone creates a deep copy of the state, so that a failing action can be
+// discarded without leaving anything half applied
+func (s *State) clone() *State {
+	c := *s
+	c.Players = make([]*Player, 0, len(s.Players))
+
+	for _, p := range s.Players {
+		cp := &Player{Name: p.Name, Life: p.Life, Zones: make(map[Zone][]*Object)}
+
+		for zone, objects := range p.Zones {
+			cp.Zones[zone] = make([]*Object, 0, len(objects))
+
+			for _, obj := range objects {
+				copied := *obj
+				cp.Zones[zone] = append(cp.Zones[zone], &copied)
+			}
+		}
+
+		c.Players = append(c.Players, cp)
+	}
+
+	return &c
+}
+
+func newPlayer(name string) *Player {
+	p := &Player{
+		Name:  name,
+		Life:  startingLife,
+		Zones: make(map[Zone][]*Object),
+	}
+
+	for _, zone := range Zones {
+		p.Zones[zone] = make([]*Object, 0)
+	}
+
+	return p
+}
@@ -0,0 +1,317 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// updateBufferSize is how many updates can be queued for a seat before the
+// seat is considered too slow and gets disconnected. A disconnected client
+// can catch up again with Rejoin.
+const updateBufferSize = 64
+
+var (
+	ErrNameTaken    = errors.New("name is already taken at this table")
+	ErrInvalidToken = errors.New("invalid reconnect token")
+	ErrSeatClosed   = errors.New("seat is closed")
+)
+
+// Update is sent to a seat whenever the game changes. The first update a
+// seat receives after joining carries a full snapshot, every update after
+// that carries a merge patch against the previous view.
+type Update struct {
+	Seq      int    `json:"seq"`
+	Since    int    `json:"since,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Snapshot *View  `json:"snapshot,omitempty"`
+	Patch    Patch  `json:"patch,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Table is a single game session. It owns the authoritative state and the
+// action log, and fans out per-player state diffs to every connected seat.
+type Table struct {
+	ID string
+
+	mux    sync.Mutex
+	seed   int64
+	state  *State
+	log    []Action
+	seats  map[string]*Seat
+	tokens map[string]string
+}
+
+// NewTable creates an empty table, using the seed for all shuffles. The
+// behaviors are optional. The table keeps the first behavior it gets for
+// every card, so that a script reloaded during the game changes neither the
+// game nor the replays of its action log.
+func NewTable(id string, seed int64, behaviors Behaviors) *Table {
+	if behaviors != nil {
+		behaviors = &pinnedBehaviors{
+			behaviors: behaviors,
+			pinned:    make(map[string]*Behavior),
+		}
+	}
+
+	return &Table{
+		ID:     id,
+		seed:   seed,
+		state:  NewState(seed, behaviors),
+		log:    make([]Action, 0),
+		seats:  make(map[string]*Seat),
+		tokens: make(map[string]string),
+	}
+}
+
+// Join connects a new client to the table under the given name. The client
+// must send an ActionJoin with its deck to take a seat in the game, until
+// then it is a spectator. The token in the first update is required to
+// reconnect with Rejoin.
+func (t *Table) Join(name string) (*Seat, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if _, taken := t.tokens[name]; taken {
+		return nil, ErrNameTaken
+	}
+
+	token := uuid.NewString()
+	t.tokens[name] = token
+
+	seat := t.newSeat(name)
+	seat.view = t.state.View(name)
+	seat.send(Update{Seq: t.state.Seq, Token: token, Snapshot: &seat.view})
+
+	return seat, nil
+}
+
+// Rejoin reconnects a client that has previously joined. When `since` is
+// the sequence number of the last update the client applied, the client is
+// sent a single patch computed by replaying the action log, otherwise it is
+// sent a fresh snapshot. Any existing connection for the name is closed.
+func (t *Table) Rejoin(name, token string, since int) (*Seat, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if expected, found := t.tokens[name]; !found || expected != token {
+		return nil, ErrInvalidToken
+	}
+
+	seat := t.newSeat(name)
+	current := t.state.View(name)
+
+	if since <= 0 || since > t.state.Seq {
+		seat.view = current
+		seat.send(Update{Seq: t.state.Seq, Snapshot: &seat.view})
+
+		return seat, nil
+	}
+
+	previous, err := Replay(t.seed, t.state.Behaviors, t.log[:since])
+	if err != nil {
+		return nil, fmt.Errorf("rebuilding state at %d: %v", since, err)
+	}
+
+	patch, err := Diff(previous.View(name), current)
+	if err != nil {
+		return nil, fmt.Errorf("creating patch: %v", err)
+	}
+
+	seat.view = current
+	seat.send(Update{Seq: t.state.Seq, Since: since, Patch: patch})
+
+	return seat, nil
+}
+
+// Log returns a copy of the action log
+func (t *Table) Log() []Action {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return append([]Action(nil), t.log...)
+}
+
+// Seed returns the seed the table was created with
+func (t *Table) Seed() int64 {
+	return t.seed
+}
+
+// Players returns the names of all seated players, in turn order
+func (t *Table) Players() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	names := make([]string, 0, len(t.state.Players))
+	for _, p := range t.state.Players {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+// Started returns true once the game has started
+func (t *Table) Started() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return t.state.Started
+}
+
+func (t *Table) newSeat(name string) *Seat {
+	if existing, found := t.seats[name]; found {
+		existing.close()
+	}
+
+	seat := &Seat{
+		table:   t,
+		name:    name,
+		updates: make(chan Update, updateBufferSize),
+	}
+
+	t.seats[name] = seat
+
+	return seat
+}
+
+func (t *Table) do(seat *Seat, a Action) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if seat.closed {
+		return ErrSeatClosed
+	}
+
+	a.Player = seat.name
+	a.Seq = t.state.Seq + 1
+
+	if err := t.state.Apply(a); err != nil {
+		return err
+	}
+
+	t.log = append(t.log, a)
+
+	for _, other := range t.seats {
+		view := t.state.View(other.name)
+
+		patch, err := Diff(other.view, view)
+		if err != nil {
+			other.close()
+			continue
+		}
+
+		other.view = view
+		other.send(Update{Seq: t.state.Seq, Patch: patch})
+	}
+
+	return nil
+}
+
+func (t *Table) leave(seat *Seat) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	seat.close()
+}
+
+// pinnedBehaviors yields the behavior a card had the first time it was
+// looked up, for the whole lifetime of a table
+type pinnedBehaviors struct {
+	behaviors Behaviors
+	mux       sync.Mutex
+	pinned    map[string]*Behavior
+}
+
+func (p *pinnedBehaviors) Behavior(name string) *Behavior {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if behavior, found := p.pinned[name]; found {
+		return behavior
+	}
+
+	behavior := p.behaviors.Behavior(name)
+	p.pinned[name] = behavior
+
+	return behavior
+}
+
+// Lock satisfies sync.Locker, the behaviors are still locked while an
+// action is applied
+func (p *pinnedBehaviors) Lock() {
+	if locker, ok := p.behaviors.(sync.Locker); ok {
+		locker.Lock()
+	}
+}
+
+// Unlock satisfies sync.Locker
+func (p *pinnedBehaviors) Unlock() {
+	if locker, ok := p.behaviors.(sync.Locker); ok {
+		locker.Unlock()
+	}
+}
+
+// Seat is a single client connection to a table
+type Seat struct {
+	table   *Table
+	name    string
+	updates chan Update
+	view    View
+	closed  bool
+}
+
+// Name returns the player name of the seat
+func (s *Seat) Name() string {
+	return s.name
+}
+
+// Updates yields the update stream of the seat. The channel is closed when
+// the seat is closed.
+func (s *Seat) Updates() <-chan Update {
+	return s.updates
+}
+
+// Do applies an action on behalf of the seat's player
+func (s *Seat) Do(a Action) error {
+	return s.table.do(s, a)
+}
+
+// Leave closes the seat. The player stays in the game and can reconnect
+// with Rejoin.
+func (s *Seat) Leave() {
+	s.table.leave(s)
+}
+
+// send must only be called while holding the table lock
+func (s *Seat) send(u Update) {
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.updates <- u:
+	default:
+		// the client is not keeping up, it will have to rejoin
+		s.close()
+	}
+}
+
+// close must only be called while holding the table lock
+func (s *Seat) close() {
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	close(s.updates)
+
+	if s.table.seats[s.name] == s {
+		delete(s.table.seats, s.name)
+	}
+}
@@ -0,0 +1,274 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// client is an in-process client which keeps its view in sync by applying
+// the updates it receives, the same way a remote client would.
+type client struct {
+	t     *testing.T
+	seat  *Seat
+	token string
+	view  View
+}
+
+func connect(t *testing.T, table *Table, name string) *client {
+	seat, err := table.Join(name)
+	if err != nil {
+		t.Fatalf("joining table: %v", err)
+	}
+
+	c := &client{t: t, seat: seat}
+	c.sync()
+
+	return c
+}
+
+func (c *client) sync() {
+	for {
+		select {
+		case u, ok := <-c.seat.Updates():
+			if !ok {
+				return
+			}
+
+			c.apply(u)
+		default:
+			return
+		}
+	}
+}
+
+func (c *client) apply(u Update) {
+	if u.Token != "" {
+		c.token = u.Token
+	}
+
+	if u.Snapshot != nil {
+		c.view = *u.Snapshot
+		return
+	}
+
+	view, err := Merge(c.view, u.Patch)
+	if err != nil {
+		c.t.Fatalf("merging patch: %v", err)
+	}
+
+	c.view = view
+}
+
+func (c *client) do(a Action) {
+	if err := c.seat.Do(a); err != nil {
+		c.t.Fatalf("%s doing %q: %v", c.seat.Name(), a.Kind, err)
+	}
+}
+
+func testDeck() []string {
+	deck := make([]string, 0)
+
+	for i := 0; i < 20; i++ {
+		deck = append(deck, "Lightning Bolt", "Mountain")
+	}
+
+	return deck
+}
+
+func TestTableClientsStayInSync(t *testing.T) {
+	table := NewTable("test", 1, nil)
+
+	alice := connect(t, table, "alice")
+	bob := connect(t, table, "bob")
+
+	alice.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	bob.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+
+	alice.sync()
+	bob.sync()
+
+	for _, c := range []*client{alice, bob} {
+		expected := table.state.View(c.seat.Name())
+		if !reflect.DeepEqual(normalize(c.view), normalize(expected)) {
+			t.Fatalf("view of %s is out of sync:\n%+v\n%+v", c.seat.Name(), c.view, expected)
+		}
+	}
+
+	if len(alice.view.Players["alice"].Hand) != startingHandSize {
+		t.Fatalf("expected alice to see her own hand")
+	}
+
+	if len(alice.view.Players["bob"].Hand) != 0 {
+		t.Fatalf("alice can see bob's hand")
+	}
+
+	if alice.view.Players["bob"].HandSize != startingHandSize {
+		t.Fatalf("expected alice to see the size of bob's hand")
+	}
+}
+
+func TestTableRejectsActionsOnOtherPlayersObjects(t *testing.T) {
+	table := NewTable("test", 1, nil)
+
+	alice := connect(t, table, "alice")
+	bob := connect(t, table, "bob")
+
+	alice.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	bob.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+	alice.sync()
+
+	card := alice.view.Players["alice"].Hand[0]
+
+	if err := bob.seat.Do(Action{Kind: ActionMove, Object: card.ID, To: ZoneGraveyard}); err != ErrNotYourObject {
+		t.Fatalf("expected %v, got %v", ErrNotYourObject, err)
+	}
+
+	if err := bob.seat.Do(Action{Kind: ActionPass}); err != ErrNotYourTurn {
+		t.Fatalf("expected %v, got %v", ErrNotYourTurn, err)
+	}
+}
+
+func TestTableRejoinCatchesUpFromActionLog(t *testing.T) {
+	table := NewTable("test", 7, nil)
+
+	alice := connect(t, table, "alice")
+	bob := connect(t, table, "bob")
+
+	alice.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	bob.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+	bob.sync()
+
+	bob.seat.Leave()
+	lastSeen := bob.view.Seq
+
+	alice.sync()
+	card := alice.view.Players["alice"].Hand[0]
+	alice.do(Action{Kind: ActionMove, Object: card.ID, To: ZoneBattlefield})
+	alice.do(Action{Kind: ActionTap, Object: card.ID})
+	alice.do(Action{Kind: ActionPass})
+
+	seat, err := table.Rejoin("bob", bob.token, lastSeen)
+	if err != nil {
+		t.Fatalf("rejoining: %v", err)
+	}
+
+	bob.seat = seat
+	bob.sync()
+
+	expected := table.state.View("bob")
+	if !reflect.DeepEqual(normalize(bob.view), normalize(expected)) {
+		t.Fatalf("view after rejoin is out of sync:\n%+v\n%+v", bob.view, expected)
+	}
+
+	if _, err = table.Rejoin("bob", "wrong", lastSeen); err != ErrInvalidToken {
+		t.Fatalf("expected %v, got %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTableRejoinAfterScriptReload(t *testing.T) {
+	behaviors := testBehaviors{
+		"Grizzly Bears": {Creature: true, Power: 2, Toughness: 2},
+	}
+
+	deck := make([]string, 20)
+	for idx := range deck {
+		deck[idx] = "Grizzly Bears"
+	}
+
+	table := NewTable("test", 7, behaviors)
+
+	alice := connect(t, table, "alice")
+	bob := connect(t, table, "bob")
+
+	alice.do(Action{Kind: ActionJoin, Deck: deck})
+	bob.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+
+	alice.sync()
+	card := alice.view.Players["alice"].Hand[0]
+	alice.do(Action{Kind: ActionMove, Object: card.ID, To: ZoneBattlefield})
+	bob.sync()
+
+	bob.seat.Leave()
+	lastSeen := bob.view.Seq
+
+	// the script of the card is reloaded, now it costs life to cast it, so a
+	// replay with the reloaded script would have alice at 19 life already
+	behaviors["Grizzly Bears"] = &Behavior{
+		Creature: true, Power: 2, Toughness: 2,
+		Triggers: []Trigger{{Event: EventEntersBattlefield, Effect: func(ctx *EffectContext) error {
+			return ctx.ChangeLife(ctx.Controller(), -1)
+		}}},
+	}
+
+	alice.do(Action{Kind: ActionLife, Amount: -1})
+
+	seat, err := table.Rejoin("bob", bob.token, lastSeen)
+	if err != nil {
+		t.Fatalf("rejoining: %v", err)
+	}
+
+	bob.seat = seat
+	bob.sync()
+
+	expected := table.state.View("bob")
+	if !reflect.DeepEqual(normalize(bob.view), normalize(expected)) {
+		t.Fatalf("view after rejoin is out of sync:\n%+v\n%+v", bob.view, expected)
+	}
+}
+
+func TestReplayIsDeterministic(t *testing.T) {
+	table := NewTable("test", 42, nil)
+
+	alice := connect(t, table, "alice")
+	alice.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+	alice.do(Action{Kind: ActionShuffle})
+	alice.do(Action{Kind: ActionDraw, Amount: 3})
+
+	replayed, err := Replay(table.Seed(), nil, table.Log())
+	if err != nil {
+		t.Fatalf("replaying: %v", err)
+	}
+
+	if !reflect.DeepEqual(replayed.View("alice"), table.state.View("alice")) {
+		t.Fatal("replayed state differs from the table state")
+	}
+}
+
+// normalize round-trips a view through a merge so that empty and nil
+// slices compare equal
+func normalize(v View) View {
+	result, _ := Merge(v, Patch{})
+	return result
+}
+
+func TestPlayersAreShuffledDifferently(t *testing.T) {
+	table := NewTable("test", 42, nil)
+
+	alice := connect(t, table, "alice")
+	bob := connect(t, table, "bob")
+	alice.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	bob.do(Action{Kind: ActionJoin, Deck: testDeck()})
+	alice.do(Action{Kind: ActionStart})
+
+	// both decks are the same, so the same permutation yields the same order
+	order := func(name string) []string {
+		names := make([]string, 0)
+
+		for _, zone := range []Zone{ZoneHand, ZoneLibrary} {
+			for _, obj := range table.state.Player(name).Zones[zone] {
+				names = append(names, obj.Name)
+			}
+		}
+
+		return names
+	}
+
+	if reflect.DeepEqual(order("alice"), order("bob")) {
+		t.Fatal("expected the libraries of alice and bob to be shuffled differently")
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+// View is the game state as seen by a single player. Hidden information,
+// like the order of libraries and the cards in other players' hands, is
+// reduced to counts.
+type View struct {
+	Seq          int                   `json:"seq"`
+	Started      bool                  `json:"started"`
+	Turn         int                   `json:"turn"`
+	ActivePlayer string                `json:"activePlayer"`
+	Seats        []string              `json:"seats"`
+	Players      map[string]PlayerView `json:"players"`
+}
+
+// PlayerView is a single player's part of a View
+type PlayerView struct {
+	Life        int      `json:"life"`
+	Library     int      `json:"library"`
+	HandSize    int      `json:"handSize"`
+	Hand        []Object `json:"hand,omitempty"`
+	Battlefield []Object `json:"battlefield"`
+	Graveyard   []Object `json:"graveyard"`
+	Exile       []Object `json:"exile"`
+}
+
+// View yields the state as seen by the named player. Spectators (names that
+// are not seated) do not see any hand.
+func (s *State) View(viewer string) View {
+	v := View{
+		Seq:          s.Seq,
+		Started:      s.Started,
+		Turn:         s.Turn,
+		ActivePlayer: s.ActivePlayer,
+		Seats:        make([]string, 0, len(s.Players)),
+		Players:      make(map[string]PlayerView),
+	}
+
+	for _, p := range s.Players {
+		pv := PlayerView{
+			Life:        p.Life,
+			Library:     len(p.Zones[ZoneLibrary]),
+			HandSize:    len(p.Zones[ZoneHand]),
+			Battlefield: s.viewObjects(p.Zones[ZoneBattlefield]),
+			Graveyard:   s.viewObjects(p.Zones[ZoneGraveyard]),
+			Exile:       s.viewObjects(p.Zones[ZoneExile]),
+		}
+
+		if p.Name == viewer {
+			pv.Hand = s.viewObjects(p.Zones[ZoneHand])
+		}
+
+		v.Seats = append(v.Seats, p.Name)
+		v.Players[p.Name] = pv
+	}
+
+	return v
+}
+
+func (s *State) viewObjects(objects []*Object) []Object {
+	result := make([]Object, 0, len(objects))
+
+	for _, obj := range objects {
+		viewed := *obj
+
+		if power, toughness, isCreature := s.Stats(obj); isCreature {
+			viewed.Power, viewed.Toughness = &power, &toughness
+		}
+
+		result = append(result, viewed)
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package httplimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestTimeout is how long a request of a client may take, retries
+// included
+const RequestTimeout = time.Minute
+
+// NewClient creates the client of an api or a site. Requests are sent with
+// the base transport, like a fake server in tests, or else directly or
+// through the proxy when there is one. They keep to perSecond requests a
+// second, any number when it is 0, and are retried like with a Transport.
+// An invalid proxy url is an error, the client then connects directly.
+func NewClient(base http.RoundTripper, proxy string, perSecond int) (*http.Client, error) {
+	var err error
+
+	if base == nil {
+		base, err = proxyTransport(proxy)
+	}
+
+	return &http.Client{Transport: New(base, float64(perSecond), perSecond), Timeout: RequestTimeout}, err
+}
+
+// proxyTransport yields a transport which sends every request through a
+// proxy, or http.DefaultTransport when the proxy is empty or invalid
+func proxyTransport(proxy string) (http.RoundTripper, error) {
+	if proxy == "" {
+		return http.DefaultTransport, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return http.DefaultTransport, fmt.Errorf("invalid proxy url %q", proxy)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+
+	return transport, nil
+}
+
+// GetBytes fetches the body of a url, any status but 200 OK is an error
+func GetBytes(ctx context.Context, client *http.Client, uri string) ([]byte, error) {
+	res, err := get(ctx, client, uri, "*/*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", uri, err)
+	}
+
+	return data, nil
+}
+
+// GetJSON fetches the json body of a url into v, any status but 200 OK is
+// an error
+func GetJSON(ctx context.Context, client *http.Client, uri string, v any) error {
+	res, err := get(ctx, client, uri, "application/json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %v", uri, err)
+	}
+
+	return nil
+}
+
+func get(ctx context.Context, client *http.Client, uri, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %v", err)
+	}
+
+	req.Header.Set("Accept", accept)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("issuing http request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %s", uri, res.Status)
+	}
+
+	return res, nil
+}
+
+// BaseURL yields a configured base url, or the fallback when it is empty.
+// It always ends with a slash, so that the paths of an api are relative to
+// it.
+func BaseURL(configured, fallback string) string {
+	if configured == "" {
+		configured = fallback
+	}
+
+	return strings.TrimSuffix(configured, "/") + "/"
+}
@@ -0,0 +1,131 @@
+package httplimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// defaults of a Transport
+const (
+	DefaultRetries = 4
+	DefaultBackoff = 250 * time.Millisecond
+
+	// the longest wait for a Retry-After header
+	maxRetryAfter = 30 * time.Second
+)
+
+// Transport is an http.RoundTripper which limits the rate of requests with
+// a token bucket, and retries requests which failed with 429 Too Many
+// Requests or a 5xx status. Retries back off exponentially, unless the
+// server says when to retry with a Retry-After header. Waiting stops when
+// the context of the request is done.
+type Transport struct {
+	Base    http.RoundTripper
+	Limiter *rate.Limiter
+	Retries int
+	Backoff time.Duration
+}
+
+// New creates a transport which allows perSecond requests on average, any
+// number when it is 0, and up to burst requests at once. The base transport
+// is http.DefaultTransport when nil.
+func New(base http.RoundTripper, perSecond float64, burst int) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	limit := rate.Limit(perSecond)
+	if perSecond <= 0 {
+		limit = rate.Inf
+	}
+
+	return &Transport{
+		Base:    base,
+		Limiter: rate.NewLimiter(limit, max(burst, 1)),
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}
+}
+
+// RoundTrip satisfies the http.RoundTripper interface
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := t.Backoff
+
+	for attempt := 0; ; attempt++ {
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		// requests with a body can only be sent again if it can be rewound
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		res, err := t.Base.RoundTrip(req)
+		if err != nil || !retryable(res.StatusCode) || attempt >= t.Retries || !rewindable(req) {
+			return res, err
+		}
+
+		wait := retryAfter(res.Header.Get("Retry-After"))
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+		}
+
+		_ = res.Body.Close()
+
+		if err = sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryAfter reads a Retry-After header in seconds or as a date
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	var wait time.Duration
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, errParse := http.ParseTime(header); errParse == nil {
+		wait = time.Until(at)
+	}
+
+	return min(wait, maxRetryAfter)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
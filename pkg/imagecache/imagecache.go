@@ -0,0 +1,373 @@
+package imagecache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const indexFileName = "index.json"
+
+// saveInterval is how often the index is saved when only the access and
+// validation times of the files changed
+const saveInterval = time.Minute
+
+// defaults of the options
+const (
+	DefaultLimit  = 1 << 30
+	DefaultMaxAge = 7 * 24 * time.Hour
+)
+
+// ErrNotCached is yielded by Cached for keys which are not in the cache
+var ErrNotCached = errors.New("not in the image cache")
+
+// Options configure a cache. Zero values are the defaults.
+type Options struct {
+	// Limit is the most bytes the cached files may take on disk
+	Limit int64
+
+	// MaxAge is how long cached files are used without asking the server
+	// if they changed
+	MaxAge time.Duration
+
+	// Client downloads the files, http.DefaultClient by default
+	Client *http.Client
+}
+
+// Entry is a cached file
+type Entry struct {
+	URL          string    `json:"url"`
+	File         string    `json:"file"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Validated    time.Time `json:"validated"`
+	Accessed     time.Time `json:"accessed"`
+}
+
+// Cache keeps downloaded files in a directory, by key. Files older than the
+// max age are revalidated with the server, using their ETag and
+// Last-Modified headers, and the least recently used files are removed when
+// the cache grows beyond its limit. The index of the files is saved when
+// files are added, and every so often when files are used. Close saves it
+// for good.
+type Cache struct {
+	dir  string
+	opts Options
+
+	mux     sync.Mutex
+	entries map[string]*Entry
+	size    int64
+	dirty   bool
+	saved   time.Time
+}
+
+// Open opens the cache in a directory, which is created if needed
+func Open(dir string, opts Options) (*Cache, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating image cache directory: %v", err)
+	}
+
+	c := &Cache{dir: dir, opts: opts, entries: make(map[string]*Entry), saved: time.Now()}
+
+	data, err := os.ReadFile(filepath.Join(dir, indexFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading image cache index: %v", err)
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(data, &c.entries); err != nil {
+			return nil, fmt.Errorf("decoding image cache index: %v", err)
+		}
+	}
+
+	for key, e := range c.entries {
+		// files removed behind the back of the cache
+		if _, errStat := os.Stat(filepath.Join(dir, e.File)); errStat != nil {
+			delete(c.entries, key)
+			continue
+		}
+
+		c.size += e.Size
+	}
+
+	return c, nil
+}
+
+// Size yields the bytes the cached files take
+func (c *Cache) Size() int64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.size
+}
+
+// Get yields the file of a key, downloading it from the url if it is not
+// cached. Files older than the max age are revalidated first. If the server
+// can not be reached, the cached file is used anyway, unless the context is
+// done.
+func (c *Cache) Get(ctx context.Context, key, url string) ([]byte, error) {
+	c.mux.Lock()
+	entry, found := c.entries[key]
+	if found && entry.URL != url {
+		// a new image, scryfall changes the url when an image is updated
+		found = false
+	}
+
+	var cached Entry
+	if found {
+		cached = *entry
+	}
+	c.mux.Unlock()
+
+	if found && time.Since(cached.Validated) < c.opts.MaxAge {
+		if data, err := c.read(key, cached); err == nil {
+			return data, nil
+		}
+
+		found = false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+
+	if found {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	res, err := c.opts.Client.Do(req)
+	if err != nil {
+		if found && ctx.Err() == nil {
+			return c.read(key, cached)
+		}
+
+		return nil, fmt.Errorf("issuing http request: %v", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && found:
+		c.touch(key, func(e *Entry) { e.Validated = time.Now() })
+
+		return c.read(key, cached)
+	case res.StatusCode != http.StatusOK:
+		if found {
+			return c.read(key, cached)
+		}
+
+		return nil, fmt.Errorf("downloading %s: %s", url, res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %v", err)
+	}
+
+	now := time.Now()
+
+	err = c.put(key, &Entry{
+		URL:          url,
+		Size:         int64(len(data)),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		Validated:    now,
+		Accessed:     now,
+	}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Cached yields the file of a key without asking the server, for when
+// there is no network
+func (c *Cache) Cached(key string) ([]byte, error) {
+	c.mux.Lock()
+	entry, found := c.entries[key]
+
+	var cached Entry
+	if found {
+		cached = *entry
+	}
+	c.mux.Unlock()
+
+	if !found {
+		return nil, ErrNotCached
+	}
+
+	return c.read(key, cached)
+}
+
+// read reads a cached file, and marks it as used
+func (c *Cache) read(key string, e Entry) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, e.File))
+	if err != nil {
+		return nil, fmt.Errorf("reading cached image: %v", err)
+	}
+
+	c.touch(key, func(e *Entry) { e.Accessed = time.Now() })
+
+	return data, nil
+}
+
+// touch updates the entry of a key, and saves the index if it was not saved
+// for a while
+func (c *Cache) touch(key string, update func(e *Entry)) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	e := c.entries[key]
+	if e == nil {
+		return
+	}
+
+	update(e)
+	c.dirty = true
+
+	if time.Since(c.saved) < saveInterval {
+		return
+	}
+
+	// the files are there either way, the times are saved again later
+	_ = c.saveIndex()
+}
+
+// Close saves the index, with the times the files were last used
+func (c *Cache) Close() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	return c.saveIndex()
+}
+
+// put stores a file, and removes the least recently used files when the
+// cache grows beyond its limit
+func (c *Cache) put(key string, e *Entry, data []byte) error {
+	e.File = fileName(key)
+	path := filepath.Join(c.dir, e.File)
+
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("writing cached image: %v", err)
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("writing cached image: %v", err)
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if old, found := c.entries[key]; found {
+		c.size -= old.Size
+	}
+
+	c.entries[key] = e
+	c.size += e.Size
+
+	c.evict(key)
+
+	return c.saveIndex()
+}
+
+// evict removes the least recently used files until the cache fits in its
+// limit. The file of the key which was just stored is kept.
+func (c *Cache) evict(keep string) {
+	if c.size <= c.opts.Limit {
+		return
+	}
+
+	keys := make([]string, 0, len(c.entries))
+
+	for key := range c.entries {
+		if key != keep {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].Accessed.Before(c.entries[keys[j]].Accessed)
+	})
+
+	for _, key := range keys {
+		if c.size <= c.opts.Limit {
+			return
+		}
+
+		e := c.entries[key]
+		_ = os.Remove(filepath.Join(c.dir, e.File))
+
+		c.size -= e.Size
+		delete(c.entries, key)
+	}
+}
+
+// saveIndex writes the index of the files, the lock must be held
+func (c *Cache) saveIndex() error {
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("encoding image cache index: %v", err)
+	}
+
+	path := filepath.Join(c.dir, indexFileName)
+
+	if err = os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("writing image cache index: %v", err)
+	}
+
+	if err = os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("writing image cache index: %v", err)
+	}
+
+	c.dirty, c.saved = false, time.Now()
+
+	return nil
+}
+
+// fileName turns a key into a file name, characters other than letters,
+// digits, dashes and dots are replaced
+func fileName(key string) string {
+	name := []byte(key)
+
+	for idx, b := range name {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', b == '-', b == '.':
+		default:
+			name[idx] = '_'
+		}
+	}
+
+	return string(name)
+}
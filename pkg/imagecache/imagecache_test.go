@@ -0,0 +1,154 @@
+package imagecache
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRevalidate(t *testing.T) {
+	requests, modified := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			modified++
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	c, err := Open(dir, Options{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		data, errGet := c.Get(context.Background(), "card-0-large.jpg", server.URL)
+		if errGet != nil || string(data) != "image" {
+			t.Fatalf("get %d: %q, %v", i, data, errGet)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected a fresh file not to be downloaded again, got %d requests", requests)
+	}
+
+	// reopened with a max age which makes every file stale
+	c, err = Open(dir, Options{MaxAge: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := c.Get(context.Background(), "card-0-large.jpg", server.URL)
+	if err != nil || string(data) != "image" || modified != 1 {
+		t.Fatalf("expected a revalidated file, got %q, %v, %d not modified", data, err, modified)
+	}
+
+	server.Close()
+
+	if data, err = c.Get(context.Background(), "card-0-large.jpg", server.URL); err != nil || string(data) != "image" {
+		t.Fatalf("expected the cached file without a server, got %q, %v", data, err)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 10))
+	}))
+	defer server.Close()
+
+	c, err := Open(t.TempDir(), Options{Limit: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, err = c.Get(context.Background(), key, server.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a is used after b, so b is the least recently used
+	time.Sleep(time.Millisecond)
+
+	if _, err = c.Get(context.Background(), "a", server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.Get(context.Background(), "c", server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.Cached("b"); err != ErrNotCached {
+		t.Fatalf("expected b to be evicted, got %v", err)
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, err = c.Cached(key); err != nil {
+			t.Fatalf("expected %s to be cached, got %v", key, err)
+		}
+	}
+
+	if c.Size() != 20 {
+		t.Fatalf("expected 20 bytes, got %d", c.Size())
+	}
+}
+
+func TestAccessTimesPersist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 10))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	c, err := Open(dir, Options{Limit: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, err = c.Get(context.Background(), key, server.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a is used after b, which is only in memory until the cache is closed
+	time.Sleep(time.Millisecond)
+
+	if _, err = c.Cached("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err = Open(dir, Options{Limit: 25}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.Get(context.Background(), "c", server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.Cached("b"); err != ErrNotCached {
+		t.Fatalf("expected b to be evicted after reopening, got %v", err)
+	}
+
+	if _, err = c.Cached("a"); err != nil {
+		t.Fatalf("expected a to be cached after reopening, got %v", err)
+	}
+}
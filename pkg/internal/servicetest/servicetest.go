@@ -0,0 +1,104 @@
+package servicetest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravestench/runtime"
+	"github.com/gravestench/runtime/pkg/events"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+// Configurable is a service with a logger and a default config, which is
+// tested without a runtime
+type Configurable interface {
+	runtime.HasLogger
+	configFile.HasDefaultConfig
+}
+
+// Configure binds a logger which discards everything to a service, and
+// yields its default config with the values of a group set, like the config
+// file service would
+func Configure(s Configurable, group string, values map[string]any) *configFile.Config {
+	logger := zerolog.Nop()
+	s.BindLogger(&logger)
+
+	cfg := s.DefaultConfig()
+
+	for key, value := range values {
+		cfg.Group(group).Set(key, value)
+	}
+
+	return &cfg
+}
+
+// WriteConfig writes a config file with the values of a group to a
+// directory, for the config file service of a runtime to load
+func WriteConfig(t *testing.T, dir, fileName, group string, values map[string]any) {
+	t.Helper()
+
+	var cfg configFile.Config
+
+	for key, value := range values {
+		cfg.Group(group).Set(key, value)
+	}
+
+	data, err := cfg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, fileName), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// StartRuntime adds the config file service, and then the other services,
+// and waits until every service is initialized
+func StartRuntime(t *testing.T, cfg *configFile.Service, services ...runtime.Service) {
+	t.Helper()
+
+	rt := runtime.New()
+	rt.SetLogDestination(io.Discard)
+
+	initialized := make(chan string, len(services)+1)
+
+	rt.Events().On(events.EventServiceInitialized, func(args ...any) {
+		if service, ok := args[0].(runtime.Service); ok {
+			initialized <- service.Name()
+		}
+	})
+
+	wait := func(names ...string) {
+		pending := make(map[string]bool)
+		for _, name := range names {
+			pending[name] = true
+		}
+
+		for len(pending) > 0 {
+			select {
+			case name := <-initialized:
+				delete(pending, name)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("services not initialized: %v", pending)
+			}
+		}
+	}
+
+	rt.Add(cfg)
+	wait(cfg.Name())
+
+	names := make([]string, 0, len(services))
+
+	for _, service := range services {
+		rt.Add(service)
+		names = append(names, service.Name())
+	}
+
+	wait(names...)
+}
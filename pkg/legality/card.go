@@ -0,0 +1,133 @@
+package legality
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+var ErrUnknownCard = errors.New("unknown card")
+
+// the legality of a card in a format, as used by scryfall
+const (
+	Legal      = "legal"
+	NotLegal   = "not_legal"
+	Banned     = "banned"
+	Restricted = "restricted"
+)
+
+// Card is what the validator needs to know about a card
+type Card struct {
+	Name          string
+	TypeLine      string
+	OracleText    string
+	ColorIdentity []string
+
+	// Legalities is the legality of the card by format key, like
+	// {"modern": "legal", "legacy": "banned"}
+	Legalities map[string]string
+}
+
+// CardLookup finds cards by name. It yields ErrUnknownCard for a card which
+// does not exist. This can be backed by an online api or a local database.
+type CardLookup interface {
+	Card(name string) (*Card, error)
+}
+
+// FromScryfall converts a scryfall card. The scryfall client does not know
+// about every format, like brawl, so the card is not legal in those unless
+// its Legalities are replaced with the legalities in the json of scryfall.
+func FromScryfall(card scryfall.Card) *Card {
+	c := &Card{
+		Name:       card.Name,
+		TypeLine:   card.TypeLine,
+		OracleText: card.OracleText,
+		Legalities: map[string]string{
+			"standard":  string(card.Legalities.Standard),
+			"pioneer":   string(card.Legalities.Pioneer),
+			"modern":    string(card.Legalities.Modern),
+			"legacy":    string(card.Legalities.Legacy),
+			"vintage":   string(card.Legalities.Vintage),
+			"pauper":    string(card.Legalities.Pauper),
+			"commander": string(card.Legalities.Commander),
+		},
+	}
+
+	// double faced cards only have oracle text on their faces
+	if c.OracleText == "" {
+		texts := make([]string, 0, len(card.CardFaces))
+
+		for _, face := range card.CardFaces {
+			if face.OracleText != nil {
+				texts = append(texts, *face.OracleText)
+			}
+		}
+
+		c.OracleText = strings.Join(texts, "\n")
+	}
+
+	for _, color := range card.ColorIdentity {
+		c.ColorIdentity = append(c.ColorIdentity, string(color))
+	}
+
+	return c
+}
+
+// frontTypeLine is the type line of the front face of a card
+func (c *Card) frontTypeLine() string {
+	typeLine, _, _ := strings.Cut(c.TypeLine, "//")
+	return typeLine
+}
+
+// IsBasicLand is true for basic lands, which are exempt from copy limits
+func (c *Card) IsBasicLand() bool {
+	typeLine := c.frontTypeLine()
+	return strings.Contains(typeLine, "Basic") && strings.Contains(typeLine, "Land")
+}
+
+// CanBeCommander is true for legendary creatures, cards which say that they
+// can be your commander, and legendary planeswalkers in formats which allow
+// them, like brawl
+func (c *Card) CanBeCommander(format Format) bool {
+	typeLine := c.frontTypeLine()
+	legendary := strings.Contains(typeLine, "Legendary")
+
+	switch {
+	case legendary && strings.Contains(typeLine, "Creature"):
+		return true
+	case legendary && format.PlaneswalkerCommanders && strings.Contains(typeLine, "Planeswalker"):
+		return true
+	}
+
+	return strings.Contains(c.OracleText, "can be your commander")
+}
+
+var regexCopyLimit = regexp.MustCompile(`A deck can have (any number of|up to (\w+)) cards named`)
+
+var numberWords = map[string]int{
+	"two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// CopyLimit yields the number of copies allowed by the card itself, like
+// Relentless Rats ("any number") or Seven Dwarves ("up to seven"). The
+// result is false if the card has no such text.
+func (c *Card) CopyLimit() (limit int, unlimited, found bool) {
+	match := regexCopyLimit.FindStringSubmatch(c.OracleText)
+	if match == nil {
+		return 0, false, false
+	}
+
+	if match[2] == "" {
+		return 0, true, true
+	}
+
+	limit, known := numberWords[strings.ToLower(match[2])]
+	if !known {
+		return 0, false, false
+	}
+
+	return limit, false, true
+}
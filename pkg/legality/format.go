@@ -0,0 +1,81 @@
+package legality
+
+import (
+	"sort"
+	"strings"
+)
+
+// Format is the set of deck construction rules of a constructed format
+type Format struct {
+	// Name is the name of the format, as listed by the mtg api
+	Name string
+
+	// Key is the key of the format in the scryfall legalities of a card
+	Key string
+
+	// MinSize is the minimum number of cards in the deck, ExactSize the
+	// exact number (if not zero). Commanders are part of the deck.
+	MinSize   int
+	ExactSize int
+
+	// MaxSideboard is the maximum number of cards in the sideboard
+	MaxSideboard int
+
+	// MaxCopies is the maximum number of copies of a card which is not a
+	// basic land, 1 for singleton formats
+	MaxCopies int
+
+	// Commander formats need a commander and every card must be within the
+	// color identity of the commander
+	Commander bool
+
+	// PlaneswalkerCommanders allows any legendary planeswalker to be the
+	// commander, like in brawl
+	PlaneswalkerCommanders bool
+
+	// Restricted formats allow a single copy of restricted cards
+	Restricted bool
+}
+
+// Singleton is true if the format allows a single copy of each card
+func (f Format) Singleton() bool {
+	return f.MaxCopies == 1
+}
+
+// Formats are the rules of every format the validator knows, by the lowercase
+// name of the format
+var Formats = map[string]Format{
+	"standard": {Name: "Standard", Key: "standard", MinSize: 60, MaxSideboard: 15, MaxCopies: 4},
+	"pioneer":  {Name: "Pioneer", Key: "pioneer", MinSize: 60, MaxSideboard: 15, MaxCopies: 4},
+	"modern":   {Name: "Modern", Key: "modern", MinSize: 60, MaxSideboard: 15, MaxCopies: 4},
+	"legacy":   {Name: "Legacy", Key: "legacy", MinSize: 60, MaxSideboard: 15, MaxCopies: 4},
+	"vintage":  {Name: "Vintage", Key: "vintage", MinSize: 60, MaxSideboard: 15, MaxCopies: 4, Restricted: true},
+	"pauper":   {Name: "Pauper", Key: "pauper", MinSize: 60, MaxSideboard: 15, MaxCopies: 4},
+	"commander": {
+		Name: "Commander", Key: "commander", ExactSize: 100, MaxSideboard: 0, MaxCopies: 1,
+		Commander: true,
+	},
+	"brawl": {
+		Name: "Brawl", Key: "brawl", ExactSize: 60, MaxSideboard: 0, MaxCopies: 1,
+		Commander: true, PlaneswalkerCommanders: true,
+	},
+}
+
+// Lookup yields the rules of a format by name, ignoring case
+func Lookup(name string) (Format, bool) {
+	format, found := Formats[strings.ToLower(strings.TrimSpace(name))]
+	return format, found
+}
+
+// Names yields the names of every known format, sorted
+func Names() []string {
+	names := make([]string, 0, len(Formats))
+
+	for _, format := range Formats {
+		names = append(names, format.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,260 @@
+package legality
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// ViolationKind is the rule a deck breaks
+type ViolationKind string
+
+const (
+	ViolationUnknownCard   ViolationKind = "unknown card"
+	ViolationDeckSize      ViolationKind = "deck size"
+	ViolationSideboardSize ViolationKind = "sideboard size"
+	ViolationTooManyCopies ViolationKind = "too many copies"
+	ViolationNotLegal      ViolationKind = "not legal"
+	ViolationBanned        ViolationKind = "banned"
+	ViolationRestricted    ViolationKind = "restricted"
+	ViolationCommander     ViolationKind = "commander"
+	ViolationColorIdentity ViolationKind = "color identity"
+)
+
+// Violation is a single broken rule. Card is empty for rules about the
+// whole deck, like its size.
+type Violation struct {
+	Kind    ViolationKind `json:"kind"`
+	Card    string        `json:"card,omitempty"`
+	Message string        `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Message
+}
+
+// Validate checks a deck against the construction rules of a format. The
+// maybe board is ignored, and companions are part of the sideboard, except
+// in commander formats where they are outside the game. An error is only
+// returned if the card lookup fails for another reason than an unknown card.
+func Validate(format Format, deck *decklist.Deck, cards CardLookup) ([]Violation, error) {
+	v := &validation{format: format, deck: deck, lookup: cards, cards: make(map[string]*Card)}
+
+	if err := v.resolveCards(); err != nil {
+		return nil, err
+	}
+
+	v.checkSize()
+	v.checkCopies()
+	v.checkLegality()
+
+	if format.Commander {
+		v.checkCommander()
+	}
+
+	return v.violations, nil
+}
+
+type validation struct {
+	format     Format
+	deck       *decklist.Deck
+	lookup     CardLookup
+	cards      map[string]*Card
+	violations []Violation
+}
+
+func (v *validation) add(kind ViolationKind, card, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Kind: kind, Card: card, Message: fmt.Sprintf(format, args...)})
+}
+
+// sections yields the sections which are part of the deck in this format
+func (v *validation) sections() []decklist.Section {
+	sections := []decklist.Section{decklist.SectionCommander, decklist.SectionMain, decklist.SectionSideboard}
+
+	if !v.format.Commander {
+		sections = append(sections, decklist.SectionCompanion)
+	}
+
+	return sections
+}
+
+func (v *validation) resolveCards() error {
+	for _, section := range append(v.sections(), decklist.SectionCompanion) {
+		for _, entry := range v.deck.Section(section) {
+			if _, done := v.cards[entry.Name]; done {
+				continue
+			}
+
+			card, err := v.lookup.Card(entry.Name)
+			if errors.Is(err, ErrUnknownCard) {
+				v.cards[entry.Name] = nil
+				v.add(ViolationUnknownCard, entry.Name, "unknown card %q", entry.Name)
+
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("looking up %q: %v", entry.Name, err)
+			}
+
+			v.cards[entry.Name] = card
+		}
+	}
+
+	return nil
+}
+
+func (v *validation) checkSize() {
+	size := v.deck.Count(decklist.SectionMain)
+	if v.format.Commander {
+		size += v.deck.Count(decklist.SectionCommander)
+	}
+
+	if v.format.ExactSize > 0 && size != v.format.ExactSize {
+		v.add(ViolationDeckSize, "", "%s decks have exactly %d cards, this deck has %d", v.format.Name, v.format.ExactSize, size)
+	}
+
+	if size < v.format.MinSize {
+		v.add(ViolationDeckSize, "", "%s decks have at least %d cards, this deck has %d", v.format.Name, v.format.MinSize, size)
+	}
+
+	sideboard := v.deck.Count(decklist.SectionSideboard)
+	if !v.format.Commander {
+		sideboard += v.deck.Count(decklist.SectionCompanion)
+	}
+
+	if sideboard > v.format.MaxSideboard {
+		v.add(ViolationSideboardSize, "", "%s sideboards have at most %d cards, this sideboard has %d", v.format.Name, v.format.MaxSideboard, sideboard)
+	}
+}
+
+// copies counts the copies of each card over every section of the deck, in
+// the order the cards are first listed
+func (v *validation) copies() (names []string, counts map[string]int) {
+	counts = make(map[string]int)
+
+	for _, section := range v.sections() {
+		for _, entry := range v.deck.Section(section) {
+			if _, seen := counts[entry.Name]; !seen {
+				names = append(names, entry.Name)
+			}
+
+			counts[entry.Name] += entry.Count
+		}
+	}
+
+	return names, counts
+}
+
+func (v *validation) checkCopies() {
+	names, counts := v.copies()
+
+	for _, name := range names {
+		card := v.cards[name]
+		if card == nil || card.IsBasicLand() {
+			continue
+		}
+
+		limit := v.format.MaxCopies
+
+		if cardLimit, unlimited, found := card.CopyLimit(); found {
+			if unlimited {
+				continue
+			}
+
+			limit = cardLimit
+		}
+
+		if counts[name] <= limit {
+			continue
+		}
+
+		if limit == 1 {
+			v.add(ViolationTooManyCopies, name, "%s is a singleton format, the deck has %d copies of %s", v.format.Name, counts[name], name)
+			continue
+		}
+
+		v.add(ViolationTooManyCopies, name, "a deck can have up to %d copies of %s, this deck has %d", limit, name, counts[name])
+	}
+}
+
+func (v *validation) checkLegality() {
+	names, counts := v.copies()
+
+	for _, name := range names {
+		card := v.cards[name]
+		if card == nil {
+			continue
+		}
+
+		switch card.Legalities[v.format.Key] {
+		case Legal:
+		case Banned:
+			v.add(ViolationBanned, name, "%s is banned in %s", name, v.format.Name)
+		case Restricted:
+			if !v.format.Restricted {
+				v.add(ViolationNotLegal, name, "%s is not legal in %s", name, v.format.Name)
+			} else if counts[name] > 1 {
+				v.add(ViolationRestricted, name, "%s is restricted in %s, this deck has %d copies", name, v.format.Name, counts[name])
+			}
+		default:
+			v.add(ViolationNotLegal, name, "%s is not legal in %s", name, v.format.Name)
+		}
+	}
+}
+
+// maxCommanders allows for partners and backgrounds
+const maxCommanders = 2
+
+func (v *validation) checkCommander() {
+	commanders := v.deck.Section(decklist.SectionCommander)
+
+	count := v.deck.Count(decklist.SectionCommander)
+	if count < 1 || count > maxCommanders {
+		v.add(ViolationCommander, "", "%s decks have one commander, or two with partner, this deck has %d", v.format.Name, count)
+	}
+
+	identity := make(map[string]bool)
+
+	for _, entry := range commanders {
+		card := v.cards[entry.Name]
+		if card == nil {
+			continue
+		}
+
+		if !card.CanBeCommander(v.format) {
+			v.add(ViolationCommander, entry.Name, "%s can not be a commander", entry.Name)
+		}
+
+		for _, color := range card.ColorIdentity {
+			identity[color] = true
+		}
+	}
+
+	if len(commanders) == 0 {
+		return
+	}
+
+	names, _ := v.copies()
+
+	for _, name := range names {
+		card := v.cards[name]
+		if card == nil {
+			continue
+		}
+
+		outside := make([]string, 0)
+
+		for _, color := range card.ColorIdentity {
+			if !identity[color] {
+				outside = append(outside, color)
+			}
+		}
+
+		if len(outside) > 0 {
+			v.add(ViolationColorIdentity, name, "%s is outside the color identity of the commander (%s)", name, strings.Join(outside, ""))
+		}
+	}
+}
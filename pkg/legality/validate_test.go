@@ -0,0 +1,180 @@
+package legality
+
+import (
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// testCards is an offline card lookup
+type testCards map[string]*Card
+
+func (c testCards) Card(name string) (*Card, error) {
+	if card, found := c[name]; found {
+		return card, nil
+	}
+
+	return nil, ErrUnknownCard
+}
+
+func legalIn(status string, formats ...string) map[string]string {
+	legalities := make(map[string]string)
+
+	for _, format := range formats {
+		legalities[format] = status
+	}
+
+	return legalities
+}
+
+var cards = testCards{
+	"Lightning Bolt": {
+		Name: "Lightning Bolt", TypeLine: "Instant", ColorIdentity: []string{"R"},
+		Legalities: legalIn(Legal, "modern", "legacy", "vintage", "pauper", "commander"),
+	},
+	"Mountain": {
+		Name: "Mountain", TypeLine: "Basic Land — Mountain", ColorIdentity: []string{"R"},
+		Legalities: legalIn(Legal, "standard", "modern", "legacy", "vintage", "pauper", "commander", "brawl"),
+	},
+	"Relentless Rats": {
+		Name: "Relentless Rats", TypeLine: "Creature — Rat", ColorIdentity: []string{"B"},
+		OracleText: "A deck can have any number of cards named Relentless Rats.",
+		Legalities: legalIn(Legal, "modern", "legacy", "vintage", "commander"),
+	},
+	"Lotus Petal": {
+		Name: "Lotus Petal", TypeLine: "Artifact",
+		Legalities: map[string]string{"legacy": Legal, "vintage": Legal, "modern": NotLegal},
+	},
+	"Sol Ring": {
+		Name: "Sol Ring", TypeLine: "Artifact",
+		Legalities: map[string]string{"legacy": Banned, "vintage": Restricted, "commander": Legal},
+	},
+	"Krenko, Mob Boss": {
+		Name: "Krenko, Mob Boss", TypeLine: "Legendary Creature — Goblin Warrior", ColorIdentity: []string{"R"},
+		Legalities: legalIn(Legal, "modern", "legacy", "vintage", "commander"),
+	},
+	"Chandra, Torch of Defiance": {
+		Name: "Chandra, Torch of Defiance", TypeLine: "Legendary Planeswalker — Chandra", ColorIdentity: []string{"R"},
+		Legalities: legalIn(Legal, "modern", "legacy", "vintage", "commander", "brawl"),
+	},
+	"Counterspell": {
+		Name: "Counterspell", TypeLine: "Instant", ColorIdentity: []string{"U"},
+		Legalities: legalIn(Legal, "legacy", "vintage", "pauper", "commander"),
+	},
+}
+
+func kinds(violations []Violation) map[ViolationKind]int {
+	result := make(map[ViolationKind]int)
+
+	for _, v := range violations {
+		result[v.Kind]++
+	}
+
+	return result
+}
+
+func validate(t *testing.T, format string, deck *decklist.Deck) map[ViolationKind]int {
+	rules, found := Lookup(format)
+	if !found {
+		t.Fatalf("unknown format %q", format)
+	}
+
+	violations, err := Validate(rules, deck, cards)
+	if err != nil {
+		t.Fatalf("validating: %v", err)
+	}
+
+	return kinds(violations)
+}
+
+func TestValidateLegalModernDeck(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Lightning Bolt"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 30, Name: "Relentless Rats"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 26, Name: "Mountain"})
+
+	if violations := validate(t, "Modern", deck); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+}
+
+func TestValidateReportsConstructionViolations(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 5, Name: "Lightning Bolt"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Lotus Petal"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Made Up Card"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 40, Name: "Mountain"})
+	deck.Add(decklist.SectionSideboard, decklist.Entry{Count: 16, Name: "Mountain"})
+
+	expected := map[ViolationKind]int{
+		ViolationDeckSize:      1,
+		ViolationSideboardSize: 1,
+		ViolationTooManyCopies: 1,
+		ViolationNotLegal:      1,
+		ViolationUnknownCard:   1,
+	}
+
+	violations := validate(t, "modern", deck)
+	for kind, count := range expected {
+		if violations[kind] != count {
+			t.Fatalf("expected %d %q violations, got %v", count, kind, violations)
+		}
+	}
+}
+
+func TestValidateBannedAndRestricted(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 2, Name: "Sol Ring"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 58, Name: "Mountain"})
+
+	if violations := validate(t, "legacy", deck); violations[ViolationBanned] != 1 {
+		t.Fatalf("expected Sol Ring to be banned in legacy, got %v", violations)
+	}
+
+	if violations := validate(t, "vintage", deck); violations[ViolationRestricted] != 1 {
+		t.Fatalf("expected Sol Ring to be restricted in vintage, got %v", violations)
+	}
+}
+
+func TestValidateCommander(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionCommander, decklist.Entry{Count: 1, Name: "Krenko, Mob Boss"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Sol Ring"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 2, Name: "Lightning Bolt"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Counterspell"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 95, Name: "Mountain"})
+
+	expected := map[ViolationKind]int{
+		ViolationTooManyCopies: 1,
+		ViolationColorIdentity: 1,
+	}
+
+	violations := validate(t, "commander", deck)
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, violations)
+	}
+
+	for kind, count := range expected {
+		if violations[kind] != count {
+			t.Fatalf("expected %v, got %v", expected, violations)
+		}
+	}
+}
+
+func TestValidateBrawl(t *testing.T) {
+	deck := decklist.New()
+	deck.Add(decklist.SectionCommander, decklist.Entry{Count: 1, Name: "Chandra, Torch of Defiance"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 58, Name: "Mountain"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 1, Name: "Lightning Bolt"})
+
+	// any legendary planeswalker can be the commander, and legality is
+	// brawl legality rather than standard legality
+	violations := validate(t, "brawl", deck)
+	if len(violations) != 1 || violations[ViolationNotLegal] != 1 {
+		t.Fatalf("expected Lightning Bolt to be the only violation, got %v", violations)
+	}
+
+	if violations = validate(t, "commander", deck); violations[ViolationCommander] != 1 {
+		t.Fatalf("expected a planeswalker not to be a commander in commander, got %v", violations)
+	}
+}
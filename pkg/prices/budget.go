@@ -0,0 +1,111 @@
+package prices
+
+import (
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// PrintingsLookup yields every printing of the card with an oracle ID
+type PrintingsLookup func(oracleID string) ([]scryfall.Card, error)
+
+// Swap is a printing replaced by a cheaper one
+type Swap struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+
+	// the printings, by set code and collector number, like "M10 146"
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// the prices of a single card, the price of the printing in the deck
+	// is zero if it has none
+	FromPrice float64 `json:"fromPrice"`
+	ToPrice   float64 `json:"toPrice"`
+
+	// Savings is what the swap saves for every copy in the deck, zero if
+	// the price of the printing in the deck is not known
+	Savings float64 `json:"savings"`
+}
+
+// Budget yields a copy of a deck in which every printing is swapped for the
+// cheapest printing with the same oracle ID, in the same finish. Entries
+// which can not be looked up, or have no priced printing, are kept as they
+// are.
+func Budget(deck *decklist.Deck, currency Currency, lookup CardLookup, printings PrintingsLookup) (*decklist.Deck, []Swap) {
+	budget := decklist.New()
+	budget.Name = deck.Name
+
+	swaps := make([]Swap, 0)
+
+	for _, section := range decklist.Sections {
+		for _, entry := range deck.Section(section) {
+			swapped, swap, ok := cheapestEntry(entry, currency, lookup, printings)
+			if ok {
+				entry = swapped
+
+				if swap != nil {
+					swaps = append(swaps, *swap)
+				}
+			}
+
+			budget.Add(section, entry)
+		}
+	}
+
+	return budget, swaps
+}
+
+func cheapestEntry(entry decklist.Entry, currency Currency, lookup CardLookup, printings PrintingsLookup) (decklist.Entry, *Swap, bool) {
+	card, err := lookup(entry)
+	if err != nil || card == nil || card.OracleID == "" {
+		return entry, nil, false
+	}
+
+	all, err := printings(card.OracleID)
+	if err != nil {
+		return entry, nil, false
+	}
+
+	cheapest, price, found := Cheapest(all, currency, entry.Foil, entry.Etched)
+	if !found {
+		return entry, nil, false
+	}
+
+	entry.Set = strings.ToUpper(cheapest.Set)
+	entry.CollectorNumber = cheapest.CollectorNumber
+
+	if cheapest.ID == card.ID {
+		return entry, nil, true
+	}
+
+	current, known := Price(*card, currency, entry.Foil, entry.Etched)
+	if known && current <= price {
+		// the printing of the deck is as cheap as it gets, keep it
+		entry.Set = strings.ToUpper(card.Set)
+		entry.CollectorNumber = card.CollectorNumber
+
+		return entry, nil, true
+	}
+
+	swap := &Swap{
+		Name:      entry.Name,
+		Count:     entry.Count,
+		From:      printingName(*card),
+		To:        printingName(cheapest),
+		FromPrice: current,
+		ToPrice:   price,
+	}
+
+	if known {
+		swap.Savings = (current - price) * float64(entry.Count)
+	}
+
+	return entry, swap, true
+}
+
+func printingName(card scryfall.Card) string {
+	return strings.ToUpper(card.Set) + " " + card.CollectorNumber
+}
@@ -0,0 +1,142 @@
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// Snapshot is every price of a printing at some time. Prices are keyed by
+// their scryfall field name: usd, usd_foil, usd_etched, eur, eur_foil and
+// tix.
+type Snapshot struct {
+	Time   time.Time          `json:"time"`
+	Prices map[string]float64 `json:"prices"`
+}
+
+// Point is a price at some time, for charting the trend of a price
+type Point struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// History stores price snapshots of printings as json files, one file per
+// scryfall ID in the root directory. Scryfall updates its prices once a day,
+// so there is at most a single snapshot per printing and day, the last one
+// recorded.
+type History struct {
+	root string
+}
+
+// NewHistory yields a price history in the root directory
+func NewHistory(root string) *History {
+	return &History{root: root}
+}
+
+// Record saves the current prices of a printing. Printings without any
+// price are not recorded.
+func (h *History) Record(card scryfall.Card, at time.Time) error {
+	if card.ID == "" {
+		return fmt.Errorf("recording prices of %q: missing scryfall id", card.Name)
+	}
+
+	snapshot := Snapshot{Time: at.UTC(), Prices: fields(card.Prices)}
+	if len(snapshot.Prices) == 0 {
+		return nil
+	}
+
+	snapshots, err := h.Snapshots(card.ID)
+	if err != nil {
+		return err
+	}
+
+	if last := len(snapshots) - 1; last >= 0 && sameDay(snapshots[last].Time, snapshot.Time) {
+		snapshots[last] = snapshot
+	} else {
+		snapshots = append(snapshots, snapshot)
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("encoding price history: %v", err)
+	}
+
+	if err = os.MkdirAll(h.root, 0755); err != nil {
+		return fmt.Errorf("creating price history directory: %v", err)
+	}
+
+	if err = os.WriteFile(h.path(card.ID), data, 0644); err != nil {
+		return fmt.Errorf("writing price history: %v", err)
+	}
+
+	return nil
+}
+
+// Snapshots yields every recorded snapshot of a printing, oldest first
+func (h *History) Snapshots(scryfallID string) ([]Snapshot, error) {
+	data, err := os.ReadFile(h.path(scryfallID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading price history: %v", err)
+	}
+
+	var snapshots []Snapshot
+
+	if err = json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("decoding price history: %v", err)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
+
+// Trend yields the price of a printing over time in a currency and finish,
+// snapshots without that price are skipped
+func (h *History) Trend(scryfallID string, currency Currency, foil, etched bool) ([]Point, error) {
+	snapshots, err := h.Snapshots(scryfallID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := string(currency)
+
+	switch {
+	case currency == Tix:
+	case etched:
+		key += "_etched"
+	case foil:
+		key += "_foil"
+	}
+
+	points := make([]Point, 0, len(snapshots))
+
+	for _, snapshot := range snapshots {
+		if price, found := snapshot.Prices[key]; found {
+			points = append(points, Point{Time: snapshot.Time, Price: price})
+		}
+	}
+
+	return points, nil
+}
+
+func (h *History) path(scryfallID string) string {
+	return filepath.Join(h.root, filepath.Base(scryfallID)+".json")
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,116 @@
+package prices
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/BlueMonday/go-scryfall"
+)
+
+// Currency is one of the currencies scryfall has prices in
+type Currency string
+
+const (
+	USD Currency = "usd"
+	EUR Currency = "eur"
+	Tix Currency = "tix" // magic online event tickets
+)
+
+// Currencies is every supported currency
+var Currencies = []Currency{USD, EUR, Tix}
+
+// ParseCurrency yields the currency with the given name
+func ParseCurrency(name string) (Currency, error) {
+	for _, c := range Currencies {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown currency %q, expected one of %v", name, Currencies)
+}
+
+// Price yields the price of a printing in a currency and finish. Scryfall
+// has no price for every combination, euro prices of etched cards and
+// foil tickets do not exist, and many printings have no price at all.
+func Price(card scryfall.Card, currency Currency, foil, etched bool) (price float64, found bool) {
+	var field string
+
+	switch currency {
+	case USD:
+		switch {
+		case etched:
+			field = card.Prices.USDEtched
+		case foil:
+			field = card.Prices.USDFoil
+		default:
+			field = card.Prices.USD
+		}
+	case EUR:
+		switch {
+		case etched:
+			return 0, false
+		case foil:
+			field = card.Prices.EURFoil
+		default:
+			field = card.Prices.EUR
+		}
+	case Tix:
+		field = card.Prices.Tix
+	}
+
+	return parse(field)
+}
+
+// Cheapest yields the printing with the lowest price in a currency and
+// finish, printings without a price are skipped
+func Cheapest(printings []scryfall.Card, currency Currency, foil, etched bool) (cheapest scryfall.Card, price float64, found bool) {
+	for _, card := range printings {
+		p, ok := Price(card, currency, foil, etched)
+		if !ok {
+			continue
+		}
+
+		if !found || p < price {
+			cheapest, price, found = card, p, true
+		}
+	}
+
+	return cheapest, price, found
+}
+
+// fields yields every price of a printing by its scryfall field name
+func fields(p scryfall.Prices) map[string]float64 {
+	all := map[string]string{
+		"usd":        p.USD,
+		"usd_foil":   p.USDFoil,
+		"usd_etched": p.USDEtched,
+		"eur":        p.EUR,
+		"eur_foil":   p.EURFoil,
+		"tix":        p.Tix,
+	}
+
+	result := make(map[string]float64)
+
+	for key, field := range all {
+		if price, found := parse(field); found {
+			result[key] = price
+		}
+	}
+
+	return result
+}
+
+// parse parses a scryfall price, which is a decimal string or empty
+func parse(field string) (float64, bool) {
+	if field == "" {
+		return 0, false
+	}
+
+	price, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return price, true
+}
@@ -0,0 +1,147 @@
+package prices
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+var testPrintings = []scryfall.Card{
+	{ID: "bolt-lea", OracleID: "bolt", Name: "Lightning Bolt", Set: "lea", CollectorNumber: "161",
+		Prices: scryfall.Prices{USD: "450.00"}},
+	{ID: "bolt-m10", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146",
+		Prices: scryfall.Prices{USD: "2.50", USDFoil: "30.00", EUR: "2.00"}},
+	{ID: "bolt-2xm", OracleID: "bolt", Name: "Lightning Bolt", Set: "2xm", CollectorNumber: "129",
+		Prices: scryfall.Prices{USD: "1.25", USDFoil: "4.00"}},
+	{ID: "bolt-prm", OracleID: "bolt", Name: "Lightning Bolt", Set: "prm", CollectorNumber: "32196"},
+	{ID: "mountain", OracleID: "mountain", Name: "Mountain", Set: "m10", CollectorNumber: "242",
+		Prices: scryfall.Prices{USD: "0.10"}},
+}
+
+func testLookup(entry decklist.Entry) (*scryfall.Card, error) {
+	var match *scryfall.Card
+
+	for idx, card := range testPrintings {
+		if card.Name != entry.Name {
+			continue
+		}
+
+		if entry.Set == "" || (card.Set == entry.Set && card.CollectorNumber == entry.CollectorNumber) {
+			match = &testPrintings[idx]
+			break
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("unknown card %q", entry.Name)
+	}
+
+	return match, nil
+}
+
+func testPrintingsLookup(oracleID string) (cards []scryfall.Card, err error) {
+	for _, card := range testPrintings {
+		if card.OracleID == oracleID {
+			cards = append(cards, card)
+		}
+	}
+
+	return cards, nil
+}
+
+func testDeck() *decklist.Deck {
+	deck := decklist.New()
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 4, Name: "Lightning Bolt", Set: "lea", CollectorNumber: "161"})
+	deck.Add(decklist.SectionMain, decklist.Entry{Count: 20, Name: "Mountain"})
+	deck.Add(decklist.SectionSideboard, decklist.Entry{Count: 1, Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", Foil: true})
+	deck.Add(decklist.SectionSideboard, decklist.Entry{Count: 1, Name: "Pyroblast"})
+	deck.Add(decklist.SectionMaybe, decklist.Entry{Count: 1, Name: "Black Lotus"})
+
+	return deck
+}
+
+func TestValue(t *testing.T) {
+	v := Value(testDeck(), USD, testLookup)
+
+	if math.Abs(v.Sections[decklist.SectionMain]-1802) > 1e-9 {
+		t.Fatalf("expected main deck value 1802, got %v", v.Sections[decklist.SectionMain])
+	}
+
+	if math.Abs(v.Total-1832) > 1e-9 {
+		t.Fatalf("expected total value 1832, got %v", v.Total)
+	}
+
+	if !reflect.DeepEqual(v.Unpriced, []string{"Pyroblast"}) {
+		t.Fatalf("expected Pyroblast to be unpriced, got %v", v.Unpriced)
+	}
+}
+
+func TestCheapest(t *testing.T) {
+	card, price, found := Cheapest(testPrintings[:4], EUR, false, false)
+	if !found || card.ID != "bolt-m10" || price != 2 {
+		t.Fatalf("expected the m10 printing for 2 eur, got %s for %v", card.ID, price)
+	}
+
+	if _, _, found = Cheapest(testPrintings[:4], EUR, false, true); found {
+		t.Fatalf("expected no etched printing in eur")
+	}
+}
+
+func TestBudget(t *testing.T) {
+	budget, swaps := Budget(testDeck(), USD, testLookup, testPrintingsLookup)
+
+	main := budget.Section(decklist.SectionMain)
+	if main[0].Set != "2XM" || main[0].CollectorNumber != "129" {
+		t.Fatalf("expected the 2xm printing of bolt, got %+v", main[0])
+	}
+
+	sideboard := budget.Section(decklist.SectionSideboard)
+	if sideboard[0].Set != "2XM" || !sideboard[0].Foil {
+		t.Fatalf("expected the foil 2xm printing of bolt, got %+v", sideboard[0])
+	}
+
+	if sideboard[1].Name != "Pyroblast" || sideboard[1].Set != "" {
+		t.Fatalf("expected unknown cards to be kept, got %+v", sideboard[1])
+	}
+
+	if len(swaps) != 2 || math.Abs(swaps[0].Savings-1795) > 1e-9 || math.Abs(swaps[1].Savings-26) > 1e-9 {
+		t.Fatalf("unexpected swaps %+v", swaps)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory(t.TempDir())
+	day := time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC)
+
+	card := testPrintings[1]
+	for idx, usd := range []string{"2.50", "2.75", "3.00"} {
+		card.Prices.USD = usd
+
+		// the first two snapshots are taken on the same day
+		at := day.Add(time.Duration(idx) * 12 * time.Hour)
+		if err := h.Record(card, at); err != nil {
+			t.Fatalf("recording snapshot: %v", err)
+		}
+	}
+
+	trend, err := h.Trend(card.ID, USD, false, false)
+	if err != nil {
+		t.Fatalf("getting trend: %v", err)
+	}
+
+	expected := []Point{{Time: day.Add(12 * time.Hour), Price: 2.75}, {Time: day.Add(24 * time.Hour), Price: 3}}
+	if !reflect.DeepEqual(trend, expected) {
+		t.Fatalf("expected trend %v, got %v", expected, trend)
+	}
+
+	foil, err := h.Trend(card.ID, USD, true, false)
+	if err != nil || len(foil) != 2 || foil[0].Price != 30 {
+		t.Fatalf("unexpected foil trend %v (%v)", foil, err)
+	}
+}
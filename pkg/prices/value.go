@@ -0,0 +1,84 @@
+package prices
+
+import (
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// CardLookup yields the printing of a deck entry. Entries without a set
+// may yield any printing.
+type CardLookup func(entry decklist.Entry) (*scryfall.Card, error)
+
+// CardValue is the value of a deck entry
+type CardValue struct {
+	Section         decklist.Section `json:"section"`
+	Name            string           `json:"name"`
+	Set             string           `json:"set"`
+	CollectorNumber string           `json:"collectorNumber"`
+	Count           int              `json:"count"`
+	Foil            bool             `json:"foil,omitempty"`
+	Etched          bool             `json:"etched,omitempty"`
+	Each            float64          `json:"each"`
+	Total           float64          `json:"total"`
+}
+
+// Valuation is the value of a deck
+type Valuation struct {
+	Currency Currency                     `json:"currency"`
+	Cards    []CardValue                  `json:"cards"`
+	Sections map[decklist.Section]float64 `json:"sections"`
+	Total    float64                      `json:"total"`
+
+	// Unpriced are the cards which could not be found, or have no price in
+	// the currency
+	Unpriced []string `json:"unpriced"`
+}
+
+// Value prices every card of a deck, except for the maybe board
+func Value(deck *decklist.Deck, currency Currency, lookup CardLookup) *Valuation {
+	v := &Valuation{
+		Currency: currency,
+		Cards:    make([]CardValue, 0),
+		Sections: make(map[decklist.Section]float64),
+		Unpriced: make([]string, 0),
+	}
+
+	for _, section := range decklist.Sections {
+		if section == decklist.SectionMaybe {
+			continue
+		}
+
+		for _, entry := range deck.Section(section) {
+			card, err := lookup(entry)
+			if err != nil || card == nil {
+				v.Unpriced = append(v.Unpriced, entry.Name)
+				continue
+			}
+
+			each, found := Price(*card, currency, entry.Foil, entry.Etched)
+			if !found {
+				v.Unpriced = append(v.Unpriced, entry.Name)
+				continue
+			}
+
+			cv := CardValue{
+				Section:         section,
+				Name:            card.Name,
+				Set:             card.Set,
+				CollectorNumber: card.CollectorNumber,
+				Count:           entry.Count,
+				Foil:            entry.Foil,
+				Etched:          entry.Etched,
+				Each:            each,
+				Total:           each * float64(entry.Count),
+			}
+
+			v.Cards = append(v.Cards, cv)
+			v.Sections[section] += cv.Total
+			v.Total += cv.Total
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,107 @@
+package proxysheet
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+)
+
+// jpegQuality is the quality of the page images of a pdf
+const jpegQuality = 92
+
+// WritePDF writes pages as a pdf, every page is a single jpeg image at the
+// size of the page
+func WritePDF(w io.Writer, pages []*image.RGBA, opts Options) error {
+	pdf := &pdfWriter{w: w}
+
+	// points are 1/72 inch
+	width := opts.Page.WidthMM / 25.4 * 72
+	height := opts.Page.HeightMM / 25.4 * 72
+
+	// objects 1 and 2 are the catalog and the page tree, every page takes
+	// three objects: the page, its content and its image
+	kids := new(bytes.Buffer)
+	for idx := range pages {
+		fmt.Fprintf(kids, "%d 0 R ", 3+idx*3)
+	}
+
+	pdf.header()
+	pdf.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pdf.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids.Bytes()), len(pages)))
+
+	for idx, page := range pages {
+		n := 3 + idx*3
+
+		img := new(bytes.Buffer)
+		if err := jpeg.Encode(img, page, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return fmt.Errorf("encoding page %d: %v", idx+1, err)
+		}
+
+		content := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", width, height)
+
+		pdf.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>", width, height, n+2, n+1))
+		pdf.stream(fmt.Sprintf("<< /Length %d >>", len(content)), []byte(content))
+		pdf.stream(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d "+
+			"/ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			page.Bounds().Dx(), page.Bounds().Dy(), img.Len()), img.Bytes())
+	}
+
+	pdf.trailer()
+
+	return pdf.err
+}
+
+// pdfWriter writes numbered objects and remembers their offsets for the
+// cross-reference table
+type pdfWriter struct {
+	w       io.Writer
+	offset  int
+	offsets []int
+	err     error
+}
+
+func (p *pdfWriter) write(data []byte) {
+	if p.err != nil {
+		return
+	}
+
+	n, err := p.w.Write(data)
+	p.offset += n
+	p.err = err
+}
+
+func (p *pdfWriter) printf(format string, args ...any) {
+	p.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (p *pdfWriter) header() {
+	// the binary comment tells tools that the file holds binary data
+	p.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+}
+
+func (p *pdfWriter) object(dictionary string) {
+	p.offsets = append(p.offsets, p.offset)
+	p.printf("%d 0 obj\n%s\nendobj\n", len(p.offsets), dictionary)
+}
+
+func (p *pdfWriter) stream(dictionary string, data []byte) {
+	p.offsets = append(p.offsets, p.offset)
+	p.printf("%d 0 obj\n%s\nstream\n", len(p.offsets), dictionary)
+	p.write(data)
+	p.printf("\nendstream\nendobj\n")
+}
+
+func (p *pdfWriter) trailer() {
+	xref := p.offset
+
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1)
+
+	for _, offset := range p.offsets {
+		p.printf("%010d 00000 n \n", offset)
+	}
+
+	p.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, xref)
+}
@@ -0,0 +1,76 @@
+package proxysheet
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// scale resizes an image with bilinear filtering
+func scale(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(b)
+		draw.Draw(rgba, b, src, b.Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	if b.Dx() == width && b.Dy() == height {
+		draw.Draw(dst, dst.Bounds(), rgba, b.Min, draw.Src)
+		return dst
+	}
+
+	xRatio := float64(b.Dx()) / float64(width)
+	yRatio := float64(b.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		sy := (float64(y)+0.5)*yRatio - 0.5
+		y0, fy := split(sy, b.Dy())
+
+		for x := 0; x < width; x++ {
+			sx := (float64(x)+0.5)*xRatio - 0.5
+			x0, fx := split(sx, b.Dx())
+
+			x1, y1 := min(x0+1, b.Dx()-1), min(y0+1, b.Dy()-1)
+
+			c00 := rgba.RGBAAt(b.Min.X+x0, b.Min.Y+y0)
+			c10 := rgba.RGBAAt(b.Min.X+x1, b.Min.Y+y0)
+			c01 := rgba.RGBAAt(b.Min.X+x0, b.Min.Y+y1)
+			c11 := rgba.RGBAAt(b.Min.X+x1, b.Min.Y+y1)
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: lerp2(c00.R, c10.R, c01.R, c11.R, fx, fy),
+				G: lerp2(c00.G, c10.G, c01.G, c11.G, fx, fy),
+				B: lerp2(c00.B, c10.B, c01.B, c11.B, fx, fy),
+				A: lerp2(c00.A, c10.A, c01.A, c11.A, fx, fy),
+			})
+		}
+	}
+
+	return dst
+}
+
+// split yields the integer and fractional part of a source coordinate,
+// clamped to the source image
+func split(v float64, size int) (int, float64) {
+	if v <= 0 {
+		return 0, 0
+	}
+
+	i := int(v)
+	if i >= size-1 {
+		return size - 1, 0
+	}
+
+	return i, v - float64(i)
+}
+
+func lerp2(c00, c10, c01, c11 uint8, fx, fy float64) uint8 {
+	top := float64(c00)*(1-fx) + float64(c10)*fx
+	bottom := float64(c01)*(1-fx) + float64(c11)*fx
+
+	return uint8(top*(1-fy) + bottom*fy + 0.5)
+}
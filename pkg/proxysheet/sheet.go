@@ -0,0 +1,190 @@
+package proxysheet
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// the size of a card, and the grid of cards on a page
+const (
+	CardWidthMM  = 63.0
+	CardHeightMM = 88.0
+	Columns      = 3
+	Rows         = 3
+	CardsPerPage = Columns * Rows
+)
+
+// DefaultDPI is the resolution of the pages, cards are 744x1039 pixels
+const DefaultDPI = 300
+
+// cut marks are drawn in the page margin, along the trim lines of the cards
+const (
+	cutMarkLengthMM = 5.0
+	cutMarkWidthMM  = 0.25
+)
+
+// PageSize is the size of a sheet of paper
+type PageSize struct {
+	Name     string
+	WidthMM  float64
+	HeightMM float64
+}
+
+var (
+	A4     = PageSize{Name: "a4", WidthMM: 210, HeightMM: 297}
+	Letter = PageSize{Name: "letter", WidthMM: 215.9, HeightMM: 279.4}
+)
+
+// PageSizes are the supported page sizes by name
+var PageSizes = map[string]PageSize{
+	A4.Name:     A4,
+	Letter.Name: Letter,
+}
+
+// Options are the layout options of the pages
+type Options struct {
+	Page PageSize
+	DPI  int
+
+	// BleedMM extends every card on each side, so that cutting a little
+	// off does not leave a white edge
+	BleedMM float64
+
+	// CutMarks draws the trim lines of the cards in the page margin
+	CutMarks bool
+}
+
+// DefaultOptions are A4 pages at 300 DPI, with cut marks and no bleed
+func DefaultOptions() Options {
+	return Options{Page: A4, DPI: DefaultDPI, CutMarks: true}
+}
+
+// px converts millimeters to pixels
+func (o Options) px(mm float64) int {
+	return int(math.Round(mm / 25.4 * float64(o.DPI)))
+}
+
+// CardSize yields the size of a card in pixels, without bleed
+func (o Options) CardSize() image.Point {
+	return image.Pt(o.px(CardWidthMM), o.px(CardHeightMM))
+}
+
+// PageSizePx yields the size of a page in pixels
+func (o Options) PageSizePx() image.Point {
+	return image.Pt(o.px(o.Page.WidthMM), o.px(o.Page.HeightMM))
+}
+
+func (o Options) validate() error {
+	if o.DPI < 1 {
+		return fmt.Errorf("invalid resolution of %d DPI", o.DPI)
+	}
+
+	if o.BleedMM < 0 {
+		return fmt.Errorf("invalid bleed of %vmm", o.BleedMM)
+	}
+
+	width := Columns * (CardWidthMM + 2*o.BleedMM)
+	height := Rows * (CardHeightMM + 2*o.BleedMM)
+
+	if width > o.Page.WidthMM || height > o.Page.HeightMM {
+		return fmt.Errorf("%dx%d cards with a bleed of %vmm take %.1fx%.1fmm, more than a %s page",
+			Columns, Rows, o.BleedMM, width, height, o.Page.Name)
+	}
+
+	return nil
+}
+
+// Render lays out cards on pages, 3x3 cards to a page, centered. Cards are
+// scaled to their true size, transparent corners and the bleed take the
+// color of the card border.
+func Render(cards []image.Image, opts Options) ([]*image.RGBA, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	pageSize := opts.PageSizePx()
+	cardSize := opts.CardSize()
+	bleed := opts.px(opts.BleedMM)
+	cell := cardSize.Add(image.Pt(2*bleed, 2*bleed))
+
+	// the top left corner of the grid
+	origin := image.Pt((pageSize.X-Columns*cell.X)/2, (pageSize.Y-Rows*cell.Y)/2)
+
+	pages := make([]*image.RGBA, 0, (len(cards)+CardsPerPage-1)/CardsPerPage)
+
+	for idx, card := range cards {
+		slot := idx % CardsPerPage
+
+		if slot == 0 {
+			page := image.NewRGBA(image.Rectangle{Max: pageSize})
+			draw.Draw(page, page.Bounds(), image.White, image.Point{}, draw.Src)
+			pages = append(pages, page)
+		}
+
+		page := pages[len(pages)-1]
+		topLeft := origin.Add(image.Pt(slot%Columns*cell.X, slot/Columns*cell.Y))
+
+		drawCard(page, image.Rectangle{Min: topLeft, Max: topLeft.Add(cell)}, card, bleed)
+	}
+
+	if opts.CutMarks {
+		for _, page := range pages {
+			drawCutMarks(page, origin, cell, bleed, opts)
+		}
+	}
+
+	return pages, nil
+}
+
+// drawCard fills a cell with the border color of a card, then draws the
+// scaled card inside of the bleed
+func drawCard(page *image.RGBA, cell image.Rectangle, card image.Image, bleed int) {
+	draw.Draw(page, cell, image.NewUniform(borderColor(card)), image.Point{}, draw.Src)
+
+	trim := cell.Inset(bleed)
+	scaled := scale(card, trim.Dx(), trim.Dy())
+
+	draw.Draw(page, trim, scaled, image.Point{}, draw.Over)
+}
+
+// borderColor samples the middle of the top border of a card, cards are
+// black bordered when it is transparent
+func borderColor(card image.Image) color.Color {
+	b := card.Bounds()
+	c := color.RGBAModel.Convert(card.At(b.Min.X+b.Dx()/2, b.Min.Y+b.Dy()/100)).(color.RGBA)
+
+	if c.A < 0xff {
+		return color.Black
+	}
+
+	return c
+}
+
+// drawCutMarks draws the trim lines of the cards in the page margins
+func drawCutMarks(page *image.RGBA, origin, cell image.Point, bleed int, opts Options) {
+	length := opts.px(cutMarkLengthMM)
+	width := max(1, opts.px(cutMarkWidthMM))
+	gridMax := origin.Add(image.Pt(Columns*cell.X, Rows*cell.Y))
+	ink := image.NewUniform(color.Black)
+
+	line := func(r image.Rectangle) {
+		draw.Draw(page, r.Intersect(page.Bounds()), ink, image.Point{}, draw.Src)
+	}
+
+	for column := 0; column < Columns; column++ {
+		for _, x := range []int{origin.X + column*cell.X + bleed, origin.X + (column+1)*cell.X - bleed} {
+			line(image.Rect(x-width/2, origin.Y-length, x-width/2+width, origin.Y))
+			line(image.Rect(x-width/2, gridMax.Y, x-width/2+width, gridMax.Y+length))
+		}
+	}
+
+	for row := 0; row < Rows; row++ {
+		for _, y := range []int{origin.Y + row*cell.Y + bleed, origin.Y + (row+1)*cell.Y - bleed} {
+			line(image.Rect(origin.X-length, y-width/2, origin.X, y-width/2+width))
+			line(image.Rect(gridMax.X, y-width/2, gridMax.X+length, y-width/2+width))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package proxysheet
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// testCard is a card sized image with a white border around red art
+func testCard() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 672, 936))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds().Inset(30), image.NewUniform(color.RGBA{R: 0xff, A: 0xff}), image.Point{}, draw.Src)
+
+	return img
+}
+
+func TestRender(t *testing.T) {
+	cards := make([]image.Image, 10)
+	for idx := range cards {
+		cards[idx] = testCard()
+	}
+
+	opts := DefaultOptions()
+	opts.BleedMM = 2
+
+	if size := opts.CardSize(); size != image.Pt(744, 1039) {
+		t.Fatalf("expected cards of 744x1039 pixels, got %v", size)
+	}
+
+	pages, err := Render(cards, opts)
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+
+	if size := pages[0].Bounds().Size(); size != image.Pt(2480, 3508) {
+		t.Fatalf("expected an a4 page at 300 dpi, got %v", size)
+	}
+
+	bleed := opts.px(opts.BleedMM)
+	cell := opts.CardSize().Add(image.Pt(2*bleed, 2*bleed))
+	origin := image.Pt((2480-3*cell.X)/2, (3508-3*cell.Y)/2)
+
+	// the bleed takes the color of the border, the art is in the middle
+	if c := pages[0].RGBAAt(origin.X+1, origin.Y+1); c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Fatalf("expected a white bleed, got %v", c)
+	}
+
+	if c := pages[0].RGBAAt(origin.X+cell.X/2, origin.Y+cell.Y/2); c != (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Fatalf("expected red art, got %v", c)
+	}
+
+	// the cut mark of the first trim line, above the grid
+	if c := pages[0].RGBAAt(origin.X+bleed, origin.Y-5); c != (color.RGBA{0, 0, 0, 0xff}) {
+		t.Fatalf("expected a cut mark, got %v", c)
+	}
+
+	// the second page only holds the tenth card
+	if c := pages[1].RGBAAt(origin.X+cell.X+cell.X/2, origin.Y+cell.Y/2); c != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Fatalf("expected an empty slot, got %v", c)
+	}
+}
+
+func TestRenderTooMuchBleed(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Page = Letter
+	opts.BleedMM = 3
+
+	if _, err := Render([]image.Image{testCard()}, opts); err == nil {
+		t.Fatalf("expected 3mm of bleed not to fit on a letter page")
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DPI = 30
+
+	cards := make([]image.Image, CardsPerPage+1)
+	for idx := range cards {
+		cards[idx] = testCard()
+	}
+
+	pages, err := Render(cards, opts)
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = WritePDF(buf, pages, opts); err != nil {
+		t.Fatalf("writing pdf: %v", err)
+	}
+
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("missing pdf header or trailer")
+	}
+
+	if !bytes.Contains(pdf, []byte("/Count 2")) || bytes.Count(pdf, []byte("/DCTDecode")) != 2 {
+		t.Fatalf("expected two pages with a jpeg each")
+	}
+
+	// every entry of the cross-reference table points at its object
+	xref := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf, -1)
+	if len(xref) != 8 {
+		t.Fatalf("expected 8 objects, got %d", len(xref))
+	}
+
+	for idx, match := range xref {
+		offset, _ := strconv.Atoi(string(match[1]))
+		expected := fmt.Sprintf("%d 0 obj\n", idx+1)
+
+		if !bytes.HasPrefix(pdf[offset:], []byte(expected)) {
+			t.Fatalf("object %d is not at offset %d", idx+1, offset)
+		}
+	}
+}
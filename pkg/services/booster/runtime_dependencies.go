@@ -0,0 +1,25 @@
+package booster
+
+import (
+	"github.com/gravestench/runtime"
+)
+
+// the following methods are invoked by the runtime
+// automatically in an endless loop. As soon as the
+// dependencies are resolved, the Init method is called.
+
+func (s *Service) DependenciesResolved() bool {
+	if s.cards == nil {
+		return false
+	}
+
+	return true
+}
+
+func (s *Service) ResolveDependencies(rt runtime.R) {
+	for _, service := range rt.Services() {
+		if candidate, ok := service.(ProvidesSetCards); ok {
+			s.cards = candidate
+		}
+	}
+}
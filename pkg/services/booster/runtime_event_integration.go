@@ -0,0 +1,22 @@
+package booster
+
+import (
+	"github.com/gravestench/runtime"
+)
+
+func (s *Service) OnServiceAdded(args ...any) {
+	if len(args) < 1 {
+		return
+	}
+
+	if candidate, ok := args[0].(runtime.Service); ok {
+		s.tryToBindSetInfo(candidate)
+	}
+}
+
+// the set info service is optional, it is bound whenever it is added
+func (s *Service) tryToBindSetInfo(service runtime.Service) {
+	if s.sets.Bind(service) {
+		s.logger.Info().Msgf("the kind of booster of a set follows its release date")
+	}
+}
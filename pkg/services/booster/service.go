@@ -0,0 +1,158 @@
+package booster
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/booster"
+	"github.com/gravestench/mtg/pkg/services/setInfo"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+type Service struct {
+	logger *zerolog.Logger
+	cards  ProvidesSetCards
+	sets   setInfo.Binding
+
+	mux   sync.Mutex
+	pools map[string]*booster.Pool
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.pools == nil {
+		s.pools = make(map[string]*booster.Pool)
+	}
+
+	for _, service := range rt.Services() {
+		// try to bind existing services
+		s.tryToBindSetInfo(service)
+		// there is a runtime event handler that does this in runtime_event_integration.go
+	}
+}
+
+func (s *Service) Name() string {
+	return "Booster"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Pack opens a pack of a set
+func (s *Service) Pack(set string, kind booster.Kind, seed int64) (booster.Pack, error) {
+	g, err := s.generator(set, kind, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Pack(), nil
+}
+
+// Sealed opens the packs of a sealed pool of a set
+func (s *Service) Sealed(set string, kind booster.Kind, seed int64) ([]booster.Pack, error) {
+	g, err := s.generator(set, kind, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Sealed(), nil
+}
+
+// Draft drafts packs of a set with a full pod, seats which are not taken by
+// pickers are taken by bots
+func (s *Service) Draft(set string, kind booster.Kind, seed int64, pickers ...booster.Picker) (*booster.DraftResult, error) {
+	g, err := s.generator(set, kind, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return booster.NewDraft(g, pickers...).Run()
+}
+
+// DefaultKind yields the kind of booster a set is opened in, which depends on
+// its release date. Without the set info service every set is opened in play
+// boosters.
+func (s *Service) DefaultKind(set string) booster.Kind {
+	sets := s.sets.Sets()
+
+	if sets == nil {
+		return booster.KindPlay
+	}
+
+	info, err := sets.Set(set)
+	if err != nil {
+		s.logger.Warn().Msgf("kind of booster of set %q: %v", set, err)
+		return booster.KindPlay
+	}
+
+	return booster.DefaultKind(info.ReleaseDate())
+}
+
+// BoosterSets yields the sets which are opened in boosters, from the newest
+// to the oldest, which requires the set info service
+func (s *Service) BoosterSets() ([]setinfo.Set, error) {
+	sets := s.sets.Sets()
+
+	if sets == nil {
+		return nil, fmt.Errorf("the sets are unknown without the set info service")
+	}
+
+	idx, err := sets.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Newest(setinfo.Set.HasBoosters), nil
+}
+
+func (s *Service) generator(set string, kind booster.Kind, seed int64) (*booster.Generator, error) {
+	pool, err := s.pool(set)
+	if err != nil {
+		return nil, err
+	}
+
+	return booster.New(pool, kind, seed)
+}
+
+// pool yields the card pool of a set, pools are remembered because a set
+// is usually opened many times in a row
+func (s *Service) pool(set string) (*booster.Pool, error) {
+	set = strings.ToLower(set)
+
+	s.mux.Lock()
+	pool, found := s.pools[set]
+	s.mux.Unlock()
+
+	if found {
+		return pool, nil
+	}
+
+	cards, err := s.cards.GetSetCards(set)
+	if err != nil {
+		return nil, fmt.Errorf("getting cards of set %q: %v", set, err)
+	}
+
+	pool = booster.NewPool(cards)
+
+	s.mux.Lock()
+	s.pools[set] = pool
+	s.mux.Unlock()
+
+	s.logger.Info().Msgf("card pool of set %q: %v", set, pool.Size())
+
+	return pool, nil
+}
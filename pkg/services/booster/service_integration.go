@@ -0,0 +1,46 @@
+package booster
+
+import (
+	"github.com/BlueMonday/go-scryfall"
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/booster"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies          = &Service{} // implement in`runtime_dependencies.go`
+	_ runtime.EventHandlerServiceAdded = &Service{} // implement in`runtime_event_integration.go`
+	_ webRouter.IsRouteInitializer     = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug           = &Service{} // implement in`web_router_integration.go`
+	_ OpensBoosters                    = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = OpensBoosters
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type OpensBoosters interface {
+	Pack(set string, kind booster.Kind, seed int64) (booster.Pack, error)
+	Sealed(set string, kind booster.Kind, seed int64) ([]booster.Pack, error)
+	Draft(set string, kind booster.Kind, seed int64, pickers ...booster.Picker) (*booster.DraftResult, error)
+	DefaultKind(set string) booster.Kind
+	BoosterSets() ([]setinfo.Set, error)
+}
+
+// ProvidesSetCards is a source of card data, like the scryfall service
+type ProvidesSetCards interface {
+	GetSetCards(set string) ([]scryfall.Card, error)
+}
@@ -0,0 +1,107 @@
+package booster
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/booster"
+)
+
+func (s *Service) Slug() string {
+	return "booster"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("sets", s.handleSets)
+	group.GET(":set/pack", s.handlePack)
+	group.GET(":set/sealed", s.handleSealed)
+	group.GET(":set/draft", s.handleDraft)
+}
+
+// handleSets yields the sets which are opened in boosters, from the newest to
+// the oldest, with the kind of booster each is opened in
+func (s *Service) handleSets(c *gin.Context) {
+	sets, err := s.BoosterSets()
+	if err != nil {
+		c.String(http.StatusServiceUnavailable, "%v", err)
+		return
+	}
+
+	response := make([]gin.H, 0, len(sets))
+	for _, set := range sets {
+		response = append(response, gin.H{"set": set, "kind": booster.DefaultKind(set.ReleaseDate())})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *Service) handlePack(c *gin.Context) {
+	kind, seed, ok := s.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	pack, err := s.Pack(c.Param("set"), kind, seed)
+	if err != nil {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"seed": seed, "pack": pack})
+}
+
+func (s *Service) handleSealed(c *gin.Context) {
+	kind, seed, ok := s.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	packs, err := s.Sealed(c.Param("set"), kind, seed)
+	if err != nil {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"seed": seed, "packs": packs})
+}
+
+// handleDraft simulates a draft in which every seat is taken by a bot
+func (s *Service) handleDraft(c *gin.Context) {
+	kind, seed, ok := s.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	result, err := s.Draft(c.Param("set"), kind, seed)
+	if err != nil {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"seed": seed, "pools": result.Pools, "picks": result.Picks})
+}
+
+// parseQuery reads the kind of booster, by default the kind the set is
+// opened in, and the seed, which is random by default. The seed is in every
+// response, so that the packs can be opened again.
+func (s *Service) parseQuery(c *gin.Context) (booster.Kind, int64, bool) {
+	kind, err := booster.ParseKind(c.DefaultQuery("kind", string(s.DefaultKind(c.Param("set")))))
+	if err != nil {
+		c.String(http.StatusBadRequest, "%v", err)
+		return "", 0, false
+	}
+
+	seed := time.Now().UnixNano()
+
+	if query := c.Query("seed"); query != "" {
+		if seed, err = strconv.ParseInt(query, 10, 64); err != nil {
+			c.String(http.StatusBadRequest, "invalid seed %q", query)
+			return "", 0, false
+		}
+	}
+
+	return kind, seed, true
+}
@@ -0,0 +1,45 @@
+package cardScripts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeyScripts    = "Card Scripts"
+	keyScriptDirectory = "directory"
+)
+
+func (s *Service) ConfigFileName() string {
+	return "card_scripts.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	cfg.Group(groupKeyScripts).Set(keyScriptDirectory, "card_scripts")
+
+	return
+}
+
+// scriptDirectory yields the absolute path of the card script directory,
+// relative paths are relative to the config file directory. The directory
+// is created if it does not exist yet.
+func (s *Service) scriptDirectory() (string, error) {
+	cfg, err := s.cfg.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		return "", err
+	}
+
+	dir := cfg.Group(groupKeyScripts).GetString(keyScriptDirectory)
+	if !filepath.IsAbs(dir) {
+		dir = s.cfg.GetFilePath(dir)
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %v", dir, err)
+	}
+
+	return dir, nil
+}
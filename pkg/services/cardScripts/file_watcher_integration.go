@@ -0,0 +1,122 @@
+package cardScripts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/services/fileWatcher"
+)
+
+// FileHandlers watches the script directory rather than the scripts, so
+// that scripts which are created later are loaded too
+func (s *Service) FileHandlers() map[string]fileWatcher.FileHandlerFunc {
+	handlers := make(map[string]fileWatcher.FileHandlerFunc)
+
+	dir, err := s.scriptDirectory()
+	if err != nil {
+		s.logger.Error().Msgf("getting card script directory: %v", err)
+		return handlers
+	}
+
+	handlers[dir] = s.scriptChanged
+
+	return handlers
+}
+
+// scriptChanged handles a change in the script directory. Scripts which are
+// written are (re)loaded, and the cards of scripts which are removed are
+// forgotten.
+func (s *Service) scriptChanged(path string) error {
+	if !strings.HasSuffix(path, ".lua") {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		s.endScript(s.beginScript(path), false)
+		s.logger.Info().Msgf("removed card script %q", path)
+
+		return nil
+	}
+
+	return s.runScriptLocked(path)
+}
+
+// runScript (re)loads a card script. Every card the script defined the last
+// time it was run is forgotten first, so that editing a script hot-reloads
+// its cards.
+func (s *Service) runScript(path string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.runScriptLocked(path)
+}
+
+// runScriptLocked is runScript, the lock must be held
+func (s *Service) runScriptLocked(path string) error {
+	if s.state == nil {
+		return fmt.Errorf("loading card script %q: lua environment is not ready", path)
+	}
+
+	previous := s.beginScript(path)
+
+	err := s.state.DoFile(path)
+
+	s.endScript(previous, err != nil)
+
+	if err != nil {
+		return fmt.Errorf("loading card script %q: %v", path, err)
+	}
+
+	s.logger.Info().Msgf("loaded card script %q", path)
+
+	return nil
+}
+
+// beginScript forgets the cards a script has defined before, and yields them
+func (s *Service) beginScript(path string) map[string]*definition {
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	previous := make(map[string]*definition)
+
+	for name, d := range s.definitions {
+		if d.script == path {
+			previous[name] = d
+			delete(s.definitions, name)
+		}
+	}
+
+	s.currentScript = path
+
+	return previous
+}
+
+// endScript restores the cards of the last version of the script that
+// worked, if running the script failed. The cards the failed run defined
+// before failing are forgotten.
+func (s *Service) endScript(previous map[string]*definition, failed bool) {
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	script := s.currentScript
+	s.currentScript = ""
+
+	if !failed {
+		return
+	}
+
+	for name, d := range s.definitions {
+		if d.script == script {
+			delete(s.definitions, name)
+		}
+	}
+
+	for name, d := range previous {
+		s.definitions[name] = d
+	}
+}
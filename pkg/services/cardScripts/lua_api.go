@@ -0,0 +1,287 @@
+package cardScripts
+
+import (
+	"fmt"
+	"sort"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/gravestench/mtg/pkg/game"
+)
+
+var (
+	knownEvents = []game.EventKind{
+		game.EventEntersBattlefield,
+		game.EventLeavesBattlefield,
+		game.EventDies,
+		game.EventTapped,
+		game.EventBeginTurn,
+		game.EventDraw,
+	}
+
+	knownScopes = []game.StaticScope{
+		game.ScopeSelf,
+		game.ScopeCreaturesYouControl,
+		game.ScopeOtherCreaturesYouControl,
+		game.ScopeCreaturesOpponentsControl,
+		game.ScopeAllCreatures,
+		game.ScopeAllOtherCreatures,
+	}
+)
+
+// luaDefine implements `cards.define(definition)`
+func (s *Service) luaDefine(L *lua.LState) int {
+	def := L.CheckTable(1)
+
+	name := lua.LVAsString(def.RawGetString("name"))
+	if name == "" {
+		L.ArgError(1, "card definition needs a name")
+		return 0
+	}
+
+	behavior, err := s.parseBehavior(def)
+	if err != nil {
+		L.RaiseError("defining %q: %v", name, err)
+		return 0
+	}
+
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	s.definitions[name] = &definition{script: s.currentScript, behavior: behavior}
+
+	return 0
+}
+
+// luaList implements `cards.list()`, yielding a sorted list of names
+func (s *Service) luaList(L *lua.LState) int {
+	s.defsMux.Lock()
+
+	names := make([]string, 0, len(s.definitions))
+	for name := range s.definitions {
+		names = append(names, name)
+	}
+
+	s.defsMux.Unlock()
+
+	sort.Strings(names)
+
+	list := L.NewTable()
+	for _, name := range names {
+		list.Append(lua.LString(name))
+	}
+
+	L.Push(list)
+
+	return 1
+}
+
+func (s *Service) parseBehavior(def *lua.LTable) (*game.Behavior, error) {
+	b := &game.Behavior{}
+
+	power, hasPower := def.RawGetString("power").(lua.LNumber)
+	toughness, hasToughness := def.RawGetString("toughness").(lua.LNumber)
+
+	if hasPower != hasToughness {
+		return nil, fmt.Errorf("a creature needs both power and toughness")
+	}
+
+	b.Creature, b.Power, b.Toughness = hasPower, int(power), int(toughness)
+
+	var err error
+
+	forEachTable(def.RawGetString("triggers"), func(idx int, t *lua.LTable) {
+		if err != nil {
+			return
+		}
+
+		event := game.EventKind(lua.LVAsString(t.RawGetString("event")))
+		if !containsEvent(event) {
+			err = fmt.Errorf("trigger %d: unknown event %q", idx, event)
+			return
+		}
+
+		fn, ok := t.RawGetString("effect").(*lua.LFunction)
+		if !ok {
+			err = fmt.Errorf("trigger %d: effect must be a function", idx)
+			return
+		}
+
+		b.Triggers = append(b.Triggers, game.Trigger{Event: event, Effect: s.wrapEffect(fn)})
+	})
+
+	forEachTable(def.RawGetString("abilities"), func(idx int, t *lua.LTable) {
+		if err != nil {
+			return
+		}
+
+		ability := game.Ability{Text: lua.LVAsString(t.RawGetString("text"))}
+
+		if cost, ok := t.RawGetString("cost").(*lua.LTable); ok {
+			ability.Cost.Tap = lua.LVAsBool(cost.RawGetString("tap"))
+			ability.Cost.Sacrifice = lua.LVAsBool(cost.RawGetString("sacrifice"))
+			ability.Cost.Life = int(lua.LVAsNumber(cost.RawGetString("life")))
+		}
+
+		if fn, ok := t.RawGetString("effect").(*lua.LFunction); ok {
+			ability.Effect = s.wrapEffect(fn)
+		}
+
+		b.Abilities = append(b.Abilities, ability)
+	})
+
+	forEachTable(def.RawGetString("static"), func(idx int, t *lua.LTable) {
+		if err != nil {
+			return
+		}
+
+		static := game.Static{
+			Scope:     game.StaticScope(lua.LVAsString(t.RawGetString("scope"))),
+			Power:     int(lua.LVAsNumber(t.RawGetString("power"))),
+			Toughness: int(lua.LVAsNumber(t.RawGetString("toughness"))),
+		}
+
+		if static.Scope == "" {
+			static.Scope = game.ScopeSelf
+		}
+
+		if !containsScope(static.Scope) {
+			err = fmt.Errorf("static %d: unknown scope %q", idx, static.Scope)
+			return
+		}
+
+		b.Statics = append(b.Statics, static)
+	})
+
+	return b, err
+}
+
+// wrapEffect turns a lua function into a game effect. The function is
+// called with a context table which is the only way for the script to
+// change the game. The game holds the lua lock while effects resolve.
+func (s *Service) wrapEffect(fn *lua.LFunction) game.Effect {
+	return func(ctx *game.EffectContext) error {
+		if s.state == nil {
+			return fmt.Errorf("lua environment is not ready")
+		}
+
+		L := s.state
+
+		return L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, s.newEffectContextTable(L, ctx))
+	}
+}
+
+func (s *Service) newEffectContextTable(L *lua.LState, ctx *game.EffectContext) *lua.LTable {
+	t := L.NewTable()
+
+	L.SetField(t, "card", lua.LString(ctx.Source.Name))
+	L.SetField(t, "id", lua.LNumber(ctx.Source.ID))
+	L.SetField(t, "controller", lua.LString(ctx.Controller()))
+
+	if ctx.Event != nil {
+		L.SetField(t, "event", lua.LString(ctx.Event.Kind))
+		L.SetField(t, "player", lua.LString(ctx.Event.Player))
+	}
+
+	// optionalPlayer reads an optional player name argument, which
+	// defaults to the controller of the card
+	optionalPlayer := func(L *lua.LState, n int) string {
+		return L.OptString(n, ctx.Controller())
+	}
+
+	check := func(L *lua.LState, err error) {
+		if err != nil {
+			L.RaiseError("%v", err)
+		}
+	}
+
+	functions := map[string]lua.LGFunction{
+		"draw": func(L *lua.LState) int {
+			check(L, ctx.Draw(optionalPlayer(L, 2), L.OptInt(1, 1)))
+			return 0
+		},
+		"gain_life": func(L *lua.LState) int {
+			check(L, ctx.ChangeLife(optionalPlayer(L, 2), L.CheckInt(1)))
+			return 0
+		},
+		"lose_life": func(L *lua.LState) int {
+			check(L, ctx.ChangeLife(optionalPlayer(L, 2), -L.CheckInt(1)))
+			return 0
+		},
+		"life": func(L *lua.LState) int {
+			life, err := ctx.Life(optionalPlayer(L, 1))
+			check(L, err)
+			L.Push(lua.LNumber(life))
+			return 1
+		},
+		"move": func(L *lua.LState) int {
+			check(L, ctx.Move(L.CheckInt(1), game.Zone(L.CheckString(2))))
+			return 0
+		},
+		"tap": func(L *lua.LState) int {
+			check(L, ctx.SetTapped(L.OptInt(1, ctx.Source.ID), true))
+			return 0
+		},
+		"untap": func(L *lua.LState) int {
+			check(L, ctx.SetTapped(L.OptInt(1, ctx.Source.ID), false))
+			return 0
+		},
+		"players": func(L *lua.LState) int {
+			L.Push(stringList(L, ctx.Players()))
+			return 1
+		},
+		"opponents": func(L *lua.LState) int {
+			L.Push(stringList(L, ctx.Opponents()))
+			return 1
+		},
+	}
+
+	for name, fn := range functions {
+		L.SetField(t, name, L.NewFunction(fn))
+	}
+
+	return t
+}
+
+func forEachTable(value lua.LValue, fn func(idx int, t *lua.LTable)) {
+	list, ok := value.(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	for idx := 1; idx <= list.Len(); idx++ {
+		if t, ok := list.RawGetInt(idx).(*lua.LTable); ok {
+			fn(idx, t)
+		}
+	}
+}
+
+func stringList(L *lua.LState, values []string) *lua.LTable {
+	t := L.NewTable()
+
+	for _, value := range values {
+		t.Append(lua.LString(value))
+	}
+
+	return t
+}
+
+func containsEvent(e game.EventKind) bool {
+	for _, known := range knownEvents {
+		if known == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsScope(scope game.StaticScope) bool {
+	for _, known := range knownScopes {
+		if known == scope {
+			return true
+		}
+	}
+
+	return false
+}
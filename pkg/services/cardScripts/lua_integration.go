@@ -0,0 +1,81 @@
+package cardScripts
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const luaGlobalCards = "cards"
+
+// these methods are automatically invoked
+// by the lua service to export stuff into the
+// lua environment for use in scripts. The lua
+// service holds its lock while exporting.
+
+func (s *Service) ExportToLua(state *lua.LState) {
+	s.defsMux.Lock()
+	if s.definitions == nil {
+		s.definitions = make(map[string]*definition)
+	}
+	s.defsMux.Unlock()
+
+	s.state = state
+
+	api := state.NewTable()
+	state.SetField(api, "define", state.NewFunction(s.luaDefine))
+	state.SetField(api, "list", state.NewFunction(s.luaList))
+	state.SetGlobal(luaGlobalCards, api)
+
+	// now that the api exists, the card scripts can be run
+	s.loadAllScripts()
+}
+
+func (s *Service) UnexportFromLua(state *lua.LState) {
+	s.Lock()
+	defer s.Unlock()
+
+	state.SetGlobal(luaGlobalCards, lua.LNil)
+	s.state = nil
+}
+
+// loadAllScripts runs every script, the lock must be held
+func (s *Service) loadAllScripts() {
+	for _, path := range s.scriptPaths() {
+		if err := s.runScriptLocked(path); err != nil {
+			s.logger.Error().Msgf("%v", err)
+		}
+	}
+}
+
+// scriptPaths yields every lua file in the script directory
+func (s *Service) scriptPaths() []string {
+	dir, err := s.scriptDirectory()
+	if err != nil {
+		s.logger.Error().Msgf("getting card script directory: %v", err)
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		s.logger.Error().Msgf("reading card script directory: %v", err)
+		return nil
+	}
+
+	paths := make([]string, 0)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lua") {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,40 @@
+package cardScripts
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/lua"
+)
+
+// the following methods are invoked by the runtime
+// automatically in an endless loop. As soon as the
+// dependencies are resolved, the Init method is called.
+
+func (s *Service) DependenciesResolved() bool {
+	if s.cfg == nil {
+		return false
+	}
+
+	s.bindMux.RLock()
+	defer s.bindMux.RUnlock()
+
+	if s.lua == nil {
+		return false
+	}
+
+	return true
+}
+
+func (s *Service) ResolveDependencies(rt runtime.R) {
+	for _, service := range rt.Services() {
+		switch candidate := service.(type) {
+		case configFile.Dependency:
+			s.cfg = candidate
+		case lua.Dependency:
+			s.bindMux.Lock()
+			s.lua = candidate
+			s.bindMux.Unlock()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cardScripts
+
+import (
+	"sync"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/gravestench/mtg/pkg/game"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	luaService "github.com/gravestench/mtg/pkg/services/lua"
+)
+
+type Service struct {
+	logger *zerolog.Logger
+	cfg    configFile.Dependency
+
+	// the lua state machine is shared with the lua service, and every call
+	// into it made by this service holds the lock of the lua service. Games
+	// hold it while they apply an action, see Lock.
+	bindMux    sync.RWMutex
+	lua        luaService.Dependency
+	unboundMux sync.Mutex
+	state      *lua.LState
+
+	defsMux       sync.Mutex
+	definitions   map[string]*definition
+	currentScript string
+}
+
+// definition is a card registered by a script
+type definition struct {
+	script   string
+	behavior *game.Behavior
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	if s.definitions == nil {
+		s.definitions = make(map[string]*definition)
+	}
+}
+
+func (s *Service) Name() string {
+	return "Card Scripts"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Behavior yields the scripted behavior of a card, or nil if no script
+// defines the card
+func (s *Service) Behavior(name string) *game.Behavior {
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	if d, found := s.definitions[name]; found {
+		return d.behavior
+	}
+
+	return nil
+}
+
+// CardNames yields the names of all scripted cards
+func (s *Service) CardNames() []string {
+	s.defsMux.Lock()
+	defer s.defsMux.Unlock()
+
+	names := make([]string, 0, len(s.definitions))
+	for name := range s.definitions {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Lock satisfies sync.Locker. Games lock their behaviors while applying an
+// action, which serializes every scripted effect with script (re)loading and
+// with every other script of the lua service.
+func (s *Service) Lock() {
+	s.bindMux.RLock()
+	s.stateLocker().Lock()
+}
+
+// Unlock satisfies sync.Locker
+func (s *Service) Unlock() {
+	s.stateLocker().Unlock()
+	s.bindMux.RUnlock()
+}
+
+// stateLocker yields the lock of the lua service, or a lock of its own
+// until the lua service is bound. The read lock of bindMux is held from
+// Lock to Unlock, so the lua service is never bound in between.
+func (s *Service) stateLocker() sync.Locker {
+	if s.lua == nil {
+		return &s.unboundMux
+	}
+
+	return s.lua
+}
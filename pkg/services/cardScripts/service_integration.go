@@ -0,0 +1,39 @@
+package cardScripts
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/fileWatcher"
+	"github.com/gravestench/mtg/pkg/services/gameServer"
+	"github.com/gravestench/mtg/pkg/services/lua"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies          = &Service{} // implement in`runtime_dependencies.go`
+	_ configFile.HasDefaultConfig      = &Service{} // implement in`config_file_integration.go`
+	_ lua.UsesLuaEnvironment           = &Service{} // implement in`lua_integration.go`
+	_ fileWatcher.NeedsFileWatcher     = &Service{} // implement in`file_watcher_integration.go`
+	_ gameServer.ProvidesCardBehaviors = &Service{} // implement in`service.go`
+	_ ProvidesCardScripts              = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = ProvidesCardScripts
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type ProvidesCardScripts interface {
+	gameServer.ProvidesCardBehaviors
+	CardNames() []string
+}
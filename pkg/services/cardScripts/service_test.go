@@ -0,0 +1,113 @@
+package cardScripts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/internal/servicetest"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/fileWatcher"
+	"github.com/gravestench/mtg/pkg/services/lua"
+)
+
+// TestCardScripts loads scripts in a runtime like the one of the app, and
+// hot-reloads them as the script directory changes
+func TestCardScripts(t *testing.T) {
+	dir := t.TempDir()
+	scripts := filepath.Join(dir, "scripts")
+
+	writeScript(t, dir, "init.lua", `x = 1`)
+	writeScript(t, scripts, "alpha.lua", alphaScript(1))
+
+	servicetest.WriteConfig(t, dir, "lua_environment.json", "Lua Environment", map[string]any{
+		"init script": filepath.Join(dir, "init.lua"),
+	})
+
+	servicetest.WriteConfig(t, dir, "card_scripts.json", groupKeyScripts, map[string]any{
+		keyScriptDirectory: scripts,
+	})
+
+	s := &Service{}
+	servicetest.StartRuntime(t, &configFile.Service{RootDirectory: dir}, &lua.Service{}, &fileWatcher.Service{}, s)
+
+	waitFor(t, "the scripted card", func() bool {
+		return power(s, "Alpha") == 1
+	})
+
+	// a script which is created after the start is loaded too
+	writeScript(t, scripts, "beta.lua", `cards.define{ name = "Beta" }`)
+
+	waitFor(t, "the new script", func() bool {
+		return s.Behavior("Beta") != nil
+	})
+
+	writeScript(t, scripts, "alpha.lua", alphaScript(2))
+
+	waitFor(t, "the edited script", func() bool {
+		return power(s, "Alpha") == 2
+	})
+
+	// a script which fails keeps the cards of its last working version
+	writeScript(t, scripts, "alpha.lua", `cards.define{ name = "Gamma" } error("broken")`)
+
+	if err := s.runScript(filepath.Join(scripts, "alpha.lua")); err == nil {
+		t.Fatal("expected the broken script to fail")
+	}
+
+	if power(s, "Alpha") != 2 || s.Behavior("Gamma") != nil {
+		t.Fatalf("expected the last working cards, got %v", s.CardNames())
+	}
+
+	if err := os.Remove(filepath.Join(scripts, "beta.lua")); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "the removed script", func() bool {
+		return s.Behavior("Beta") == nil
+	})
+}
+
+func alphaScript(power int) string {
+	return fmt.Sprintf(`cards.define{ name = "Alpha", power = %d, toughness = 1 }`, power)
+}
+
+func power(s *Service, name string) int {
+	if b := s.Behavior(name); b != nil {
+		return b.Power
+	}
+
+	return 0
+}
+
+// writeScript writes a file next to its path and renames it, so that the
+// file watcher never sees it half written
+func writeScript(t *testing.T, dir, name, source string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path+".tmp", []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
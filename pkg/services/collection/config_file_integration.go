@@ -0,0 +1,38 @@
+package collection
+
+import (
+	"path/filepath"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeyCollection = "Collection"
+	keyCollectionFile  = "file"
+)
+
+func (s *Service) ConfigFileName() string {
+	return "collection.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	cfg.Group(groupKeyCollection).Set(keyCollectionFile, "my_collection.json")
+
+	return
+}
+
+// collectionFilePath yields the absolute path of the collection file,
+// relative paths are relative to the config file directory
+func (s *Service) collectionFilePath() (string, error) {
+	cfg, err := s.cfg.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		return "", err
+	}
+
+	path := cfg.Group(groupKeyCollection).GetString(keyCollectionFile)
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return s.cfg.GetFilePath(path), nil
+}
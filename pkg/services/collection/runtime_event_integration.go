@@ -0,0 +1,54 @@
+package collection
+
+import (
+	"github.com/gravestench/runtime"
+)
+
+func (s *Service) OnServiceAdded(args ...any) {
+	if len(args) < 1 {
+		return
+	}
+
+	if candidate, ok := args[0].(runtime.Service); ok {
+		s.tryToBindDeckProvider(candidate)
+		s.tryToBindSetResolver(candidate)
+	}
+}
+
+// deck providers are optional, they are bound whenever they are added
+func (s *Service) tryToBindDeckProvider(service runtime.Service) {
+	candidate, ok := service.(ProvidesDecks)
+	if !ok {
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, provider := range s.deckProviders {
+		if provider == candidate {
+			return
+		}
+	}
+
+	s.deckProviders = append(s.deckProviders, candidate)
+	s.logger.Info().Msgf("missing cards will list the decks of %q", service.Name())
+}
+
+// set resolvers are optional, one is bound whenever it is added
+func (s *Service) tryToBindSetResolver(service runtime.Service) {
+	candidate, ok := service.(ResolvesSetCodes)
+	if !ok {
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.sets == candidate {
+		return
+	}
+
+	s.sets = candidate
+	s.logger.Info().Msgf("imported set names are resolved to set codes by %q", service.Name())
+}
@@ -0,0 +1,165 @@
+package collection
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/collection"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+type Service struct {
+	logger *zerolog.Logger
+	cfg    configFile.Dependency
+
+	collection *collection.Collection
+
+	mux           sync.Mutex
+	deckProviders []ProvidesDecks
+	sets          ResolvesSetCodes
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	path, err := s.collectionFilePath()
+	if err != nil {
+		s.logger.Fatal().Msgf("loading config file: %v", err)
+	}
+
+	c, err := collection.Open(path)
+	if err != nil {
+		s.logger.Fatal().Msgf("opening collection: %v", err)
+	}
+
+	s.collection = c
+	s.logger.Info().Msgf("using collection %q", path)
+
+	for _, service := range rt.Services() {
+		// try to bind existing services
+		s.tryToBindDeckProvider(service)
+		s.tryToBindSetResolver(service)
+		// there is a runtime event handler that does this in runtime_event_integration.go
+	}
+}
+
+func (s *Service) Name() string {
+	return "Collection"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Items yields every stack of cards in the collection, sorted by name
+func (s *Service) Items() []collection.Item {
+	return s.collection.Items()
+}
+
+// Add adds cards to the collection and saves it
+func (s *Service) Add(items ...collection.Item) error {
+	s.collection.Add(items...)
+	return s.collection.Save()
+}
+
+// Remove removes cards from the collection and saves it, it yields how many
+// cards were removed
+func (s *Service) Remove(item collection.Item, quantity int) (int, error) {
+	removed := s.collection.Remove(item, quantity)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, s.collection.Save()
+}
+
+// ImportCSV adds the cards of a Moxfield, Deckbox or Archidekt collection
+// export. The rows which could be read are imported even if others could
+// not, those are reported as decklist.Errors.
+func (s *Service) ImportCSV(data string) ([]collection.Item, error) {
+	items, errParse := collection.ImportCSV(data, s.setLookup())
+	if errParse != nil {
+		s.logger.Warn().Msgf("importing collection: %v", errParse)
+	}
+
+	if len(items) == 0 {
+		return nil, errParse
+	}
+
+	if err := s.Add(items...); err != nil {
+		return nil, err
+	}
+
+	return items, errParse
+}
+
+// setLookup resolves set codes and set names with the bound set resolver,
+// or is nil without one
+func (s *Service) setLookup() collection.SetLookup {
+	s.mux.Lock()
+	sets := s.sets
+	s.mux.Unlock()
+
+	if sets == nil {
+		return nil
+	}
+
+	return func(codeOrName string) (string, bool) {
+		code, err := sets.SetCode(codeOrName)
+		return code, err == nil
+	}
+}
+
+// Missing yields the cards of a deck which are not in the collection, and
+// the other known decks which use them
+func (s *Service) Missing(deck *decklist.Deck, exactPrintings bool) []collection.MissingCard {
+	return s.collection.Missing(deck, exactPrintings, s.decks())
+}
+
+// decks yields every deck of every deck provider, keyed by provider and
+// deck name, like "TappedOut/a-slow-painful-death"
+func (s *Service) decks() map[string]*decklist.Deck {
+	s.mux.Lock()
+	providers := append([]ProvidesDecks(nil), s.deckProviders...)
+	s.mux.Unlock()
+
+	all := make(map[string]*decklist.Deck)
+
+	for _, provider := range providers {
+		decks, err := provider.Decks()
+		if err != nil {
+			s.logger.Warn().Msgf("getting decks from %s: %v", provider.Name(), err)
+			continue
+		}
+
+		for name, deck := range decks {
+			all[provider.Name()+"/"+name] = deck
+		}
+	}
+
+	return all
+}
+
+// deckProviderNames yields the names of the bound deck providers, sorted
+func (s *Service) deckProviderNames() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	names := make([]string, 0, len(s.deckProviders))
+	for _, provider := range s.deckProviders {
+		names = append(names, provider.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
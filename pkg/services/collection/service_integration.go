@@ -0,0 +1,57 @@
+package collection
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/collection"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies          = &Service{} // implement in`runtime_dependencies.go`
+	_ runtime.EventHandlerServiceAdded = &Service{} // implement in`runtime_event_integration.go`
+	_ configFile.HasDefaultConfig      = &Service{} // implement in`config_file_integration.go`
+	_ webRouter.IsRouteInitializer     = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug           = &Service{} // implement in`web_router_integration.go`
+	_ ManagesCollection                = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = ManagesCollection
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type ManagesCollection interface {
+	Items() []collection.Item
+	Add(items ...collection.Item) error
+	Remove(item collection.Item, quantity int) (int, error)
+	ImportCSV(data string) ([]collection.Item, error)
+	Missing(deck *decklist.Deck, exactPrintings bool) []collection.MissingCard
+}
+
+// ProvidesDecks is a service which knows decks, like the tappedout
+// service. Missing cards list the decks of every deck provider using them.
+type ProvidesDecks interface {
+	runtime.Service
+	Decks() (map[string]*decklist.Deck, error)
+}
+
+// ResolvesSetCodes is a service which knows the sets, like the set info
+// service. It resolves the set codes and set names of imported collections
+// to set codes.
+type ResolvesSetCodes interface {
+	runtime.Service
+	SetCode(codeOrName string) (string, error)
+}
@@ -0,0 +1,94 @@
+package collection
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/collection"
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func (s *Service) Slug() string {
+	return "collection"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("", s.handleGetItems)
+	group.POST("", s.handleAddItems)
+	group.POST("import", s.handleImportCSV)
+	group.POST("missing", s.handleMissing)
+	group.GET("decks", s.handleGetDeckProviders)
+}
+
+func (s *Service) handleGetItems(c *gin.Context) {
+	c.JSON(http.StatusOK, s.Items())
+}
+
+// handleAddItems adds the json array of items in the request body
+func (s *Service) handleAddItems(c *gin.Context) {
+	var items []collection.Item
+
+	if err := c.ShouldBindJSON(&items); err != nil {
+		c.String(http.StatusBadRequest, "decoding items: %v", err)
+		return
+	}
+
+	if err := s.Add(items...); err != nil {
+		c.String(http.StatusInternalServerError, "adding items: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, s.Items())
+}
+
+// handleImportCSV imports the collection export in the request body, the
+// rows which could not be read are listed in the response
+func (s *Service) handleImportCSV(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "reading csv: %v", err)
+		return
+	}
+
+	items, err := s.ImportCSV(string(body))
+
+	var lineErrors decklist.Errors
+	if err != nil && !errors.As(err, &lineErrors) {
+		c.String(http.StatusInternalServerError, "importing csv: %v", err)
+		return
+	}
+
+	rejected := make([]string, 0, len(lineErrors))
+	for _, lineError := range lineErrors {
+		rejected = append(rejected, lineError.Error())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imported": items, "rejected": rejected})
+}
+
+// handleMissing yields the cards of the deck list in the request body which
+// are not in the collection. With ?exact=true, printings must match.
+func (s *Service) handleMissing(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "reading deck list: %v", err)
+		return
+	}
+
+	deck, err := decklist.Parse(string(body))
+	if err != nil {
+		s.logger.Warn().Msgf("parsing deck list: %v", err)
+	}
+
+	exact, _ := strconv.ParseBool(c.Query("exact"))
+
+	c.JSON(http.StatusOK, s.Missing(deck, exact))
+}
+
+func (s *Service) handleGetDeckProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, s.deckProviderNames())
+}
@@ -0,0 +1,38 @@
+package deckLibrary
+
+import (
+	"path/filepath"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeyDeckLibrary = "Deck Library"
+	keyDirectory        = "directory"
+)
+
+func (s *Service) ConfigFileName() string {
+	return "deck_library.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	cfg.Group(groupKeyDeckLibrary).Set(keyDirectory, "decks")
+
+	return
+}
+
+// libraryDirectory yields the absolute path of the library directory,
+// relative paths are relative to the config file directory
+func (s *Service) libraryDirectory() (string, error) {
+	cfg, err := s.cfg.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		return "", err
+	}
+
+	path := cfg.Group(groupKeyDeckLibrary).GetString(keyDirectory)
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return s.cfg.GetFilePath(path), nil
+}
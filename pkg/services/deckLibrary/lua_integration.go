@@ -0,0 +1,180 @@
+package deckLibrary
+
+import (
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/gravestench/mtg/pkg/decklibrary"
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+const luaGlobalLibrary = "library"
+
+// these methods are automatically invoked
+// by the lua service to export stuff into the
+// lua environment for use in scripts.
+
+func (s *Service) ExportToLua(state *lua.LState) {
+	functions := map[string]lua.LGFunction{
+		"fetch":  s.luaFetch,
+		"list":   s.luaList,
+		"search": s.luaSearch,
+		"get":    s.luaGet,
+		"tag":    s.luaTag,
+		"untag":  s.luaUntag,
+		"rename": s.luaRename,
+		"delete": s.luaDelete,
+	}
+
+	api := state.NewTable()
+	for name, fn := range functions {
+		state.SetField(api, name, state.NewFunction(fn))
+	}
+
+	state.SetGlobal(luaGlobalLibrary, api)
+}
+
+func (s *Service) UnexportFromLua(state *lua.LState) {
+	state.SetGlobal(luaGlobalLibrary, lua.LNil)
+}
+
+// luaFetch implements `library.fetch(url)`, yielding the fetched deck
+func (s *Service) luaFetch(L *lua.LState) int {
+	entry, err := s.Fetch(L.CheckString(1))
+	if err != nil {
+		L.RaiseError("%v", err)
+		return 0
+	}
+
+	L.Push(entryTable(L, entry))
+
+	return 1
+}
+
+// luaList implements `library.list()`, yielding every deck by name
+func (s *Service) luaList(L *lua.LState) int {
+	L.Push(entryList(L, s.Entries()))
+	return 1
+}
+
+// luaSearch implements `library.search(card, tag)`, both are optional
+func (s *Service) luaSearch(L *lua.LState) int {
+	entries, err := s.Search(L.OptString(1, ""), L.OptString(2, ""))
+	if err != nil {
+		L.RaiseError("%v", err)
+		return 0
+	}
+
+	L.Push(entryList(L, entries))
+
+	return 1
+}
+
+// luaGet implements `library.get(id)`, yielding the MTG Arena deck list
+func (s *Service) luaGet(L *lua.LState) int {
+	deck, err := s.Deck(L.CheckString(1))
+	if err != nil {
+		L.RaiseError("%v", err)
+		return 0
+	}
+
+	list, err := decklist.Export(decklist.FormatArena, deck)
+	if err != nil {
+		L.RaiseError("%v", err)
+		return 0
+	}
+
+	L.Push(lua.LString(list))
+
+	return 1
+}
+
+// luaTag implements `library.tag(id, tag, ...)`
+func (s *Service) luaTag(L *lua.LState) int {
+	s.luaCheck(L, s.Tag(L.CheckString(1), restStrings(L, 2)...))
+	return 0
+}
+
+// luaUntag implements `library.untag(id, tag, ...)`
+func (s *Service) luaUntag(L *lua.LState) int {
+	s.luaCheck(L, s.Untag(L.CheckString(1), restStrings(L, 2)...))
+	return 0
+}
+
+// luaRename implements `library.rename(id, name)`
+func (s *Service) luaRename(L *lua.LState) int {
+	s.luaCheck(L, s.Rename(L.CheckString(1), L.CheckString(2)))
+	return 0
+}
+
+// luaDelete implements `library.delete(id)`
+func (s *Service) luaDelete(L *lua.LState) int {
+	s.luaCheck(L, s.Delete(L.CheckString(1)))
+	return 0
+}
+
+func (s *Service) luaCheck(L *lua.LState, err error) {
+	if err != nil {
+		L.RaiseError("%v", err)
+	}
+}
+
+func restStrings(L *lua.LState, from int) []string {
+	values := make([]string, 0)
+
+	for idx := from; idx <= L.GetTop(); idx++ {
+		values = append(values, L.CheckString(idx))
+	}
+
+	return values
+}
+
+func entryList(L *lua.LState, entries []decklibrary.Entry) *lua.LTable {
+	list := L.NewTable()
+
+	for _, entry := range entries {
+		list.Append(entryTable(L, entry))
+	}
+
+	return list
+}
+
+func entryTable(L *lua.LState, entry decklibrary.Entry) *lua.LTable {
+	t := L.NewTable()
+
+	L.SetField(t, "id", lua.LString(entry.ID))
+	L.SetField(t, "name", lua.LString(entry.Name))
+	L.SetField(t, "source", lua.LString(entry.Source))
+	L.SetField(t, "url", lua.LString(entry.URL))
+	L.SetField(t, "author", lua.LString(entry.Author))
+	L.SetField(t, "format", lua.LString(entry.Format))
+	L.SetField(t, "cards", lua.LNumber(entry.Cards))
+	L.SetField(t, "fetched", lua.LString(entry.Fetched.Format(time.RFC3339)))
+
+	tags := L.NewTable()
+	for _, tag := range entry.Tags {
+		tags.Append(lua.LString(tag))
+	}
+
+	L.SetField(t, "tags", tags)
+
+	categories := L.NewTable()
+	for _, category := range entry.Categories {
+		cards := L.NewTable()
+		for _, card := range category.Entries {
+			L.SetField(cards, card.Name, lua.LNumber(card.Count))
+		}
+
+		c := L.NewTable()
+		L.SetField(c, "name", lua.LString(category.Name))
+		L.SetField(c, "section", lua.LString(string(category.Section)))
+		L.SetField(c, "cards", cards)
+
+		categories.Append(c)
+	}
+
+	L.SetField(t, "categories", categories)
+
+	return t
+}
@@ -0,0 +1,119 @@
+package deckLibrary
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (s *Service) ModalTui() (name string, model tea.Model) {
+	return s.Name(), &tui{Service: s}
+}
+
+type tui struct {
+	*Service
+
+	// the index of the selected deck
+	cursor int
+
+	// whether the cards of the selected deck are shown, grouped by set
+	bySet bool
+}
+
+func (m *tui) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "s":
+		m.bySet = !m.bySet
+	}
+
+	return m, nil
+}
+
+func (m *tui) View() string {
+	if m.library == nil {
+		return "the deck library is not open yet"
+	}
+
+	entries := m.Entries()
+	if len(entries) == 0 {
+		return "the deck library is empty"
+	}
+
+	// decks may have been deleted since the cursor moved
+	m.cursor = min(max(m.cursor, 0), len(entries)-1)
+
+	styleHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#ef7aef"))
+	styleSelected := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aefef"))
+
+	longestName := 0
+
+	for _, entry := range entries {
+		if len(entry.Name) > longestName {
+			longestName = len(entry.Name)
+		}
+	}
+
+	rAlign := lipgloss.NewStyle().
+		Width(longestName+2).
+		Padding(0, 1).
+		Align(lipgloss.Right)
+
+	lAlign := lipgloss.NewStyle().
+		Width(12).
+		Padding(0, 1).
+		Align(lipgloss.Left)
+
+	output := styleHeader.Render(rAlign.Render("Deck") +
+		lAlign.Render("Format") +
+		lAlign.Render("Cards") +
+		lAlign.Render("Fetched"))
+
+	for idx, entry := range entries {
+		row := rAlign.Render(entry.Name) +
+			lAlign.Render(entry.Format) +
+			lAlign.Render(fmt.Sprint(entry.Cards)) +
+			lAlign.Render(entry.Fetched.Local().Format("2006-01-02"))
+
+		if idx == m.cursor {
+			row = styleSelected.Render(row)
+		}
+
+		output += "\r\n" + row
+	}
+
+	selected := entries[m.cursor]
+
+	output += "\r\n\r\n" + styleHeader.Render(selected.Name) + "\r\n"
+	output += fmt.Sprintf("%s, from %s by %s\r\n", selected.ID, selected.Source, selected.Author)
+	output += selected.URL + "\r\n"
+
+	if len(selected.Tags) > 0 {
+		output += "tags: " + strings.Join(selected.Tags, ", ") + "\r\n"
+	}
+
+	if !m.bySet {
+		return output + "\r\n(s) show the cards by set"
+	}
+
+	list, err := m.DeckBySet(selected.ID)
+	if err != nil {
+		return output + "\r\n" + err.Error()
+	}
+
+	return output + "\r\n" + strings.ReplaceAll(list, "\n", "\r\n")
+}
@@ -0,0 +1,156 @@
+package deckLibrary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/decklibrary"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/setInfo"
+	"github.com/gravestench/mtg/pkg/services/tappedout"
+)
+
+type Service struct {
+	logger *zerolog.Logger
+	cfg    configFile.Dependency
+	decks  tappedout.Dependency
+	sets   setInfo.Binding
+
+	library *decklibrary.Library
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	dir, err := s.libraryDirectory()
+	if err != nil {
+		s.logger.Fatal().Msgf("loading config file: %v", err)
+	}
+
+	library, err := decklibrary.Open(dir)
+	if err != nil {
+		s.logger.Fatal().Msgf("opening deck library: %v", err)
+	}
+
+	s.library = library
+	s.logger.Info().Msgf("using deck library %q", dir)
+
+	for _, service := range rt.Services() {
+		// try to bind existing services
+		s.tryToBindSetInfo(service)
+		// there is a runtime event handler that does this in runtime_event_integration.go
+	}
+}
+
+func (s *Service) Name() string {
+	return "Deck Library"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Fetch fetches a deck from any deck site or file and saves it in the
+// library, a deck which is already there is updated
+func (s *Service) Fetch(uri string) (decklibrary.Entry, error) {
+	deck, err := s.decks.FetchDeck(uri)
+	if err != nil {
+		return decklibrary.Entry{}, err
+	}
+
+	entry, err := s.library.Put(deck, time.Now())
+	if err != nil {
+		return decklibrary.Entry{}, fmt.Errorf("saving %s in the deck library: %v", uri, err)
+	}
+
+	return entry, nil
+}
+
+// Entries yields the metadata of every deck, by name
+func (s *Service) Entries() []decklibrary.Entry {
+	return s.library.Entries()
+}
+
+// Entry yields the metadata of a deck
+func (s *Service) Entry(id string) (decklibrary.Entry, error) {
+	return s.library.Entry(id)
+}
+
+// Deck loads a deck of the library
+func (s *Service) Deck(id string) (*decklist.Deck, error) {
+	return s.library.Deck(id)
+}
+
+// DeckBySet writes the deck list of a deck grouped by the sets of the
+// printings, from the oldest set to the newest. Without the set info service
+// the sets are sorted by code.
+func (s *Service) DeckBySet(id string) (string, error) {
+	deck, err := s.library.Deck(id)
+	if err != nil {
+		return "", err
+	}
+
+	sets := s.sets.Sets()
+
+	if sets == nil {
+		return decklist.ExportBySet(deck, nil), nil
+	}
+
+	idx, err := sets.Index()
+	if err != nil {
+		s.logger.Warn().Msgf("sorting %s by set: %v", id, err)
+		return decklist.ExportBySet(deck, nil), nil
+	}
+
+	return decklist.ExportBySet(deck, func(code string) (string, time.Time, bool) {
+		set, errSet := idx.Set(code)
+		return set.Name, set.ReleaseDate(), errSet == nil
+	}), nil
+}
+
+// Search yields the decks with a card and a tag, either can be empty
+func (s *Service) Search(card, tag string) ([]decklibrary.Entry, error) {
+	return s.library.Search(card, tag)
+}
+
+func (s *Service) Tag(id string, tags ...string) error {
+	return s.library.Tag(id, tags...)
+}
+
+func (s *Service) Untag(id string, tags ...string) error {
+	return s.library.Untag(id, tags...)
+}
+
+func (s *Service) Rename(id, name string) error {
+	return s.library.Rename(id, name)
+}
+
+func (s *Service) Delete(id string) error {
+	return s.library.Delete(id)
+}
+
+// Decks yields every deck of the library by ID, so that the collection
+// service lists them for missing cards
+func (s *Service) Decks() (map[string]*decklist.Deck, error) {
+	decks := make(map[string]*decklist.Deck)
+
+	for _, entry := range s.library.Entries() {
+		deck, err := s.library.Deck(entry.ID)
+		if deck == nil {
+			return nil, err
+		}
+
+		decks[entry.ID] = deck
+	}
+
+	return decks, nil
+}
@@ -0,0 +1,51 @@
+package deckLibrary
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/decklibrary"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/services/collection"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/lua"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies          = &Service{} // implement in`runtime_dependencies.go`
+	_ runtime.EventHandlerServiceAdded = &Service{} // implement in`runtime_event_integration.go`
+	_ configFile.HasDefaultConfig      = &Service{} // implement in`config_file_integration.go`
+	_ lua.UsesLuaEnvironment           = &Service{} // implement in`lua_integration.go`
+	_ webRouter.IsRouteInitializer     = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug           = &Service{} // implement in`web_router_integration.go`
+	_ collection.ProvidesDecks         = &Service{} // implement in`service.go`
+	_ ManagesDeckLibrary               = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = ManagesDeckLibrary
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type ManagesDeckLibrary interface {
+	Fetch(uri string) (decklibrary.Entry, error)
+	Entries() []decklibrary.Entry
+	Entry(id string) (decklibrary.Entry, error)
+	Deck(id string) (*decklist.Deck, error)
+	DeckBySet(id string) (string, error)
+	Search(card, tag string) ([]decklibrary.Entry, error)
+	Tag(id string, tags ...string) error
+	Untag(id string, tags ...string) error
+	Rename(id, name string) error
+	Delete(id string) error
+}
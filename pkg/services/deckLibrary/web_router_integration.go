@@ -0,0 +1,174 @@
+package deckLibrary
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/decklibrary"
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func (s *Service) Slug() string {
+	return "library"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("", s.handleGetEntries)
+	group.POST("", s.handleFetch)
+	group.GET("search", s.handleSearch)
+	group.GET(":id", s.handleGetDeck)
+	group.PUT(":id/name", s.handleRename)
+	group.POST(":id/tags", s.handleTag)
+	group.DELETE(":id/tags/:tag", s.handleUntag)
+	group.DELETE(":id", s.handleDelete)
+}
+
+func (s *Service) handleGetEntries(c *gin.Context) {
+	c.JSON(http.StatusOK, s.Entries())
+}
+
+// handleFetch fetches the deck of the url in the request body, like
+// {"url": "https://tappedout.net/mtg-decks/izzet-delver/"}
+func (s *Service) handleFetch(c *gin.Context) {
+	var body struct {
+		URL string `json:"url"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil || body.URL == "" {
+		c.String(http.StatusBadRequest, "expected a deck url")
+		return
+	}
+
+	entry, err := s.Fetch(body.URL)
+	if err != nil {
+		c.String(http.StatusBadGateway, "fetching deck: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// handleSearch yields the decks with a card and a tag, like
+// search?card=bolt&tag=tempo
+func (s *Service) handleSearch(c *gin.Context) {
+	entries, err := s.Search(c.Query("card"), c.Query("tag"))
+	if err != nil {
+		c.String(http.StatusInternalServerError, "searching deck library: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// handleGetDeck yields the metadata and the cards of a deck. With
+// ?format=arena, text, mtgo or csv the deck list is exported instead, and
+// with ?view=sets the deck list is grouped by set in release order.
+func (s *Service) handleGetDeck(c *gin.Context) {
+	id := c.Param("id")
+
+	if c.Query("view") == "sets" {
+		list, err := s.DeckBySet(id)
+		if err != nil {
+			s.respondError(c, err)
+			return
+		}
+
+		c.String(http.StatusOK, list)
+
+		return
+	}
+
+	entry, err := s.Entry(id)
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	deck, err := s.Deck(id)
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	if format := c.Query("format"); format != "" {
+		list, errExport := decklist.Export(decklist.Format(format), deck)
+		if errExport != nil {
+			c.String(http.StatusBadRequest, "%v", errExport)
+			return
+		}
+
+		c.String(http.StatusOK, list)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entry": entry, "deck": deck})
+}
+
+// handleRename renames a deck to the name in the request body, like
+// {"name": "My Delver"}
+func (s *Service) handleRename(c *gin.Context) {
+	var body struct {
+		Name string `json:"name"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.String(http.StatusBadRequest, "decoding name: %v", err)
+		return
+	}
+
+	s.respondEntry(c, s.Rename(c.Param("id"), body.Name))
+}
+
+// handleTag adds the json array of tags in the request body to a deck
+func (s *Service) handleTag(c *gin.Context) {
+	var tags []string
+
+	if err := c.ShouldBindJSON(&tags); err != nil {
+		c.String(http.StatusBadRequest, "decoding tags: %v", err)
+		return
+	}
+
+	s.respondEntry(c, s.Tag(c.Param("id"), tags...))
+}
+
+func (s *Service) handleUntag(c *gin.Context) {
+	s.respondEntry(c, s.Untag(c.Param("id"), c.Param("tag")))
+}
+
+func (s *Service) handleDelete(c *gin.Context) {
+	if err := s.Delete(c.Param("id")); err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, "deck deleted")
+}
+
+// respondEntry yields the changed deck, or the error which changing it
+// yielded
+func (s *Service) respondEntry(c *gin.Context, err error) {
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	entry, err := s.Entry(c.Param("id"))
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (s *Service) respondError(c *gin.Context, err error) {
+	if errors.Is(err, decklibrary.ErrNotFound) {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.String(http.StatusBadRequest, "%v", err)
+}
@@ -0,0 +1,94 @@
+package deckStats
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/gravestench/mtg/pkg/deckstats"
+)
+
+func (s *Service) ModalTui() (name string, model tea.Model) {
+	return s.Name(), &tui{Service: s}
+}
+
+type tui struct {
+	*Service
+}
+
+func (m *tui) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m, nil
+}
+
+func (m *tui) View() string {
+	deck, stats := m.last()
+	if stats == nil {
+		return "no deck has been analyzed yet"
+	}
+
+	styleHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#ef7aef"))
+	stylePermanent := lipgloss.NewStyle().Foreground(lipgloss.Color("#7aefef"))
+	styleNonPermanent := lipgloss.NewStyle().Foreground(lipgloss.Color("#efef7a"))
+
+	rAlign := lipgloss.NewStyle().
+		Width(14).
+		Padding(0, 1).
+		Align(lipgloss.Right)
+
+	lAlign := lipgloss.NewStyle().
+		Width(12).
+		Padding(0, 1).
+		Align(lipgloss.Left)
+
+	var output string
+
+	if deck.Name != "" {
+		output += styleHeader.Render(deck.Name) + "\r\n"
+	}
+
+	output += fmt.Sprintf("%d cards, %d lands (%d recommended)\r\n", stats.Cards, stats.Lands, stats.RecommendedLands)
+	output += fmt.Sprintf("average mana value %.2f, %.2f without lands\r\n", stats.AverageCMC, stats.AverageCMCWithoutLands)
+
+	// the mana curve, as a bar per mana value
+	output += "\r\n" + styleHeader.Render(rAlign.Render("Mana Value")+" "+
+		stylePermanent.Render("permanents")+" "+styleNonPermanent.Render("non-permanents"))
+
+	for _, bucket := range stats.Curve {
+		label := fmt.Sprint(bucket.CMC)
+		if bucket.CMC == deckstats.MaxCurveCMC {
+			label += "+"
+		}
+
+		output += "\r\n" + rAlign.Render(label) + " " +
+			stylePermanent.Render(strings.Repeat("█", bucket.Permanents)) +
+			styleNonPermanent.Render(strings.Repeat("█", bucket.NonPermanents)) +
+			fmt.Sprintf(" %d", bucket.Permanents+bucket.NonPermanents)
+	}
+
+	// pips and land production, side by side per color
+	output += "\r\n\r\n" + styleHeader.Render(rAlign.Render("Color")+lAlign.Render("Pips")+lAlign.Render("Lands"))
+
+	for _, color := range deckstats.Colors {
+		output += "\r\n" + rAlign.Render(color) +
+			lAlign.Render(fmt.Sprint(stats.Pips[color])) +
+			lAlign.Render(fmt.Sprint(stats.LandProduction[color]))
+	}
+
+	output += "\r\n\r\n" + styleHeader.Render(rAlign.Render("Type")+lAlign.Render("Cards"))
+
+	for _, t := range deckstats.Types {
+		if stats.Types[t] == 0 {
+			continue
+		}
+
+		output += "\r\n" + rAlign.Render(t) + lAlign.Render(fmt.Sprint(stats.Types[t]))
+	}
+
+	return output
+}
@@ -0,0 +1,28 @@
+package deckStats
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+// the following methods are invoked by the runtime
+// automatically in an endless loop. As soon as the
+// dependencies are resolved, the Init method is called.
+
+func (s *Service) DependenciesResolved() bool {
+	if s.scryfall == nil {
+		return false
+	}
+
+	return true
+}
+
+func (s *Service) ResolveDependencies(rt runtime.R) {
+	for _, service := range rt.Services() {
+		switch candidate := service.(type) {
+		case scryfall.Dependency:
+			s.scryfall = candidate
+		}
+	}
+}
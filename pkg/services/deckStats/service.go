@@ -0,0 +1,89 @@
+package deckStats
+
+import (
+	"sync"
+
+	"github.com/BlueMonday/go-scryfall"
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/deckstats"
+	scryfallService "github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+type Service struct {
+	logger   *zerolog.Logger
+	scryfall scryfallService.Dependency
+
+	mux   sync.Mutex
+	cards map[string]*scryfall.Card
+
+	// the last analyzed deck, which is shown in the modal tui
+	lastDeck  *decklist.Deck
+	lastStats *deckstats.Stats
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	s.cards = make(map[string]*scryfall.Card)
+}
+
+func (s *Service) Name() string {
+	return "Deck Stats"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Analyze computes the statistics of a deck
+func (s *Service) Analyze(deck *decklist.Deck) *deckstats.Stats {
+	stats := deckstats.Analyze(deck, s.card)
+
+	if len(stats.Unknown) > 0 {
+		s.logger.Warn().Msgf("cards missing from the deck stats: %v", stats.Unknown)
+	}
+
+	s.mux.Lock()
+	s.lastDeck, s.lastStats = deck, stats
+	s.mux.Unlock()
+
+	return stats
+}
+
+// card looks up a card with scryfall, cards are remembered because decks
+// tend to be analyzed repeatedly while they are being built
+func (s *Service) card(name string) (*scryfall.Card, error) {
+	s.mux.Lock()
+	card, found := s.cards[name]
+	s.mux.Unlock()
+
+	if found {
+		return card, nil
+	}
+
+	card, err := s.scryfall.GetCardByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mux.Lock()
+	s.cards[name] = card
+	s.mux.Unlock()
+
+	return card, nil
+}
+
+func (s *Service) last() (*decklist.Deck, *deckstats.Stats) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.lastDeck, s.lastStats
+}
@@ -0,0 +1,47 @@
+package deckStats
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func (s *Service) Slug() string {
+	return "deck"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("stats", s.handleGetLastStats)
+	group.POST("stats", s.handleAnalyze)
+}
+
+// handleGetLastStats yields the stats of the last analyzed deck
+func (s *Service) handleGetLastStats(c *gin.Context) {
+	_, stats := s.last()
+	if stats == nil {
+		c.String(http.StatusNotFound, "no deck has been analyzed yet")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// handleAnalyze analyzes the deck list in the request body, in any format
+// the decklist package can parse
+func (s *Service) handleAnalyze(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "reading deck list: %v", err)
+		return
+	}
+
+	deck, err := decklist.Parse(string(body))
+	if err != nil {
+		s.logger.Warn().Msgf("parsing deck list: %v", err)
+	}
+
+	c.JSON(http.StatusOK, s.Analyze(deck))
+}
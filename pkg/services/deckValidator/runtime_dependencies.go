@@ -0,0 +1,42 @@
+package deckValidator
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/legality"
+	"github.com/gravestench/mtg/pkg/services/mtgapi"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+// the following methods are invoked by the runtime
+// automatically in an endless loop. As soon as the
+// dependencies are resolved, the Init method is called.
+
+func (s *Service) DependenciesResolved() bool {
+	if s.mtgapi == nil {
+		return false
+	}
+
+	// either a local card database or scryfall
+	if s.cards == nil && s.scryfall == nil {
+		return false
+	}
+
+	return true
+}
+
+func (s *Service) ResolveDependencies(rt runtime.R) {
+	for _, service := range rt.Services() {
+		if candidate, ok := service.(mtgapi.Dependency); ok {
+			s.mtgapi = candidate
+		}
+
+		if candidate, ok := service.(scryfall.Dependency); ok {
+			s.scryfall = candidate
+		}
+
+		if candidate, ok := service.(legality.CardLookup); ok {
+			s.cards = candidate
+		}
+	}
+}
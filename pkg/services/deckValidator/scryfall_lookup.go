@@ -0,0 +1,62 @@
+package deckValidator
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	goscryfall "github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/carddb"
+	"github.com/gravestench/mtg/pkg/legality"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+// scryfallLookup looks up cards online, when there is no local card
+// database. Cards are remembered, decks tend to be validated repeatedly.
+type scryfallLookup struct {
+	client scryfall.Dependency
+	mux    sync.Mutex
+	cards  map[string]*legality.Card
+}
+
+func (l *scryfallLookup) Card(name string) (*legality.Card, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if card, found := l.cards[name]; found {
+		return card, nil
+	}
+
+	if l.client == nil {
+		return nil, errors.New("no card database or scryfall client")
+	}
+
+	result, err := l.client.GetCardByName(name)
+
+	var scryfallErr *goscryfall.Error
+	notFound := errors.As(err, &scryfallErr) && scryfallErr.Status == http.StatusNotFound
+	if notFound || errors.Is(err, carddb.ErrNotFound) {
+		return nil, legality.ErrUnknownCard
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l.cards == nil {
+		l.cards = make(map[string]*legality.Card)
+	}
+
+	// the legalities of the scryfall client lack formats like brawl
+	legalities, err := l.client.GetCardLegalities(result.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	card := legality.FromScryfall(*result)
+	card.Legalities = legalities
+	l.cards[name] = card
+
+	return card, nil
+}
@@ -0,0 +1,87 @@
+package deckValidator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/legality"
+	"github.com/gravestench/mtg/pkg/services/mtgapi"
+	"github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+type Service struct {
+	logger   *zerolog.Logger
+	mtgapi   mtgapi.Dependency
+	scryfall scryfall.Dependency
+
+	// cards is preferably a local card database, which makes validation
+	// work offline. Otherwise, cards are looked up with scryfall.
+	cards legality.CardLookup
+
+	formatsOnce sync.Once
+	formats     []string
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	if s.cards == nil {
+		s.cards = &scryfallLookup{client: s.scryfall}
+	}
+}
+
+func (s *Service) Name() string {
+	return "Deck Validator"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Formats yields the formats which can be validated. These are the formats
+// listed by the mtg api which the validator has rules for. If the mtg api
+// can not be reached, every format the validator has rules for is listed.
+func (s *Service) Formats() []string {
+	s.formatsOnce.Do(func() {
+		listed, err := s.mtgapi.GetFormats()
+		if err != nil {
+			s.logger.Warn().Msgf("getting formats from the mtg api, using built-in formats: %v", err)
+			s.formats = legality.Names()
+
+			return
+		}
+
+		s.formats = make([]string, 0)
+
+		for _, name := range listed {
+			if format, found := legality.Lookup(name); found {
+				s.formats = append(s.formats, format.Name)
+			}
+		}
+
+		if len(s.formats) == 0 {
+			s.formats = legality.Names()
+		}
+	})
+
+	return s.formats
+}
+
+// Validate checks a deck against the construction rules of a format
+func (s *Service) Validate(format string, deck *decklist.Deck) ([]legality.Violation, error) {
+	rules, found := legality.Lookup(format)
+	if !found {
+		return nil, fmt.Errorf("unknown format %q, known formats are %v", format, s.Formats())
+	}
+
+	return legality.Validate(rules, deck, s.cards)
+}
@@ -0,0 +1,36 @@
+package deckValidator
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/legality"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service              = &Service{} // implement in`service.go`
+	_ runtime.HasLogger            = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies      = &Service{} // implement in`runtime_dependencies.go`
+	_ webRouter.IsRouteInitializer = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug       = &Service{} // implement in`web_router_integration.go`
+	_ ValidatesDecks               = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = ValidatesDecks
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type ValidatesDecks interface {
+	Formats() []string
+	Validate(format string, deck *decklist.Deck) ([]legality.Violation, error)
+}
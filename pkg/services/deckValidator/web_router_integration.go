@@ -0,0 +1,59 @@
+package deckValidator
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/legality"
+)
+
+func (s *Service) Slug() string {
+	return "legality"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("formats", s.handleListFormats)
+	group.POST(":format", s.handleValidate)
+}
+
+func (s *Service) handleListFormats(c *gin.Context) {
+	c.JSON(http.StatusOK, s.Formats())
+}
+
+type validateResponse struct {
+	Format     string
+	Legal      bool
+	Violations []legality.Violation
+	ParseError string `json:",omitempty"`
+}
+
+// handleValidate validates the deck list in the request body, in any format
+// the decklist package can parse
+func (s *Service) handleValidate(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "reading deck list: %v", err)
+		return
+	}
+
+	response := validateResponse{Format: c.Param("format")}
+
+	deck, err := decklist.Parse(string(body))
+	if err != nil {
+		response.ParseError = err.Error()
+	}
+
+	violations, err := s.Validate(response.Format, deck)
+	if err != nil {
+		c.String(http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	response.Legal = len(violations) == 0 && response.ParseError == ""
+	response.Violations = violations
+
+	c.JSON(http.StatusOK, response)
+}
@@ -16,5 +16,14 @@ func (s *Service) OnServiceAdded(args ...interface{}) {
 		return
 	}
 
+	s.mux.Lock()
+	initialized := s.watcher != nil
+	s.mux.Unlock()
+
+	// services which are added before the watcher exists are set up by Init
+	if !initialized {
+		return
+	}
+
 	go s.setupServiceToWatchFiles(service)
 }
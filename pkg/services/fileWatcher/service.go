@@ -1,6 +1,7 @@
 package fileWatcher
 
 import (
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -14,16 +15,21 @@ type Service struct {
 	logger         *zerolog.Logger
 	cfg            configFile.Dependency
 	watcher        *fsnotify.Watcher
+	mux            sync.Mutex
 	activeWatchers map[string]FileHandlerFunc
 }
 
 func (s *Service) Init(rt runtime.Runtime) {
+	s.mux.Lock()
+
 	s.activeWatchers = make(map[string]func(string) error)
 
 	if err := s.initWatcher(); err != nil {
 		s.logger.Fatal().Msgf("initializing file watcher: %v", err)
 	}
 
+	s.mux.Unlock()
+
 	for _, service := range rt.Services() {
 		// try to bind existing services
 		go s.setupServiceToWatchFiles(service)
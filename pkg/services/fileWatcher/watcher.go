@@ -1,6 +1,7 @@
 package fileWatcher
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -43,7 +44,7 @@ func (s *Service) initWatcher() error {
 
 				s.logger.Debug().Msgf("file watcher event: %#v", event)
 
-				f, ok := s.activeWatchers[event.Name]
+				f, ok := s.handlerOf(event.Name)
 				if !ok {
 					s.logger.Warn().Msgf("unable to locate registered watcher for path %q", event.Name)
 					continue
@@ -65,7 +66,8 @@ func (s *Service) initWatcher() error {
 }
 
 // AddWatcher watches the given file for changes and invokes f with the file
-// path when a change is detected.
+// path when a change is detected. When the path is a directory, f is invoked
+// with the path of every file in it which changes.
 func (s *Service) AddWatcher(path string, f func(path string) error) {
 	s.logger.Debug().Msgf("adding watcher for %q", path)
 
@@ -74,7 +76,24 @@ func (s *Service) AddWatcher(path string, f func(path string) error) {
 		return
 	}
 
+	s.mux.Lock()
 	s.activeWatchers[path] = f
+	s.mux.Unlock()
+}
+
+// handlerOf yields the handler of a watched file, or of the watched
+// directory the file is in
+func (s *Service) handlerOf(path string) (FileHandlerFunc, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if f, ok := s.activeWatchers[path]; ok {
+		return f, true
+	}
+
+	f, ok := s.activeWatchers[filepath.Dir(path)]
+
+	return f, ok
 }
 
 // WatchAndLoad watches the given file for changes and invokes f with the file
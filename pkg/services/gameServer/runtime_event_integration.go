@@ -0,0 +1,32 @@
+package gameServer
+
+import (
+	"github.com/gravestench/runtime"
+)
+
+func (s *Service) OnServiceAdded(args ...any) {
+	if len(args) < 1 {
+		return
+	}
+
+	if candidate, ok := args[0].(runtime.Service); ok {
+		s.tryToBindCardBehaviors(candidate)
+	}
+}
+
+func (s *Service) tryToBindCardBehaviors(service runtime.Service) {
+	candidate, ok := service.(ProvidesCardBehaviors)
+	if !ok {
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.behaviors != nil {
+		return
+	}
+
+	s.behaviors = candidate
+	s.logger.Info().Msgf("new tables will use card behaviors from %q", service.Name())
+}
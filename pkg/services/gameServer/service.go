@@ -0,0 +1,114 @@
+package gameServer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/game"
+)
+
+type tableLookup = map[string]*game.Table
+
+type Service struct {
+	logger    *zerolog.Logger
+	mux       sync.Mutex
+	tables    tableLookup
+	behaviors game.Behaviors
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	s.mux.Lock()
+	if s.tables == nil {
+		s.tables = make(tableLookup)
+	}
+	s.mux.Unlock()
+
+	if rt == nil {
+		return
+	}
+
+	for _, service := range rt.Services() {
+		// try to bind existing services
+		s.tryToBindCardBehaviors(service)
+		// there is a runtime event handler that does this in runtime_event_integration.go
+	}
+}
+
+func (s *Service) Name() string {
+	return "Game Server"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// NewTable creates a new table with a random seed
+func (s *Service) NewTable() *game.Table {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.tables == nil {
+		s.tables = make(tableLookup)
+	}
+
+	table := game.NewTable(uuid.NewString(), time.Now().UnixNano(), s.behaviors)
+	s.tables[table.ID] = table
+
+	s.logger.Info().Msgf("created table %s", table.ID)
+
+	return table
+}
+
+// Table yields the table with the given id
+func (s *Service) Table(id string) (*game.Table, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	table, found := s.tables[id]
+	if !found {
+		return nil, fmt.Errorf("table %q not found", id)
+	}
+
+	return table, nil
+}
+
+// Tables yields all tables, sorted by id
+func (s *Service) Tables() []*game.Table {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ids := make([]string, 0, len(s.tables))
+	for id := range s.tables {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	tables := make([]*game.Table, 0, len(ids))
+	for _, id := range ids {
+		tables = append(tables, s.tables[id])
+	}
+
+	return tables
+}
+
+// RemoveTable closes a table, connected clients are not notified
+func (s *Service) RemoveTable(id string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.tables, id)
+}
@@ -0,0 +1,43 @@
+package gameServer
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/game"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.EventHandlerServiceAdded = &Service{} // implement in`runtime_event_integration.go`
+	_ webRouter.IsRouteInitializer     = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug           = &Service{} // implement in`web_router_integration.go`
+	_ IsGameServer                     = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = IsGameServer
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type IsGameServer interface {
+	NewTable() *game.Table
+	Table(id string) (*game.Table, error)
+	Tables() []*game.Table
+	RemoveTable(id string)
+}
+
+// ProvidesCardBehaviors is an integration interface other services can
+// implement to give cards their abilities in every table created afterwards.
+type ProvidesCardBehaviors interface {
+	game.Behaviors
+}
@@ -0,0 +1,168 @@
+package gameServer
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/gravestench/mtg/pkg/game"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// without CheckOrigin, browsers may only connect from pages served by
+	// the web server itself. Desktop clients do not send an origin.
+}
+
+func (s *Service) Slug() string {
+	return "game"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("tables", s.handleListTables)
+	group.POST("tables", s.handleCreateTable)
+	group.GET("tables/:table", s.handleGetTable)
+	group.GET("tables/:table/ws", s.handleConnect)
+}
+
+type tableInfo struct {
+	ID      string
+	Players []string
+	Started bool
+}
+
+func newTableInfo(table *game.Table) tableInfo {
+	return tableInfo{
+		ID:      table.ID,
+		Players: table.Players(),
+		Started: table.Started(),
+	}
+}
+
+func (s *Service) handleListTables(c *gin.Context) {
+	tables := make([]tableInfo, 0)
+
+	for _, table := range s.Tables() {
+		tables = append(tables, newTableInfo(table))
+	}
+
+	c.JSON(http.StatusOK, tables)
+}
+
+func (s *Service) handleCreateTable(c *gin.Context) {
+	c.JSON(http.StatusCreated, newTableInfo(s.NewTable()))
+}
+
+func (s *Service) handleGetTable(c *gin.Context) {
+	table, err := s.Table(c.Param("table"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, newTableInfo(table))
+}
+
+// handleConnect upgrades the request to a websocket. The `player` query
+// parameter names the client, a client reconnecting also passes the `token`
+// it received in its first update and `since`, the sequence number of the
+// last update it applied.
+//
+// Clients send game.Action messages and receive game.Update messages. A
+// client which can not join is sent an update with just the error, and the
+// connection is closed.
+func (s *Service) handleConnect(c *gin.Context) {
+	table, err := s.Table(c.Param("table"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, err.Error())
+		return
+	}
+
+	// upgrade before joining, a name is taken for good once it joined and a
+	// client which never got its token could not use it again
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		s.logger.Error().Msgf("upgrading connection to table %s: %v", table.ID, err)
+		return
+	}
+
+	name, token := c.Query("player"), c.Query("token")
+
+	var seat *game.Seat
+
+	if token == "" {
+		seat, err = table.Join(name)
+	} else {
+		since, _ := strconv.Atoi(c.Query("since"))
+		seat, err = table.Rejoin(name, token, since)
+	}
+
+	if err != nil {
+		_ = conn.WriteJSON(game.Update{Error: err.Error()})
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ""))
+		_ = conn.Close()
+
+		return
+	}
+
+	s.logger.Info().Msgf("%q connected to table %s", name, table.ID)
+
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+
+	go s.writeUpdates(conn, seat, errs, done)
+
+	defer func() {
+		close(done)
+		seat.Leave()
+		s.logger.Info().Msgf("%q disconnected from table %s", name, table.ID)
+	}()
+
+	for {
+		var action game.Action
+
+		if err = conn.ReadJSON(&action); err != nil {
+			return
+		}
+
+		if err = seat.Do(action); err != nil {
+			if errors.Is(err, game.ErrSeatClosed) {
+				return
+			}
+
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}
+}
+
+// writeUpdates is the only writer of the connection, it forwards the seat
+// updates and action errors until the seat is closed.
+func (s *Service) writeUpdates(conn *websocket.Conn, seat *game.Seat, errs <-chan error, done <-chan struct{}) {
+	defer func() { _ = conn.Close() }()
+
+	for {
+		select {
+		case update, ok := <-seat.Updates():
+			if !ok {
+				return
+			}
+
+			if err := conn.WriteJSON(update); err != nil {
+				return
+			}
+		case err := <-errs:
+			if err := conn.WriteJSON(game.Update{Error: err.Error()}); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package gameServer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/game"
+)
+
+func newTestServer(t *testing.T) (*Service, *httptest.Server) {
+	logger := zerolog.Nop()
+
+	s := &Service{}
+	s.BindLogger(&logger)
+	s.Init(nil)
+
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	s.InitRoutes(engine.Group(s.Slug()))
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	return s, server
+}
+
+func dial(t *testing.T, server *httptest.Server, table, query string) *websocket.Conn {
+	url := fmt.Sprintf("ws%s/game/tables/%s/ws?%s", strings.TrimPrefix(server.URL, "http"), table, query)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dialing %s: %v", url, err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) game.Update {
+	var update game.Update
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err := conn.ReadJSON(&update); err != nil {
+		t.Fatalf("reading update: %v", err)
+	}
+
+	return update
+}
+
+func TestWebsocketPlayersOnlySeeTheirOwnHand(t *testing.T) {
+	s, server := newTestServer(t)
+	table := s.NewTable()
+
+	deck := make([]string, 40)
+	for idx := range deck {
+		deck[idx] = "Island"
+	}
+
+	alice := dial(t, server, table.ID, "player=alice")
+	bob := dial(t, server, table.ID, "player=bob")
+
+	views := make(map[*websocket.Conn]game.View)
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		hello := read(t, conn)
+		if hello.Token == "" || hello.Snapshot == nil {
+			t.Fatalf("expected a token and a snapshot, got %+v", hello)
+		}
+
+		views[conn] = *hello.Snapshot
+	}
+
+	actions := []struct {
+		conn   *websocket.Conn
+		action game.Action
+	}{
+		{alice, game.Action{Kind: game.ActionJoin, Deck: deck}},
+		{bob, game.Action{Kind: game.ActionJoin, Deck: deck}},
+		{alice, game.Action{Kind: game.ActionStart}},
+	}
+
+	for _, step := range actions {
+		if err := step.conn.WriteJSON(step.action); err != nil {
+			t.Fatalf("writing action: %v", err)
+		}
+
+		for _, conn := range []*websocket.Conn{alice, bob} {
+			update := read(t, conn)
+			if update.Error != "" {
+				t.Fatalf("unexpected error: %v", update.Error)
+			}
+
+			view, err := game.Merge(views[conn], update.Patch)
+			if err != nil {
+				t.Fatalf("merging: %v", err)
+			}
+
+			views[conn] = view
+		}
+	}
+
+	if len(views[alice].Players["alice"].Hand) != 7 || len(views[alice].Players["bob"].Hand) != 0 {
+		t.Fatalf("unexpected hands in alice's view: %+v", views[alice].Players)
+	}
+
+	if len(views[bob].Players["bob"].Hand) != 7 || len(views[bob].Players["alice"].Hand) != 0 {
+		t.Fatalf("unexpected hands in bob's view: %+v", views[bob].Players)
+	}
+
+	// bob is not the active player, so this is rejected
+	if err := bob.WriteJSON(game.Action{Kind: game.ActionPass}); err != nil {
+		t.Fatalf("writing action: %v", err)
+	}
+
+	if update := read(t, bob); update.Error == "" {
+		t.Fatalf("expected an error update, got %+v", update)
+	}
+}
+
+func TestWebsocketRejectsBadToken(t *testing.T) {
+	s, server := newTestServer(t)
+	table := s.NewTable()
+
+	_ = dial(t, server, table.ID, "player=alice")
+
+	conn := dial(t, server, table.ID, "player=alice&token=nope")
+	if update := read(t, conn); update.Error != game.ErrInvalidToken.Error() {
+		t.Fatalf("expected %q, got %+v", game.ErrInvalidToken, update)
+	}
+
+	var update game.Update
+	if err := conn.ReadJSON(&update); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Fatalf("expected the connection to be closed, got %v", err)
+	}
+}
+
+func TestWebsocketRejectsForeignOrigin(t *testing.T) {
+	s, server := newTestServer(t)
+	table := s.NewTable()
+
+	url := fmt.Sprintf("ws%s/game/tables/%s/ws?player=alice", strings.TrimPrefix(server.URL, "http"), table.ID)
+
+	header := http.Header{"Origin": []string{"http://example.com"}}
+	if _, _, err := websocket.DefaultDialer.Dial(url, header); err == nil {
+		t.Fatal("expected dialing from another origin to fail")
+	}
+
+	// the failed connection did not take the name
+	header = http.Header{"Origin": []string{server.URL}}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		t.Fatalf("expected dialing from the same origin to work: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	if hello := read(t, conn); hello.Token == "" {
+		t.Fatalf("expected a token, got %+v", hello)
+	}
+}
@@ -1,6 +1,8 @@
 package lua
 
 import (
+	"time"
+
 	"github.com/gravestench/runtime"
 )
 
@@ -22,17 +24,27 @@ func (s *Service) tryToExportToLuaEnvironment(args ...any) {
 		return
 	}
 
-	if candidate, ok := service.(runtime.HasDependencies); ok {
-		if !candidate.DependenciesResolved() {
-			return
-		}
-	}
-
 	if _, exists := s.boundServices[service.Name()]; exists {
 		return
 	}
 
-	go luaUser.ExportToLua(s.state)
 	s.boundServices[service.Name()] = service
+
+	go s.exportToLuaEnvironment(service, luaUser)
+}
+
+// exportToLuaEnvironment waits for the service to resolve its dependencies,
+// and then exports it while holding the lock of the lua state machine
+func (s *Service) exportToLuaEnvironment(service runtime.Service, luaUser UsesLuaEnvironment) {
+	if dependent, ok := service.(runtime.HasDependencies); ok {
+		for !dependent.DependenciesResolved() {
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	luaUser.ExportToLua(s.state)
 	s.logger.Info().Msgf("successfully exported %q to lua", service.Name())
 }
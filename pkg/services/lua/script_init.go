@@ -5,6 +5,9 @@ import (
 )
 
 func (s *Service) runScript(script string) error {
+	s.Lock()
+	defer s.Unlock()
+
 	if err := s.state.DoFile(script); err != nil {
 		return fmt.Errorf("executing init script %q: %v", script, err)
 	}
@@ -19,6 +19,10 @@ type Service struct {
 	events        *ee.EventEmitter
 	mux           sync.Mutex
 	boundServices map[string]any
+
+	// the lua state machine is not safe for concurrent use, everything
+	// which calls into it holds this lock, see Lock
+	stateMux sync.Mutex
 }
 
 func (s *Service) Init(rt runtime.R) {
@@ -29,15 +33,7 @@ func (s *Service) Init(rt runtime.R) {
 	rt.Events().On(events.EventServiceAdded, s.tryToExportToLuaEnvironment)
 
 	for _, service := range rt.Services() {
-		if candidate, ok := service.(runtime.HasDependencies); ok {
-			if !candidate.DependenciesResolved() {
-				continue
-			}
-		}
-
-		if candidate, ok := service.(UsesLuaEnvironment); ok {
-			s.tryToExportToLuaEnvironment(candidate)
-		}
+		s.tryToExportToLuaEnvironment(service)
 	}
 
 	// wait for all siblings to be ready before we launch scripts
@@ -76,3 +72,15 @@ func (s *Service) BindLogger(logger *zerolog.Logger) {
 func (s *Service) Logger() *zerolog.Logger {
 	return s.logger
 }
+
+// Lock satisfies sync.Locker. The lock is held while scripts run and while
+// services export to the lua state machine, other services which call into
+// the state machine must hold it too.
+func (s *Service) Lock() {
+	s.stateMux.Lock()
+}
+
+// Unlock satisfies sync.Locker
+func (s *Service) Unlock() {
+	s.stateMux.Unlock()
+}
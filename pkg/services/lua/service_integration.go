@@ -1,6 +1,8 @@
 package lua
 
 import (
+	"sync"
+
 	"github.com/gravestench/runtime"
 	"github.com/yuin/gopher-lua"
 
@@ -16,10 +18,15 @@ var (
 
 type Dependency = ManagesLuaEnvironment
 
+// ManagesLuaEnvironment is locked by anything which calls into the lua state
+// machine, which is not safe for concurrent use
 type ManagesLuaEnvironment interface {
+	sync.Locker
 	LuaState() *lua.LState
 }
 
+// UsesLuaEnvironment is implemented by services which export to the lua
+// state machine. ExportToLua is called with the lock of the lua service held.
 type UsesLuaEnvironment interface {
 	ExportToLua(state *lua.LState)
 	UnexportFromLua(state *lua.LState)
@@ -0,0 +1,77 @@
+package mtgapi
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/catalog"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeyCatalog          = "Catalog"
+	groupKeyRefreshIntervals = "Refresh Intervals"
+	keyDirectory             = "directory"
+	keyMTGApiURL             = "mtg api url"
+	keyScryfallURL           = "scryfall url"
+	keyProxy                 = "proxy"
+)
+
+// defaultRefreshIntervals are how long the catalogs are used before they are
+// fetched again, new sets are released far more often than new card types
+var defaultRefreshIntervals = map[string]time.Duration{
+	catalog.Types:      30 * 24 * time.Hour,
+	catalog.Subtypes:   7 * 24 * time.Hour,
+	catalog.Supertypes: 30 * 24 * time.Hour,
+	catalog.Formats:    7 * 24 * time.Hour,
+	catalog.Sets:       24 * time.Hour,
+	catalog.Keywords:   7 * 24 * time.Hour,
+}
+
+func (s *Service) ConfigFileName() string {
+	return "catalog.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	g := cfg.Group(groupKeyCatalog)
+
+	g.Set(keyDirectory, "catalog")
+	g.Set(keyMTGApiURL, defaultMTGApiURL)
+	g.Set(keyScryfallURL, defaultScryfallURL)
+	g.Set(keyProxy, "")
+
+	intervals := cfg.Group(groupKeyRefreshIntervals)
+	for name, interval := range defaultRefreshIntervals {
+		intervals.Set(name, interval.String())
+	}
+
+	return
+}
+
+// cacheDirectory yields the absolute path of the catalog cache, relative
+// paths are relative to the config file directory
+func (s *Service) cacheDirectory() string {
+	path := s.cfg.Group(groupKeyCatalog).GetString(keyDirectory)
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return s.cfgManager.GetFilePath(path)
+}
+
+// refreshInterval yields how long a catalog is used before it is fetched
+// again, like "24h"
+func (s *Service) refreshInterval(name string) time.Duration {
+	value := s.cfg.Group(groupKeyRefreshIntervals).GetString(name)
+	if value == "" {
+		return defaultRefreshIntervals[name]
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		s.logger.Warn().Msgf("refresh interval of %s: %v", name, err)
+		return defaultRefreshIntervals[name]
+	}
+
+	return interval
+}
@@ -0,0 +1,85 @@
+package mtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/catalog"
+	"github.com/gravestench/mtg/pkg/httplimit"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+const (
+	defaultMTGApiURL   = "https://api.magicthegathering.io/v1/"
+	defaultScryfallURL = "https://api.scryfall.com/"
+
+	// scryfall asks for 50 to 100 milliseconds between requests
+	requestsPerSecond = 10
+)
+
+// newHTTPClient creates the client of every request for the catalogs
+func (s *Service) newHTTPClient() *http.Client {
+	proxy := s.cfg.Group(groupKeyCatalog).GetString(keyProxy)
+
+	client, err := httplimit.NewClient(s.Transport, proxy, requestsPerSecond)
+	if err != nil {
+		s.logger.Warn().Msgf("%v, connecting directly", err)
+	}
+
+	return client
+}
+
+// fetch fetches a catalog. The types come from the mtg api, which does not
+// know about keywords and lags behind with sets, those come from scryfall.
+func (s *Service) fetch(ctx context.Context, name string) ([]string, error) {
+	mtgAPI := s.url(keyMTGApiURL, defaultMTGApiURL)
+	scryfall := s.url(keyScryfallURL, defaultScryfallURL)
+
+	switch name {
+	case catalog.Types, catalog.Subtypes, catalog.Supertypes, catalog.Formats:
+		// like {"subtypes": ["Abian", ...]}
+		var response map[string][]string
+		if err := httplimit.GetJSON(ctx, s.http, mtgAPI+name, &response); err != nil {
+			return nil, err
+		}
+
+		values, found := response[name]
+		if !found {
+			return nil, fmt.Errorf("the mtg api response has no %s", name)
+		}
+
+		return values, nil
+	case catalog.Sets:
+		// the same sets as the set info service
+		sets, err := setinfo.FetchSets(ctx, s.http, scryfall)
+		if err != nil {
+			return nil, err
+		}
+
+		codes := make([]string, 0, len(sets))
+		for _, set := range sets {
+			codes = append(codes, strings.ToUpper(set.Code))
+		}
+
+		return codes, nil
+	case catalog.Keywords:
+		var response struct {
+			Data []string `json:"data"`
+		}
+
+		if err := httplimit.GetJSON(ctx, s.http, scryfall+"catalog/keyword-abilities", &response); err != nil {
+			return nil, err
+		}
+
+		return response.Data, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", catalog.ErrUnknownCatalog, name)
+}
+
+// url yields a configured base url, which always ends with a slash
+func (s *Service) url(key, fallback string) string {
+	return httplimit.BaseURL(s.cfg.Group(groupKeyCatalog).GetString(key), fallback)
+}
@@ -1,60 +1,70 @@
 package mtgapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
-	"github.com/BlueMonday/go-scryfall"
 	"github.com/gravestench/runtime"
 	"github.com/rs/zerolog"
 
-	"github.com/MagicTheGathering/mtg-sdk-go"
-
+	"github.com/gravestench/mtg/pkg/catalog"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
+// retryAfter is how long old catalogs are used after they could not be
+// fetched, before fetching them is tried again
+const retryAfter = time.Minute
+
+// Service provides the catalogs of the card types, subtypes, supertypes,
+// formats, sets and keywords. Catalogs are cached on disk and fetched again
+// after their refresh interval. When a catalog can not be fetched, the
+// cached one is used even if it is old, and the snapshot built into the
+// program when it was never cached.
 type Service struct {
-	client     *scryfall.Client
+	// Transport sends every request for the catalogs, like a fake server
+	// in tests. When nil, a transport is created from the config file.
+	Transport http.RoundTripper
+
+	http       *http.Client
 	logger     *zerolog.Logger
 	cfgManager configFile.Dependency
 	cfg        *configFile.Config
-}
+	cache      *catalog.Cache
 
-func (s *Service) DependenciesResolved() bool {
-	if s.cfgManager == nil {
-		return false
-	}
+	mux   sync.Mutex
+	lists map[string]catalog.List
 
-	return true
-}
+	// when the catalogs last failed to be fetched, and which are being
+	// fetched, so that other calls use the old ones meanwhile
+	failed   map[string]time.Time
+	fetching map[string]bool
 
-func (s *Service) ResolveDependencies(r runtime.R) {
-	for _, service := range r.Services() {
-		if candidate, ok := service.(configFile.Dependency); ok {
-			s.cfgManager = candidate
-		}
-	}
+	// the catalog of the lists, built again when the lists change
+	catalog      *catalog.Catalog
+	listsChanged bool
 }
 
 func (s *Service) Init(rt runtime.Runtime) {
-	client, err := scryfall.NewClient()
-	if err != nil {
-		s.logger.Fatal().Msgf("could not open scryfall client: %v", err)
-	}
-
-	s.client = client
-
 	cfg, err := s.cfgManager.GetConfigByFileName(s.ConfigFileName())
 	if err != nil {
 		s.logger.Fatal().Msgf("loading config file: %v", err)
 	}
 
 	s.cfg = cfg
+	s.http = s.newHTTPClient()
+	s.cache = catalog.NewCache(s.cacheDirectory())
+	s.lists = make(map[string]catalog.List)
+	s.failed = make(map[string]time.Time)
+	s.fetching = make(map[string]bool)
 }
 
 func (s *Service) Name() string {
-	return "Scryfall"
+	return "Catalog"
 }
 
 func (s *Service) BindLogger(logger *zerolog.Logger) {
@@ -65,43 +75,188 @@ func (s *Service) Logger() *zerolog.Logger {
 	return s.logger
 }
 
-func (s *Service) ConfigFileName() string {
-	return "scryfall.json"
+func (s *Service) GetTypes() ([]string, error) {
+	return s.list(catalog.Types)
 }
 
-func (s *Service) DefaultConfig() (cfg configFile.Config) {
-	cfg.Group("scryfall").Set("directory", "/tmp")
+func (s *Service) GetSubTypes() ([]string, error) {
+	return s.list(catalog.Subtypes)
+}
 
-	return
+func (s *Service) GetFormats() ([]string, error) {
+	return s.list(catalog.Formats)
 }
 
-func (s *Service) GetTypes() ([]string, error) {
-	return mtg.GetTypes()
+func (s *Service) GetSuperTypes() ([]string, error) {
+	return s.list(catalog.Supertypes)
 }
 
-func (s *Service) GetSubTypes() ([]string, error) {
-	return mtg.GetSubTypes()
+// GetSets yields the codes of every set, in upper case like "M10"
+func (s *Service) GetSets() ([]string, error) {
+	return s.list(catalog.Sets)
 }
 
-func (s *Service) GetFormats() ([]string, error) {
-	return mtg.GetFormats()
+// GetKeywords yields the keyword abilities, like "Flying"
+func (s *Service) GetKeywords() ([]string, error) {
+	return s.list(catalog.Keywords)
 }
 
-func (s *Service) GetSuperTypes() ([]string, error) {
-	return mtg.GetSuperTypes()
+// Catalog yields every catalog, for looking up and validating values
+func (s *Service) Catalog() *catalog.Catalog {
+	lists := make(map[string][]string)
+
+	for _, name := range catalog.Names {
+		// the catalogs always have values, from the snapshot if need be
+		lists[name], _ = s.list(name)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.catalog == nil || s.listsChanged {
+		s.catalog = catalog.New(lists)
+		s.listsChanged = false
+	}
+
+	return s.catalog
 }
 
-func download(uri string) ([]byte, error) {
-	res, err := http.Get(uri)
-	if err != nil {
-		return nil, fmt.Errorf("issuing http request: %v", err)
+// ValidateSubtypes yields a catalog.ErrUnknownSubtype naming every subtype
+// which is not in the catalog, for card.CardBuilder.BuildValidated
+func (s *Service) ValidateSubtypes(subtypes ...string) error {
+	return s.Catalog().ValidateSubtypes(subtypes...)
+}
+
+// ParseTypeLine parses the type line of a card, rejecting unknown types and
+// subtypes
+func (s *Service) ParseTypeLine(line string) (catalog.TypeLine, error) {
+	return s.Catalog().ParseTypeLine(line)
+}
+
+// Refresh fetches catalogs regardless of their refresh intervals, every
+// catalog when none are named. The catalogs which can not be fetched are
+// kept as they were.
+func (s *Service) Refresh(names ...string) error {
+	if len(names) == 0 {
+		names = catalog.Names
+	}
+
+	var errs []error
+
+	for _, name := range names {
+		if _, err := s.refresh(name); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	d, err := io.ReadAll(res.Body)
+	return errors.Join(errs...)
+}
+
+// list yields the values of a catalog, fetching it when it is stale. A
+// catalog which could not be fetched is not fetched again before retryAfter,
+// and a catalog which is being fetched is not waited for.
+func (s *Service) list(name string) ([]string, error) {
+	if _, known := defaultRefreshIntervals[name]; !known {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrUnknownCatalog, name)
+	}
+
+	list, fetch := s.current(name)
+	if !fetch {
+		return list.Values, nil
+	}
+
+	fetched, err := s.refresh(name)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delete(s.fetching, name)
+
+	if err == nil {
+		return fetched.Values, nil
+	}
+
+	s.failed[name] = time.Now()
+
+	// another list may have been fetched meanwhile, by Refresh
+	list, found := s.lists[name]
+
+	switch {
+	case !found:
+		// the snapshot is kept until the catalog is fetched
+		list = catalog.List{Values: catalog.Snapshot().List(name)}
+		s.lists[name], s.listsChanged = list, true
+
+		s.logger.Warn().Msgf("%v, using the built-in %s", err, name)
+	case list.Fetched.IsZero():
+		s.logger.Warn().Msgf("%v, using the built-in %s", err, name)
+	default:
+		s.logger.Warn().Msgf("%v, using the %s fetched %s", err, name, list.Fetched.Format(time.DateOnly))
+	}
+
+	return list.Values, nil
+}
+
+// current yields the list of a catalog which is there, loading it from the
+// cache the first time, and whether it has to be fetched. When it has to be
+// fetched, it is marked as being fetched.
+func (s *Service) current(name string) (list catalog.List, fetch bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	list, found := s.lists[name]
+	if !found {
+		cached, err := s.cache.Load(name)
+
+		switch {
+		case err == nil:
+			list, found = cached, true
+			s.lists[name], s.listsChanged = cached, true
+		case !errors.Is(err, os.ErrNotExist):
+			s.logger.Warn().Msgf("%v", err)
+		}
+	}
+
+	now := time.Now()
+
+	switch {
+	case found && !list.Stale(s.refreshInterval(name), now):
+		return list, false
+	case !s.fetching[name] && now.Sub(s.failed[name]) >= retryAfter:
+		s.fetching[name] = true
+		return list, true
+	case !found:
+		// the first fetch of the catalog is still going on
+		return catalog.List{Values: catalog.Snapshot().List(name)}, false
+	}
+
+	return list, false
+}
+
+// refresh fetches a catalog and caches it, the lock must not be held as it
+// is only taken once the catalog is fetched
+func (s *Service) refresh(name string) (catalog.List, error) {
+	values, err := s.fetch(context.Background(), name)
 	if err != nil {
-		return nil, fmt.Errorf("reading response body: %v", err)
+		return catalog.List{}, fmt.Errorf("fetching %s: %w", name, err)
+	}
+
+	if len(values) == 0 {
+		return catalog.List{}, fmt.Errorf("fetching %s: the catalog is empty", name)
 	}
 
-	return d, err
+	list := catalog.List{Fetched: time.Now().UTC(), Values: values}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.lists[name], s.listsChanged = list, true
+
+	if err = s.cache.Save(name, list); err != nil {
+		s.logger.Warn().Msgf("%v", err)
+	}
+
+	s.logger.Info().Msgf("fetched %d %s", len(values), name)
+
+	return list, nil
 }
@@ -3,9 +3,21 @@ package mtgapi
 import (
 	"github.com/gravestench/runtime"
 
+	"github.com/gravestench/mtg/pkg/catalog"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service             = &Service{} // implement in`service.go`
+	_ runtime.HasLogger           = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies     = &Service{} // implement in`runtime_dependencies.go`
+	_ configFile.HasDefaultConfig = &Service{} // implement in`config_file_integration.go`
+	_ MTGApiClient                = &Service{} // implement in`service.go`
+)
+
 type Dependency = MTGApiClient
 
 type MTGApiClient interface {
@@ -17,4 +29,10 @@ type MTGApiClient interface {
 	GetSubTypes() ([]string, error)
 	GetFormats() ([]string, error)
 	GetSuperTypes() ([]string, error)
+	GetSets() ([]string, error)
+	GetKeywords() ([]string, error)
+	Catalog() *catalog.Catalog
+	Refresh(names ...string) error
+	ValidateSubtypes(subtypes ...string) error
+	ParseTypeLine(line string) (catalog.TypeLine, error)
 }
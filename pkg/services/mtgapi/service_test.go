@@ -0,0 +1,191 @@
+package mtgapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/catalog"
+	"github.com/gravestench/mtg/pkg/fakeapi"
+	"github.com/gravestench/mtg/pkg/httplimit"
+	"github.com/gravestench/mtg/pkg/internal/servicetest"
+)
+
+// newTestService creates a service which fetches the catalogs from a fake
+// server, and caches them in a temporary directory
+func newTestService(t *testing.T, baseURL, dir string) *Service {
+	s := &Service{}
+	s.cfg = servicetest.Configure(s, groupKeyCatalog, map[string]any{
+		keyDirectory:   dir,
+		keyMTGApiURL:   baseURL + "/v1",
+		keyScryfallURL: baseURL,
+	})
+	s.http = s.newHTTPClient()
+	s.cache = catalog.NewCache(s.cacheDirectory())
+	s.lists = make(map[string]catalog.List)
+	s.failed = make(map[string]time.Time)
+	s.fetching = make(map[string]bool)
+
+	return s
+}
+
+func TestCatalogs(t *testing.T) {
+	server, err := fakeapi.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	dir := t.TempDir()
+	s := newTestService(t, server.URL, dir)
+
+	sets, err := s.GetSets()
+	if err != nil || len(sets) == 0 || sets[0] != "MKM" {
+		t.Fatalf("unexpected sets %v (%v)", sets, err)
+	}
+
+	if keywords, _ := s.GetKeywords(); len(keywords) != 4 {
+		t.Fatalf("expected 4 keywords, got %v", keywords)
+	}
+
+	if err = s.ValidateSubtypes("Goblin", "Shaman"); !errors.Is(err, catalog.ErrUnknownSubtype) {
+		t.Fatalf("expected shamans to be unknown to the fake api, got %v", err)
+	}
+
+	typeLine, err := s.ParseTypeLine("Legendary Creature — Goblin Warrior")
+	if err != nil || typeLine.String() != "Legendary Creature — Goblin Warrior" {
+		t.Fatalf("unexpected type line %v (%v)", typeLine, err)
+	}
+
+	for _, name := range catalog.Names {
+		if _, err = os.Stat(filepath.Join(dir, name+".json")); err != nil {
+			t.Fatalf("expected the %s to be cached: %v", name, err)
+		}
+	}
+
+	// fresh catalogs are not fetched again
+	requests := len(server.Requests())
+
+	if _, err = newTestService(t, server.URL, dir).GetSubTypes(); err != nil || len(server.Requests()) != requests {
+		t.Fatalf("expected the cached subtypes, got %d requests (%v)", len(server.Requests())-requests, err)
+	}
+
+	// stale catalogs are used when they can not be fetched again
+	offline := newTestService(t, "http://127.0.0.1:1", dir)
+	offline.cfg.Group(groupKeyRefreshIntervals).Set(catalog.Subtypes, "1ns")
+
+	time.Sleep(time.Millisecond)
+
+	if subtypes, _ := offline.GetSubTypes(); len(subtypes) != 11 {
+		t.Fatalf("expected the stale subtypes, got %v", subtypes)
+	}
+
+	if err = offline.Refresh(catalog.Subtypes); err == nil {
+		t.Fatal("expected refreshing to fail without the api")
+	}
+
+	// without a cache, the built-in snapshot is used
+	empty := newTestService(t, "http://127.0.0.1:1", t.TempDir())
+
+	if err = empty.ValidateSubtypes("Goblin", "Shaman"); err != nil {
+		t.Fatalf("expected the snapshot to know shamans, got %v", err)
+	}
+
+	if _, err = empty.GetSubTypes(); err != nil {
+		t.Fatalf("expected the snapshot, got %v", err)
+	}
+
+	if _, err = s.list("colors"); !errors.Is(err, catalog.ErrUnknownCatalog) {
+		t.Fatalf("expected an unknown catalog, got %v", err)
+	}
+}
+
+// TestUnreachableCatalogs fetches the catalogs from a server which hangs
+// until it fails, like an api which is down
+func TestUnreachableCatalogs(t *testing.T) {
+	hang := make(chan struct{})
+	release := sync.OnceFunc(func() { close(hang) })
+	requests := make(chan string, 16)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r.URL.Path
+
+		if r.URL.Path == "/v1/subtypes" {
+			<-hang
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	defer release()
+
+	s := newTestService(t, server.URL, t.TempDir())
+
+	// the api is down for good, retrying its requests would only be slower
+	s.http.Transport.(*httplimit.Transport).Retries = 0
+
+	done := make(chan []string)
+
+	go func() {
+		subtypes, _ := s.GetSubTypes()
+		done <- subtypes
+	}()
+
+	<-requests
+
+	// the other catalogs do not wait for the subtypes, and the subtypes which
+	// are being fetched are the snapshot meanwhile
+	if types := within(t, s.GetTypes); len(types) != len(catalog.Snapshot().List(catalog.Types)) {
+		t.Fatalf("expected the built-in types, got %v", types)
+	}
+
+	if subtypes := within(t, s.GetSubTypes); len(subtypes) != len(catalog.Snapshot().List(catalog.Subtypes)) {
+		t.Fatalf("expected the built-in subtypes while fetching, got %d", len(subtypes))
+	}
+
+	release()
+
+	if subtypes := <-done; len(subtypes) != len(catalog.Snapshot().List(catalog.Subtypes)) {
+		t.Fatalf("expected the built-in subtypes, got %d", len(subtypes))
+	}
+
+	// the catalogs which failed are not fetched again before retryAfter
+	for len(requests) > 0 {
+		<-requests
+	}
+
+	_ = s.Catalog()
+	_, _ = s.GetSubTypes()
+
+	if len(requests) != len(catalog.Names)-2 {
+		t.Fatalf("expected only the catalogs which were not fetched yet, got %d requests", len(requests))
+	}
+
+	if _, found := s.lists[catalog.Subtypes]; !found {
+		t.Fatal("expected the built-in subtypes to be kept")
+	}
+}
+
+// within yields the values of a catalog, failing when getting them blocks
+func within(t *testing.T, get func() ([]string, error)) []string {
+	values := make(chan []string, 1)
+
+	go func() {
+		v, _ := get()
+		values <- v
+	}()
+
+	select {
+	case v := <-values:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out getting a catalog")
+	}
+
+	return nil
+}
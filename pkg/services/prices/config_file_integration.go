@@ -0,0 +1,55 @@
+package prices
+
+import (
+	"path/filepath"
+
+	"github.com/gravestench/mtg/pkg/prices"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeyPrices     = "Prices"
+	keyCurrency        = "currency"
+	keyHistoryDir      = "history directory"
+	keyRecordHistory   = "record history"
+	defaultHistoryPath = "price_history"
+)
+
+func (s *Service) ConfigFileName() string {
+	return "prices.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	g := cfg.Group(groupKeyPrices)
+
+	g.Set(keyCurrency, string(prices.USD))
+	g.Set(keyHistoryDir, defaultHistoryPath)
+	g.Set(keyRecordHistory, true)
+
+	return
+}
+
+// loadConfig reads the currency and the price history directory, relative
+// directories are relative to the config file directory
+func (s *Service) loadConfig() error {
+	cfg, err := s.cfg.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		return err
+	}
+
+	g := cfg.Group(groupKeyPrices)
+
+	if s.currency, err = prices.ParseCurrency(g.GetString(keyCurrency)); err != nil {
+		return err
+	}
+
+	dir := g.GetString(keyHistoryDir)
+	if !filepath.IsAbs(dir) {
+		dir = s.cfg.GetFilePath(dir)
+	}
+
+	s.history = prices.NewHistory(dir)
+	s.record = g.GetBool(keyRecordHistory)
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package prices
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/prices"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	scryfallService "github.com/gravestench/mtg/pkg/services/scryfall"
+)
+
+// scryfall updates its prices once a day, looked up cards are remembered
+// for as long
+const cacheTTL = 24 * time.Hour
+
+type Service struct {
+	logger   *zerolog.Logger
+	cfg      configFile.Dependency
+	scryfall scryfallService.Dependency
+
+	currency prices.Currency
+	history  *prices.History
+	record   bool
+
+	mux       sync.Mutex
+	cachedAt  time.Time
+	cards     map[string]*scryfall.Card
+	printings map[string][]scryfall.Card
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	s.expireCache()
+
+	if err := s.loadConfig(); err != nil {
+		s.logger.Fatal().Msgf("loading config file: %v", err)
+	}
+}
+
+func (s *Service) Name() string {
+	return "Prices"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Currency yields the configured currency
+func (s *Service) Currency() prices.Currency {
+	return s.currency
+}
+
+// Value prices every card of a deck, the prices are recorded in the price
+// history
+func (s *Service) Value(deck *decklist.Deck) *prices.Valuation {
+	v := prices.Value(deck, s.currency, s.card)
+
+	if len(v.Unpriced) > 0 {
+		s.logger.Warn().Msgf("cards without a price in %s: %v", s.currency, v.Unpriced)
+	}
+
+	return v
+}
+
+// Cheapest yields the cheapest printing of a card
+func (s *Service) Cheapest(name string, foil, etched bool) (*scryfall.Card, float64, error) {
+	card, err := s.card(decklist.Entry{Name: name})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	printings, err := s.getPrintings(card.OracleID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cheapest, price, found := prices.Cheapest(printings, s.currency, foil, etched)
+	if !found {
+		return nil, 0, fmt.Errorf("no printing of %q has a price in %s", name, s.currency)
+	}
+
+	return &cheapest, price, nil
+}
+
+// Budget yields a copy of a deck with the cheapest printing of every card
+func (s *Service) Budget(deck *decklist.Deck) (*decklist.Deck, []prices.Swap) {
+	return prices.Budget(deck, s.currency, s.card, s.getPrintings)
+}
+
+// Trend yields the recorded prices of a printing over time. Without a set,
+// the printing scryfall finds by name is used.
+func (s *Service) Trend(entry decklist.Entry) ([]prices.Point, error) {
+	card, err := s.card(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.history.Trend(card.ID, s.currency, entry.Foil, entry.Etched)
+}
+
+// card looks up the printing of a deck entry, and records its prices
+func (s *Service) card(entry decklist.Entry) (*scryfall.Card, error) {
+	key := strings.ToLower(strings.Join([]string{entry.Name, entry.Set, entry.CollectorNumber}, "|"))
+
+	s.mux.Lock()
+	s.expireCache()
+	cached, found := s.cards[key]
+	s.mux.Unlock()
+
+	if found {
+		return cached, nil
+	}
+
+	var (
+		card *scryfall.Card
+		err  error
+	)
+
+	if entry.Set != "" && entry.CollectorNumber != "" {
+		card, err = s.scryfall.GetCardByPrinting(entry.Set, entry.CollectorNumber)
+	} else {
+		card, err = s.scryfall.GetCardByName(entry.Name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.mux.Lock()
+	s.cards[key] = card
+	s.mux.Unlock()
+
+	s.recordPrices(*card)
+
+	return card, nil
+}
+
+// getPrintings yields every printing of a card, and records their prices
+func (s *Service) getPrintings(oracleID string) ([]scryfall.Card, error) {
+	s.mux.Lock()
+	s.expireCache()
+	printings, found := s.printings[oracleID]
+	s.mux.Unlock()
+
+	if found {
+		return printings, nil
+	}
+
+	printings, err := s.scryfall.GetPrintings(oracleID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mux.Lock()
+	s.printings[oracleID] = printings
+	s.mux.Unlock()
+
+	for _, card := range printings {
+		s.recordPrices(card)
+	}
+
+	return printings, nil
+}
+
+func (s *Service) recordPrices(card scryfall.Card) {
+	if !s.record {
+		return
+	}
+
+	if err := s.history.Record(card, time.Now()); err != nil {
+		s.logger.Warn().Msgf("recording prices of %q: %v", card.Name, err)
+	}
+}
+
+// expireCache forgets the looked up cards once their prices are outdated,
+// the lock must be held
+func (s *Service) expireCache() {
+	if s.cards != nil && time.Since(s.cachedAt) < cacheTTL {
+		return
+	}
+
+	s.cachedAt = time.Now()
+	s.cards = make(map[string]*scryfall.Card)
+	s.printings = make(map[string][]scryfall.Card)
+}
@@ -0,0 +1,42 @@
+package prices
+
+import (
+	"github.com/BlueMonday/go-scryfall"
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/prices"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service              = &Service{} // implement in`service.go`
+	_ runtime.HasLogger            = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies      = &Service{} // implement in`runtime_dependencies.go`
+	_ configFile.HasDefaultConfig  = &Service{} // implement in`config_file_integration.go`
+	_ webRouter.IsRouteInitializer = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug       = &Service{} // implement in`web_router_integration.go`
+	_ PricesCards                  = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = PricesCards
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type PricesCards interface {
+	Currency() prices.Currency
+	Value(deck *decklist.Deck) *prices.Valuation
+	Cheapest(name string, foil, etched bool) (*scryfall.Card, float64, error)
+	Budget(deck *decklist.Deck) (*decklist.Deck, []prices.Swap)
+	Trend(entry decklist.Entry) ([]prices.Point, error)
+}
@@ -0,0 +1,108 @@
+package prices
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+func (s *Service) Slug() string {
+	return "prices"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.POST("value", s.handleValue)
+	group.POST("budget", s.handleBudget)
+	group.GET("cheapest/:name", s.handleCheapest)
+	group.GET("trend/:name", s.handleTrend)
+}
+
+// handleValue prices the deck list in the request body
+func (s *Service) handleValue(c *gin.Context) {
+	deck, ok := s.readDeck(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, s.Value(deck))
+}
+
+// handleBudget yields the deck list in the request body with the cheapest
+// printing of every card, as an arena deck list, and the swapped printings
+func (s *Service) handleBudget(c *gin.Context) {
+	deck, ok := s.readDeck(c)
+	if !ok {
+		return
+	}
+
+	budget, swaps := s.Budget(deck)
+
+	list, err := decklist.Export(decklist.FormatArena, budget)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "exporting deck list: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deck": list, "swaps": swaps})
+}
+
+// handleCheapest yields the cheapest printing of a card, ?foil=true or
+// ?etched=true for other finishes
+func (s *Service) handleCheapest(c *gin.Context) {
+	foil, _ := strconv.ParseBool(c.Query("foil"))
+	etched, _ := strconv.ParseBool(c.Query("etched"))
+
+	card, price, err := s.Cheapest(c.Param("name"), foil, etched)
+	if err != nil {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":            card.Name,
+		"set":             card.Set,
+		"collectorNumber": card.CollectorNumber,
+		"currency":        s.Currency(),
+		"price":           price,
+	})
+}
+
+// handleTrend yields the recorded prices of a card, ?set=&number= select a
+// printing, ?foil=true or ?etched=true other finishes
+func (s *Service) handleTrend(c *gin.Context) {
+	entry := decklist.Entry{
+		Name:            c.Param("name"),
+		Set:             c.Query("set"),
+		CollectorNumber: c.Query("number"),
+	}
+
+	entry.Foil, _ = strconv.ParseBool(c.Query("foil"))
+	entry.Etched, _ = strconv.ParseBool(c.Query("etched"))
+
+	trend, err := s.Trend(entry)
+	if err != nil {
+		c.String(http.StatusNotFound, "%v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
+
+func (s *Service) readDeck(c *gin.Context) (*decklist.Deck, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, "reading deck list: %v", err)
+		return nil, false
+	}
+
+	deck, err := decklist.Parse(string(body))
+	if err != nil {
+		s.logger.Warn().Msgf("parsing deck list: %v", err)
+	}
+
+	return deck, true
+}
@@ -0,0 +1,19 @@
+package raylibRenderer
+
+import (
+	"image"
+
+	"github.com/gravestench/mtg/pkg/card"
+)
+
+// ComposeCard composes the image of a card from its template and artwork,
+// with the symbol of its set when the set info service is there
+func (s *Service) ComposeCard(c *card.Card) image.Image {
+	if sets := s.sets.Sets(); sets != nil {
+		if err := c.LoadSetSymbol(sets); err != nil {
+			s.logger.Warn().Msgf("drawing the set symbol of %q: %v", c.Name, err)
+		}
+	}
+
+	return c.CompositeCardImage()
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gravestench/mtg/pkg/cache"
 	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/setInfo"
 )
 
 type Service struct {
@@ -15,6 +16,7 @@ type Service struct {
 
 	cfg   configFile.Dependency
 	cache *cache.Cache
+	sets  setInfo.Binding
 
 	cameras map[string]*rl.Camera2D
 
@@ -28,6 +30,12 @@ func (s *Service) Init(rt runtime.Runtime) {
 	s.rootNode = s.NewRenderable()
 	s.rootNode.Disable() // dont render
 
+	for _, service := range rt.Services() {
+		// try to bind existing services
+		s.tryToBindSetInfo(service)
+		// there is a runtime event handler that does this in runtime_event_integration.go
+	}
+
 	go s.initRenderer()
 }
 
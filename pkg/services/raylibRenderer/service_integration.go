@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gravestench/runtime"
 
+	"github.com/gravestench/mtg/pkg/card"
 	"github.com/gravestench/mtg/pkg/services/cacheManager"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
@@ -15,12 +16,13 @@ import (
 // compile-time error if the service does not
 // implement them.
 var (
-	_ runtime.Service             = &Service{} // implement in`service.go`
-	_ runtime.HasLogger           = &Service{} // implement in`service.go`
-	_ runtime.HasDependencies     = &Service{} // implement in`dependencies.go`
-	_ configFile.HasDefaultConfig = &Service{} // implement in`lua_integration.go`
-	_ cacheManager.HasCache       = &Service{} // implement in`lua_integration.go`
-	_ IsRenderer                  = &Service{} // implement in`service.go`
+	_ runtime.Service                  = &Service{} // implement in`service.go`
+	_ runtime.HasLogger                = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies          = &Service{} // implement in`dependencies.go`
+	_ runtime.EventHandlerServiceAdded = &Service{} // implement in`runtime_event_integration.go`
+	_ configFile.HasDefaultConfig      = &Service{} // implement in`lua_integration.go`
+	_ cacheManager.HasCache            = &Service{} // implement in`lua_integration.go`
+	_ IsRenderer                       = &Service{} // implement in`service.go`
 )
 
 // this is an alias which can be used to make
@@ -39,6 +41,7 @@ type IsRenderer interface {
 	ManagesCameras
 	ProvidesTextures
 	ProvidesRenderables
+	ComposesCards
 }
 
 type ManagesWindow interface {
@@ -62,6 +65,10 @@ type ProvidesRenderables interface {
 	NewRenderable() Renderable
 }
 
+type ComposesCards interface {
+	ComposeCard(c *card.Card) image.Image
+}
+
 // Renderable is a thing that the renderer provides to other services, which
 // encapsulates the necessary behavior of something that can be rendered
 type Renderable interface {
@@ -0,0 +1,32 @@
+package scryfall
+
+import (
+	"github.com/gravestench/mtg/pkg/cache"
+)
+
+func (s *Service) CacheBudget() int {
+	const defaultBudget = 256 * mb
+
+	if s.cfgManager == nil {
+		return defaultBudget
+	}
+
+	cfg, err := s.cfgManager.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		return defaultBudget
+	}
+
+	budget := cfg.Group(groupKeyScryfall).GetInt(keyImageMemoryBudget)
+	if budget <= 0 {
+		return defaultBudget
+	}
+
+	return budget * mb
+}
+
+func (s *Service) FlushCache(newCache *cache.Cache) {
+	s.decodedMux.Lock()
+	defer s.decodedMux.Unlock()
+
+	s.decoded = newCache
+}
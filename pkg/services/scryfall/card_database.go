@@ -0,0 +1,168 @@
+package scryfall
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/carddb"
+	"github.com/gravestench/mtg/pkg/cardquery"
+)
+
+const (
+	groupKeyScryfall = "scryfall"
+	keyBulkData      = "bulk data"
+	keyCardDatabase  = "card database"
+	keyOffline       = "offline"
+)
+
+// errOffline is yielded by lookups which would need the network while the
+// service is offline
+var errOffline = errors.New("scryfall is offline, and the card is not in the card database")
+
+// openCardDatabase opens the local card database. When a bulk data file is
+// configured which is newer than the database, it is imported first.
+func (s *Service) openCardDatabase() error {
+	group := s.cfg.Group(groupKeyScryfall)
+
+	s.offline = group.GetBool(keyOffline)
+
+	dir := group.GetString(keyCardDatabase)
+	if dir == "" {
+		if s.offline {
+			return fmt.Errorf("offline without a card database")
+		}
+
+		return nil
+	}
+
+	dir = s.absolutePath(dir)
+
+	if bulk := group.GetString(keyBulkData); bulk != "" && s.isOutdated(dir, s.absolutePath(bulk)) {
+		return s.ImportBulkData(bulk)
+	}
+
+	if !carddb.Exists(dir) {
+		if s.offline {
+			return fmt.Errorf("offline without a card database, set the %q to a scryfall bulk data file", keyBulkData)
+		}
+
+		return nil
+	}
+
+	db, err := carddb.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	s.setCardDatabase(db)
+
+	return nil
+}
+
+// ImportBulkData imports a scryfall bulk data file into the card database,
+// which is used for every card lookup from then on
+func (s *Service) ImportBulkData(path string) error {
+	dir := s.cfg.Group(groupKeyScryfall).GetString(keyCardDatabase)
+	if dir == "" {
+		return fmt.Errorf("no %q directory configured", keyCardDatabase)
+	}
+
+	s.logger.Info().Msgf("importing scryfall bulk data %q", path)
+
+	db, err := carddb.ImportFile(s.absolutePath(dir), s.absolutePath(path))
+	if err != nil {
+		return fmt.Errorf("importing bulk data: %v", err)
+	}
+
+	s.logger.Info().Msgf("imported %d cards", db.Len())
+	s.setCardDatabase(db)
+
+	return nil
+}
+
+func (s *Service) setCardDatabase(db *carddb.DB) {
+	s.dbMux.Lock()
+
+	if s.db != nil {
+		_ = s.db.Close()
+	}
+
+	s.db = db
+	s.dbMux.Unlock()
+
+	// the names are rebuilt from the new database
+	s.resolverMux.Lock()
+	s.resolver = nil
+	s.resolverMux.Unlock()
+}
+
+func (s *Service) cardDatabase() *carddb.DB {
+	s.dbMux.Lock()
+	defer s.dbMux.Unlock()
+
+	return s.db
+}
+
+// isOutdated tells if a card database is missing, or older than a bulk
+// data file
+func (s *Service) isOutdated(dir, bulk string) bool {
+	bulkInfo, err := os.Stat(bulk)
+	if err != nil {
+		s.logger.Warn().Msgf("bulk data: %v", err)
+		return false
+	}
+
+	if !carddb.Exists(dir) {
+		return true
+	}
+
+	dbInfo, err := os.Stat(dir)
+	if err != nil {
+		return true
+	}
+
+	return bulkInfo.ModTime().After(dbInfo.ModTime())
+}
+
+// absolutePath resolves paths relative to the config directory
+func (s *Service) absolutePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return s.cfgManager.GetFilePath(path)
+}
+
+// lookup runs a lookup against the card database, and against the scryfall
+// api if the card database does not have the card, or does not understand
+// the query. When offline, the card database is all there is.
+func lookup[T any](s *Service, local func(db *carddb.DB) (T, error), remote func() (T, error)) (T, error) {
+	if db := s.cardDatabase(); db != nil {
+		result, err := local(db)
+		fallback := errors.Is(err, carddb.ErrNotFound) || errors.Is(err, cardquery.ErrSyntax)
+		if err == nil || !fallback || s.offline {
+			return result, err
+		}
+	}
+
+	if s.offline {
+		var zero T
+		return zero, errOffline
+	}
+
+	return remote()
+}
+
+// nonEmpty turns empty results of the card database into ErrNotFound, so
+// that lookups fall back to the scryfall api
+func nonEmpty(cards []scryfall.Card, err error) ([]scryfall.Card, error) {
+	if err == nil && len(cards) == 0 {
+		return nil, carddb.ErrNotFound
+	}
+
+	return cards, err
+}
@@ -0,0 +1,92 @@
+package scryfall
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// cornerRadius is the corner radius of a card relative to its width, cards
+// are 63mm wide and have corners with a radius of 1/8 inch
+const cornerRadius = 3.175 / 63
+
+// roundCorners makes the corners of a card image transparent, with
+// antialiased edges. RGBA and NRGBA images are changed in place, other
+// images are copied to an RGBA image first.
+func roundCorners(img image.Image) image.Image {
+	switch dst := img.(type) {
+	case *image.RGBA:
+		applyCornerMask(dst.Pix, dst.Stride, dst.Rect, true)
+		return dst
+	case *image.NRGBA:
+		applyCornerMask(dst.Pix, dst.Stride, dst.Rect, false)
+		return dst
+	}
+
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Rect, img, bounds.Min, draw.Src)
+	applyCornerMask(dst.Pix, dst.Stride, dst.Rect, true)
+
+	return dst
+}
+
+// applyCornerMask scales the alpha of the pixels in the corners of an image
+// by how much of each pixel is inside the rounded rectangle. With
+// premultiplied alpha, the colors are scaled too.
+func applyCornerMask(pix []uint8, stride int, rect image.Rectangle, premultiplied bool) {
+	w, h := rect.Dx(), rect.Dy()
+	radius := float64(w) * cornerRadius
+	size := min(int(math.Ceil(radius)), w/2, h/2)
+
+	mask := cornerMask(radius, size)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			coverage := mask[y*size+x]
+			if coverage == 255 {
+				continue
+			}
+
+			// the mask is of the top left corner, mirrored to the others
+			corners := [4][2]int{{x, y}, {w - 1 - x, y}, {x, h - 1 - y}, {w - 1 - x, h - 1 - y}}
+
+			for _, corner := range corners {
+				offset := corner[1]*stride + corner[0]*4
+
+				if premultiplied {
+					for c := 0; c < 4; c++ {
+						pix[offset+c] = scale(pix[offset+c], coverage)
+					}
+				} else {
+					pix[offset+3] = scale(pix[offset+3], coverage)
+				}
+			}
+		}
+	}
+}
+
+// cornerMask yields the coverage of the pixels of the top left corner by a
+// circle with a radius, from 0 to 255, row by row
+func cornerMask(radius float64, size int) []uint8 {
+	mask := make([]uint8, size*size)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := radius-(float64(x)+0.5), radius-(float64(y)+0.5)
+			if dx <= 0 || dy <= 0 {
+				mask[y*size+x] = 255
+				continue
+			}
+
+			coverage := radius - math.Hypot(dx, dy) + 0.5
+			mask[y*size+x] = uint8(math.Round(255 * min(max(coverage, 0), 1)))
+		}
+	}
+
+	return mask
+}
+
+func scale(value, coverage uint8) uint8 {
+	return uint8((uint16(value)*uint16(coverage) + 127) / 255)
+}
@@ -0,0 +1,145 @@
+package scryfall
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+// the size of the large images of scryfall
+const largeWidth, largeHeight = 672, 936
+
+// darkCard is a card image with a black border, like the jpegs of scryfall
+func darkCard() *image.YCbCr {
+	img := image.NewYCbCr(image.Rect(0, 0, largeWidth, largeHeight), image.YCbCrSubsampleRatio420)
+
+	for idx := range img.Cb {
+		img.Cb[idx], img.Cr[idx] = 128, 128
+	}
+
+	return img
+}
+
+func TestRoundCorners(t *testing.T) {
+	img := roundCorners(darkCard())
+
+	alpha := func(x, y int) uint32 {
+		_, _, _, a := img.At(x, y).RGBA()
+		return a >> 8
+	}
+
+	for _, corner := range []image.Point{{0, 0}, {largeWidth - 1, 0}, {0, largeHeight - 1}, {largeWidth - 1, largeHeight - 1}} {
+		if a := alpha(corner.X, corner.Y); a != 0 {
+			t.Fatalf("expected corner %v to be transparent, got alpha %d", corner, a)
+		}
+	}
+
+	// the flood fill cleared the whole border of dark cards, the mask only
+	// cuts off the corners
+	for _, p := range []image.Point{{0, largeHeight / 2}, {largeWidth / 2, 0}, {largeWidth / 2, largeHeight / 2}} {
+		if a := alpha(p.X, p.Y); a != 255 {
+			t.Fatalf("expected %v to be opaque, got alpha %d", p, a)
+		}
+	}
+
+	// the edge of the corner is antialiased
+	radius := int(math.Ceil(largeWidth * cornerRadius))
+	partial := false
+
+	for x := 0; x < radius; x++ {
+		if a := alpha(x, radius/4); a > 0 && a < 255 {
+			partial = true
+		}
+	}
+
+	if !partial {
+		t.Fatal("expected the edge of the corner to be antialiased")
+	}
+}
+
+func TestRoundCornersNRGBA(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 10, 10+63, 10+88))
+	draw.Draw(img, img.Rect, image.NewUniform(color.NRGBA{R: 200, A: 255}), image.Point{}, draw.Src)
+
+	roundCorners(img)
+
+	if c := img.NRGBAAt(10, 10); c.A != 0 || c.R != 200 {
+		t.Fatalf("expected a transparent corner with its color kept, got %v", c)
+	}
+
+	if c := img.NRGBAAt(40, 50); c.A != 255 {
+		t.Fatalf("expected an opaque center, got %v", c)
+	}
+}
+
+func BenchmarkRoundCorners(b *testing.B) {
+	src := darkCard()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		roundCorners(src)
+	}
+}
+
+// BenchmarkFloodFillCorners is the flood fill roundCorners replaced, copying
+// the jpeg pixel by pixel and filling from every corner
+func BenchmarkFloodFillCorners(b *testing.B) {
+	src := darkCard()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bounds := src.Bounds()
+		rgba := image.NewRGBA(bounds)
+
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				rgba.Set(x, y, src.At(x, y))
+			}
+		}
+
+		for _, corner := range []image.Point{{0, 0}, {bounds.Dx() - 1, 0}, {0, bounds.Dy() - 1}, {bounds.Dx() - 1, bounds.Dy() - 1}} {
+			floodFill(rgba, corner, 0.5)
+		}
+	}
+}
+
+func floodFill(img *image.RGBA, start image.Point, threshold float64) {
+	bounds := img.Bounds()
+	visited := make([][]bool, bounds.Dy())
+	for y := range visited {
+		visited[y] = make([]bool, bounds.Dx())
+	}
+
+	target := img.RGBAAt(start.X, start.Y)
+	queue := []image.Point{start}
+
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if visited[p.Y][p.X] {
+			continue
+		}
+
+		visited[p.Y][p.X] = true
+
+		current := img.RGBAAt(p.X, p.Y)
+		dR, dG, dB := float64(target.R)-float64(current.R), float64(target.G)-float64(current.G), float64(target.B)-float64(current.B)
+
+		if (dR*dR+dG*dG+dB*dB)/(3*255*255) > threshold {
+			continue
+		}
+
+		img.Set(p.X, p.Y, color.Transparent)
+
+		for _, next := range []image.Point{{p.X + 1, p.Y}, {p.X - 1, p.Y}, {p.X, p.Y + 1}, {p.X, p.Y - 1}} {
+			if next.In(bounds) {
+				queue = append(queue, next)
+			}
+		}
+	}
+}
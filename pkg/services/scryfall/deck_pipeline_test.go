@@ -0,0 +1,122 @@
+package scryfall
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/fakeapi"
+	"github.com/gravestench/mtg/pkg/internal/servicetest"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/tappedout"
+)
+
+// TestDeckImages runs the whole pipeline against a fake server: a deck from
+// tappedout, its cards from scryfall and their images, in a runtime like
+// the one of the app
+func TestDeckImages(t *testing.T) {
+	server, err := fakeapi.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	servicetest.WriteConfig(t, dir, "scryfall.json", groupKeyScryfall, map[string]any{
+		keyBaseURL:           server.URL,
+		keyDirectory:         dir,
+		keyCardDatabase:      "",
+		keyRequestsPerSecond: 1000,
+	})
+
+	servicetest.WriteConfig(t, dir, "tappedout.json", "tappedout", map[string]any{
+		"base url": server.URL,
+	})
+
+	to, s := &tappedout.Service{}, &Service{}
+	servicetest.StartRuntime(t, &configFile.Service{RootDirectory: dir}, to, s)
+
+	deck, err := to.GetDeck("https://tappedout.net/mtg-decks/izzet-delver/")
+	if err != nil {
+		t.Fatalf("getting deck: %v", err)
+	}
+
+	images, err := s.GetDeckImages(deck)
+	if err != nil {
+		t.Fatalf("getting images: %v", err)
+	}
+
+	expected := []struct {
+		name  string
+		faces int
+	}{
+		{"Lightning Bolt", 1},
+		{"Delver of Secrets", 2},
+		{"Mountain", 1},
+	}
+
+	if len(images) != len(expected) {
+		t.Fatalf("expected %d cards, got %d", len(expected), len(images))
+	}
+
+	for idx, e := range expected {
+		if images[idx].Entry.Name != e.name || len(images[idx].Faces) != e.faces {
+			t.Fatalf("card %d: expected %s with %d faces, got %s with %d",
+				idx, e.name, e.faces, images[idx].Entry.Name, len(images[idx].Faces))
+		}
+
+		if bounds := images[idx].Faces[0].Bounds(); bounds.Dx() != 63 || bounds.Dy() != 88 {
+			t.Fatalf("card %d: unexpected image size %v", idx, bounds)
+		}
+	}
+
+	// the crops keep their corners, and a missing printing of the preferred
+	// language falls back to the printing of the deck
+	back, err := s.GetImagesFromCardWithOptions(images[1].Card, ImageOptions{
+		Size:     ImageArtCrop,
+		Faces:    FacesBack,
+		Language: "de",
+	})
+	if err != nil {
+		t.Fatalf("getting the back face: %v", err)
+	}
+
+	if len(back) != 1 {
+		t.Fatalf("expected only the back face, got %d images", len(back))
+	}
+
+	if _, _, _, a := back[0].At(0, 0).RGBA(); a == 0 {
+		t.Fatal("expected the corners of the art crop to be kept")
+	}
+
+	// a second render of the deck uses the cached images
+	downloads := countImageRequests(server)
+
+	if _, err = s.GetDeckImages(deck); err != nil {
+		t.Fatal(err)
+	}
+
+	if again := countImageRequests(server); again != downloads {
+		t.Fatalf("expected no more image downloads, got %d after %d", again, downloads)
+	}
+
+	// a deck which is cancelled while its cards are searched has no images
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if images, err = s.GetDeckImagesContext(ctx, deck); !errors.Is(err, context.Canceled) || len(images) != 0 {
+		t.Fatalf("expected no images and a cancelled context, got %d images (%v)", len(images), err)
+	}
+}
+
+func countImageRequests(server *fakeapi.Server) (count int) {
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "/images/") {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,21 @@
+package scryfall
+
+const (
+	// EventDeckProgress is emitted while the cards of a deck are resolved,
+	// and while the hash index is built, with a DeckProgress
+	EventDeckProgress = "scryfall deck progress"
+)
+
+// the stages of resolving a deck, and of building the hash index
+const (
+	StageSearch = "search"
+	StageImages = "images"
+	StageHashes = "hashes"
+)
+
+// DeckProgress tells how many of the cards of a deck are done in a stage
+type DeckProgress struct {
+	Stage string
+	Done  int
+	Total int
+}
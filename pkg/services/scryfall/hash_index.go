@@ -0,0 +1,99 @@
+package scryfall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/cardhash"
+)
+
+const (
+	keyHashIndex     = "hash index"
+	defaultHashIndex = "card_hashes.gob"
+)
+
+// artOptions select the images which are hashed, the art of the front face
+var artOptions = ImageOptions{Size: ImageArtCrop, Faces: FacesFront}
+
+// HashIndex yields the index of the hashes of the art of the printings,
+// which photos of cards are recognized with. It is empty until it is built
+// with BuildHashIndex.
+func (s *Service) HashIndex() (*cardhash.Index, error) {
+	return cardhash.Load(s.hashIndexPath())
+}
+
+// BuildHashIndex hashes the art of every english printing of the card
+// database, or of the printings of some sets, and saves the index. The
+// printings hashed before are skipped, so that an index which was stopped
+// by the context can be completed later.
+func (s *Service) BuildHashIndex(ctx context.Context, sets ...string) (*cardhash.Index, error) {
+	db := s.cardDatabase()
+	if db == nil {
+		return nil, fmt.Errorf("building the hash index needs a card database, set the %q", keyBulkData)
+	}
+
+	idx, err := s.HashIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := db.Filter(func(card *scryfall.Card) bool {
+		if len(sets) > 0 && !containsFold(sets, card.Set) {
+			return false
+		}
+
+		return !idx.Has(card.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading card database: %v", err)
+	}
+
+	s.logger.Info().Msgf("hashing the art of %d printings", len(cards))
+
+	err = s.forEach(ctx, StageHashes, len(cards), func(ctx context.Context, n int) {
+		card := cards[n]
+
+		images, errGet := s.cardImages(ctx, card, artOptions)
+		if errGet != nil || len(images) < 1 {
+			s.logger.Debug().Msgf("no art of %s (%s %s): %v", card.Name, card.Set, card.CollectorNumber, errGet)
+			return
+		}
+
+		idx.Add(cardhash.Entry{
+			ID:              card.ID,
+			Name:            card.Name,
+			Set:             card.Set,
+			CollectorNumber: card.CollectorNumber,
+			Hash:            cardhash.Compute(images[0]),
+		})
+	})
+
+	// what was hashed is kept, also when the context is done
+	if errSave := idx.Save(s.hashIndexPath()); errSave != nil {
+		return nil, errSave
+	}
+
+	return idx, err
+}
+
+func (s *Service) hashIndexPath() string {
+	path := s.cfg.Group(groupKeyScryfall).GetString(keyHashIndex)
+	if path == "" {
+		path = defaultHashIndex
+	}
+
+	return s.absolutePath(path)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package scryfall
+
+import (
+	"net/http"
+
+	"github.com/gravestench/mtg/pkg/httplimit"
+)
+
+const (
+	keyRequestsPerSecond = "requests per second"
+	keyWorkers           = "workers"
+	keyBaseURL           = "base url"
+	keyProxy             = "proxy"
+
+	// scryfall asks for 50 to 100 milliseconds between requests
+	defaultRequestsPerSecond = 10
+	defaultWorkers           = 8
+	defaultBaseURL           = "https://api.scryfall.com/"
+)
+
+// newHTTPClient creates the client of every request to scryfall, which
+// keeps to the configured request rate and retries failed requests
+func (s *Service) newHTTPClient() *http.Client {
+	group := s.cfg.Group(groupKeyScryfall)
+
+	perSecond := group.GetInt(keyRequestsPerSecond)
+	if perSecond <= 0 {
+		perSecond = defaultRequestsPerSecond
+	}
+
+	s.workers = group.GetInt(keyWorkers)
+	if s.workers <= 0 {
+		s.workers = defaultWorkers
+	}
+
+	client, err := httplimit.NewClient(s.Transport, group.GetString(keyProxy), perSecond)
+	if err != nil {
+		s.logger.Warn().Msgf("%v, connecting directly", err)
+	}
+
+	return client
+}
+
+// baseURL yields the url of the scryfall api, which always ends with a
+// slash so that the paths of the client are relative to it
+func (s *Service) baseURL() string {
+	return httplimit.BaseURL(s.cfg.Group(groupKeyScryfall).GetString(keyBaseURL), defaultBaseURL)
+}
@@ -0,0 +1,140 @@
+package scryfall
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/imagecache"
+)
+
+const (
+	keyDirectory         = "directory"
+	keyImageCacheSize    = "image cache size (MB)"
+	keyImageMaxAge       = "image max age (hours)"
+	keyImageMemoryBudget = "image memory budget (MB)"
+
+	imageCacheDirectory = "scryfall_images"
+	mb                  = 1024 * 1024
+)
+
+// openImageCache opens the disk cache of card images, in the configured
+// directory
+func (s *Service) openImageCache() error {
+	group := s.cfg.Group(groupKeyScryfall)
+
+	dir := group.GetString(keyDirectory)
+	if dir == "" {
+		return nil
+	}
+
+	images, err := imagecache.Open(filepath.Join(s.absolutePath(dir), imageCacheDirectory), imagecache.Options{
+		Limit:  int64(group.GetInt(keyImageCacheSize)) * mb,
+		MaxAge: time.Duration(group.GetInt(keyImageMaxAge)) * time.Hour,
+		Client: s.http,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.images = images
+
+	return nil
+}
+
+// OnShutdown saves the index of the disk cache, so that the least recently
+// used images are still known after a restart
+func (s *Service) OnShutdown() {
+	if s.images == nil {
+		return
+	}
+
+	if err := s.images.Close(); err != nil {
+		s.logger.Warn().Msgf("closing image cache: %v", err)
+	}
+}
+
+// imageKey identifies the image of a face of a printing in a size. Cards
+// without a scryfall ID have no key, and are not cached.
+func imageKey(card scryfall.Card, face int, size, url string) string {
+	if card.ID == "" {
+		return ""
+	}
+
+	extension := strings.Split(path.Ext(url), "?")[0]
+
+	return fmt.Sprintf("%s-%d-%s%s", card.ID, face, size, extension)
+}
+
+// imageData yields the file of an image from the disk cache, or downloads
+// it. When offline, only cached images are available.
+func (s *Service) imageData(ctx context.Context, key, url string) ([]byte, error) {
+	if s.images == nil || key == "" {
+		if s.offline {
+			return nil, fmt.Errorf("scryfall is offline, and the image is not cached")
+		}
+
+		data, err := s.download(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("could not download: %v", err)
+		}
+
+		return data, nil
+	}
+
+	if s.offline {
+		data, err := s.images.Cached(key)
+		if err != nil {
+			return nil, fmt.Errorf("scryfall is offline: %w", err)
+		}
+
+		return data, nil
+	}
+
+	data, err := s.images.Get(ctx, key, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not download: %v", err)
+	}
+
+	return data, nil
+}
+
+// decodedImage yields a decoded image from the memory cache. The images are
+// shared, and must not be modified.
+func (s *Service) decodedImage(key string) (image.Image, bool) {
+	s.decodedMux.Lock()
+	defer s.decodedMux.Unlock()
+
+	if s.decoded == nil || key == "" {
+		return nil, false
+	}
+
+	cached, found := s.decoded.Retrieve(key)
+	if !found {
+		return nil, false
+	}
+
+	img, ok := cached.(image.Image)
+
+	return img, ok
+}
+
+func (s *Service) cacheDecodedImage(key string, img image.Image) {
+	s.decodedMux.Lock()
+	defer s.decodedMux.Unlock()
+
+	if s.decoded == nil || key == "" {
+		return
+	}
+
+	bounds := img.Bounds()
+
+	// another deck may have decoded the same image meanwhile
+	_ = s.decoded.Insert(key, img, bounds.Dx()*bounds.Dy()*4)
+}
@@ -0,0 +1,130 @@
+package scryfall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/BlueMonday/go-scryfall"
+
+	"github.com/gravestench/mtg/pkg/carddb"
+)
+
+// the image sizes of scryfall
+const (
+	ImageSmall      ImageSize = "small"
+	ImageNormal     ImageSize = "normal"
+	ImageLarge      ImageSize = "large"
+	ImagePNG        ImageSize = "png"
+	ImageArtCrop    ImageSize = "art_crop"
+	ImageBorderCrop ImageSize = "border_crop"
+)
+
+// ImageSize is one of the image sizes of scryfall
+type ImageSize string
+
+// the faces of a card to get images of
+const (
+	FacesAll ImageFaces = iota
+	FacesFront
+	FacesBack
+)
+
+// ImageFaces selects the faces of a card to get images of
+type ImageFaces int
+
+// ImageOptions select the images yielded by GetImagesFromCardWithOptions.
+// The zero value is the large image of every face, in the language of the
+// card.
+type ImageOptions struct {
+	Size  ImageSize
+	Faces ImageFaces
+
+	// Language is the preferred language of the images, like "de" or "ja".
+	// When the printing does not exist in the language, the images of the
+	// card are yielded.
+	Language string
+}
+
+// DefaultImageOptions are the options of GetImagesFromCard
+var DefaultImageOptions = ImageOptions{Size: ImageLarge, Faces: FacesAll}
+
+// url yields the url of the image of a size
+func (size ImageSize) url(uris scryfall.ImageURIs) (string, error) {
+	switch size {
+	case ImageSmall:
+		return uris.Small, nil
+	case ImageNormal:
+		return uris.Normal, nil
+	case "", ImageLarge:
+		return uris.Large, nil
+	case ImagePNG:
+		return uris.PNG, nil
+	case ImageArtCrop:
+		return uris.ArtCrop, nil
+	case ImageBorderCrop:
+		return uris.BorderCrop, nil
+	}
+
+	return "", fmt.Errorf("unknown image size %q", size)
+}
+
+// roundCorners tells if the corners of an image of a size are cut off. The
+// crops have no corners, and the png images are rounded already.
+func (size ImageSize) roundCorners() bool {
+	switch size {
+	case ImagePNG, ImageArtCrop, ImageBorderCrop:
+		return false
+	}
+
+	return true
+}
+
+// faceURIs yields the image uris of the selected faces of a card, with the
+// index of each face
+func (opts ImageOptions) faceURIs(card scryfall.Card) (faces []int, uris []scryfall.ImageURIs) {
+	if card.ImageURIs != nil {
+		// single-faced cards, and the cards with both faces on the front
+		if opts.Faces == FacesBack {
+			return nil, nil
+		}
+
+		return []int{0}, []scryfall.ImageURIs{*card.ImageURIs}
+	}
+
+	for idx, face := range card.CardFaces {
+		if opts.Faces == FacesFront && idx != 0 || opts.Faces == FacesBack && idx == 0 {
+			continue
+		}
+
+		faces = append(faces, idx)
+		uris = append(uris, face.ImageURIs)
+	}
+
+	return faces, uris
+}
+
+// inLanguage yields the printing of a card in the preferred language, or the
+// card itself when there is no such printing
+func (s *Service) inLanguage(ctx context.Context, card scryfall.Card, lang string) scryfall.Card {
+	if lang == "" || strings.EqualFold(string(card.Lang), lang) || card.Set == "" {
+		return card
+	}
+
+	printing, err := lookup(s, func(db *carddb.DB) (*scryfall.Card, error) {
+		return db.CardByPrintingInLanguage(card.Set, card.CollectorNumber, lang)
+	}, func() (*scryfall.Card, error) {
+		printing, err := s.client.GetCardBySetCodeAndCollectorNumberInLang(ctx, card.Set, card.CollectorNumber, scryfall.Lang(strings.ToLower(lang)))
+		if err != nil {
+			return nil, err
+		}
+
+		return &printing, nil
+	})
+	if err != nil {
+		s.logger.Debug().Msgf("no %s printing of %s (%s %s), using %s: %v", lang, card.Name, card.Set, card.CollectorNumber, card.Lang, err)
+		return card
+	}
+
+	return *printing
+}
@@ -0,0 +1,54 @@
+package scryfall
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// forEach calls fn for every index up to n on the configured number of
+// workers, and emits the progress of a stage. When the context is done, no
+// more calls are started and its error is yielded.
+func (s *Service) forEach(ctx context.Context, stage string, n int, fn func(ctx context.Context, idx int)) error {
+	indices := make(chan int)
+
+	var (
+		wg   sync.WaitGroup
+		done atomic.Int32
+	)
+
+	for w := 0; w < min(max(s.workers, 1), n); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range indices {
+				fn(ctx, idx)
+				s.emitProgress(stage, int(done.Add(1)), n)
+			}
+		}()
+	}
+
+feed:
+	for idx := 0; idx < n; idx++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indices <- idx:
+		}
+	}
+
+	close(indices)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (s *Service) emitProgress(stage string, done, total int) {
+	if s.rt == nil {
+		return
+	}
+
+	s.rt.Events().Emit(EventDeckProgress, DeckProgress{Stage: stage, Done: done, Total: total})
+}
@@ -0,0 +1,90 @@
+package scryfall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gravestench/mtg/pkg/carddb"
+	"github.com/gravestench/mtg/pkg/cardname"
+	"github.com/gravestench/mtg/pkg/decklist"
+)
+
+// the number of suggestions yielded by ResolveName
+const nameSuggestions = 5
+
+// ResolveName yields the card names which a misspelled name might mean, the
+// most likely first
+func (s *Service) ResolveName(name string) ([]cardname.Suggestion, error) {
+	r, err := s.nameResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Suggest(name, nameSuggestions), nil
+}
+
+// CorrectDeck resolves the names of a deck. It yields a copy of the deck
+// where confidently misspelled names are corrected, and every name which
+// was not a card name as is, with suggestions.
+func (s *Service) CorrectDeck(deck *decklist.Deck) (*decklist.Deck, []cardname.Correction, error) {
+	r, err := s.nameResolver()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	corrected, corrections := r.CorrectDeck(deck)
+
+	return corrected, corrections, nil
+}
+
+// nameResolver yields the resolver of every card name, which is built from
+// the card database or from the card name catalog of scryfall once
+func (s *Service) nameResolver() (*cardname.Resolver, error) {
+	s.resolverMux.Lock()
+	defer s.resolverMux.Unlock()
+
+	if s.resolver != nil {
+		return s.resolver, nil
+	}
+
+	names, err := lookup(s, func(db *carddb.DB) ([]string, error) {
+		return db.Names()
+	}, func() ([]string, error) {
+		catalog, err := s.client.GetCardNamesCatalog(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("could not get card names: %w", err)
+		}
+
+		return catalog.Data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.resolver = cardname.NewResolver(names)
+
+	return s.resolver, nil
+}
+
+// correctDeck corrects the names of a deck before its cards are looked up,
+// and logs the names which could not be resolved
+func (s *Service) correctDeck(deck *decklist.Deck) *decklist.Deck {
+	corrected, corrections, err := s.CorrectDeck(deck)
+	if err != nil {
+		s.logger.Warn().Msgf("resolving card names: %v", err)
+		return deck
+	}
+
+	for _, c := range corrections {
+		switch {
+		case c.Corrected:
+			s.logger.Info().Msgf("corrected %q to %q", c.Name, c.Suggestions[0].Name)
+		case len(c.Suggestions) > 0:
+			s.logger.Warn().Msgf("unknown card %q, did you mean %q?", c.Name, c.Suggestions[0].Name)
+		default:
+			s.logger.Warn().Msgf("unknown card %q", c.Name)
+		}
+	}
+
+	return corrected
+}
@@ -3,34 +3,57 @@ package scryfall
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"image"
-	"image/color"
-	"image/draw"
 	"image/jpeg"
 	"image/png"
 	"io"
-	"math"
 	"net/http"
-	"regexp"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/BlueMonday/go-scryfall"
 	"github.com/gravestench/runtime"
 	"github.com/rs/zerolog"
 
+	"github.com/gravestench/mtg/pkg/cache"
+	"github.com/gravestench/mtg/pkg/carddb"
+	"github.com/gravestench/mtg/pkg/cardname"
+	"github.com/gravestench/mtg/pkg/cardquery"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/imagecache"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
-const (
-	regexSplitMtgArenaLine = `(?P<Count>\d+) (?P<Name>[^(]+) \((?P<Set>[^)]+)\)(?P<CollectorNumber> \d+)?`
-)
-
 type Service struct {
+	// Transport sends every request to scryfall, like a recording or a
+	// fake server in tests. When nil, a transport is created from the
+	// config file.
+	Transport http.RoundTripper
+
+	rt         runtime.Runtime
 	client     *scryfall.Client
+	http       *http.Client
+	workers    int
 	logger     *zerolog.Logger
 	cfgManager configFile.Dependency
 	cfg        *configFile.Config
+
+	// the local card database, imported from scryfall bulk data
+	dbMux   sync.Mutex
+	db      *carddb.DB
+	offline bool
+
+	// card images on disk, and decoded in memory
+	images     *imagecache.Cache
+	decodedMux sync.Mutex
+	decoded    *cache.Cache
+
+	// the card names misspelled names are resolved to
+	resolverMux sync.Mutex
+	resolver    *cardname.Resolver
 }
 
 func (s *Service) DependenciesResolved() bool {
@@ -50,12 +73,7 @@ func (s *Service) ResolveDependencies(r runtime.R) {
 }
 
 func (s *Service) Init(rt runtime.Runtime) {
-	client, err := scryfall.NewClient()
-	if err != nil {
-		s.logger.Fatal().Msgf("could not open scryfall client: %v", err)
-	}
-
-	s.client = client
+	s.rt = rt
 
 	cfg, err := s.cfgManager.GetConfigByFileName(s.ConfigFileName())
 	if err != nil {
@@ -63,6 +81,38 @@ func (s *Service) Init(rt runtime.Runtime) {
 	}
 
 	s.cfg = cfg
+
+	if err = s.setup(); err != nil {
+		s.logger.Fatal().Msg(err.Error())
+	}
+}
+
+// setup creates the clients, and opens the card database and image cache
+// of the loaded config
+func (s *Service) setup() error {
+	s.http = s.newHTTPClient()
+
+	// the http client keeps to the rate limit, instead of the client
+	client, err := scryfall.NewClient(
+		scryfall.WithBaseURL(s.baseURL()),
+		scryfall.WithHTTPClient(s.http),
+		scryfall.WithLimiter(nil),
+	)
+	if err != nil {
+		return fmt.Errorf("could not open scryfall client: %v", err)
+	}
+
+	s.client = client
+
+	if err = s.openCardDatabase(); err != nil {
+		return fmt.Errorf("opening card database: %v", err)
+	}
+
+	if err = s.openImageCache(); err != nil {
+		return fmt.Errorf("opening image cache: %v", err)
+	}
+
+	return nil
 }
 
 func (s *Service) Name() string {
@@ -82,12 +132,65 @@ func (s *Service) ConfigFileName() string {
 }
 
 func (s *Service) DefaultConfig() (cfg configFile.Config) {
-	cfg.Group("scryfall").Set("directory", "/tmp")
+	g := cfg.Group(groupKeyScryfall)
+
+	g.Set(keyDirectory, "/tmp")
+	g.Set(keyImageCacheSize, 1024)
+	g.Set(keyImageMaxAge, 7*24)
+	g.Set(keyImageMemoryBudget, 256)
+	g.Set(keyBulkData, "")
+	g.Set(keyCardDatabase, "card_database")
+	g.Set(keyHashIndex, defaultHashIndex)
+	g.Set(keyOffline, false)
+	g.Set(keyRequestsPerSecond, defaultRequestsPerSecond)
+	g.Set(keyWorkers, defaultWorkers)
+	g.Set(keyBaseURL, defaultBaseURL)
+	g.Set(keyProxy, "")
 
 	return
 }
 
-func (s *Service) Search(name string) (*scryfall.CardListResponse, error) {
+// Search yields the cards which match a query in the scryfall search syntax.
+// With a card database, queries are evaluated locally, and only queries the
+// local evaluator does not understand are sent to the scryfall api.
+func (s *Service) Search(query string) (*scryfall.CardListResponse, error) {
+	local := func(db *carddb.DB) (*scryfall.CardListResponse, error) {
+		q, err := cardquery.Parse(query)
+		if err != nil {
+			return nil, err
+		}
+
+		var cards []scryfall.Card
+
+		if name, isName := q.Name(); isName {
+			cards, err = nonEmpty(db.Search(name))
+		} else {
+			cards, err = nonEmpty(db.Filter(q.Match))
+		}
+
+		return &scryfall.CardListResponse{Cards: cards}, err
+	}
+
+	return lookup(s, local, func() (*scryfall.CardListResponse, error) {
+		return s.searchOnline(context.Background(), query)
+	})
+}
+
+// searchName yields the cards with every word of a name in their names. The
+// name is not parsed as a query, names may contain characters which have a
+// meaning in queries.
+func (s *Service) searchName(ctx context.Context, name string) (*scryfall.CardListResponse, error) {
+	local := func(db *carddb.DB) (*scryfall.CardListResponse, error) {
+		cards, err := nonEmpty(db.Search(name))
+		return &scryfall.CardListResponse{Cards: cards}, err
+	}
+
+	return lookup(s, local, func() (*scryfall.CardListResponse, error) {
+		return s.searchOnline(ctx, name)
+	})
+}
+
+func (s *Service) searchOnline(ctx context.Context, name string) (*scryfall.CardListResponse, error) {
 	sco := scryfall.SearchCardsOptions{
 		Unique:        scryfall.UniqueModePrints,
 		Order:         scryfall.OrderSet,
@@ -95,7 +198,7 @@ func (s *Service) Search(name string) (*scryfall.CardListResponse, error) {
 		IncludeExtras: true,
 	}
 
-	result, err := s.client.SearchCards(context.Background(), name, sco)
+	result, err := s.client.SearchCards(ctx, name, sco)
 	if err != nil {
 		return nil, fmt.Errorf("could not search card: %v", err)
 	}
@@ -103,112 +206,263 @@ func (s *Service) Search(name string) (*scryfall.CardListResponse, error) {
 	return &result, nil
 }
 
-func (s *Service) SearchWithDeckList(list string) (cards []scryfall.Card) {
-	const (
-		regexSplitMtgArenaLine = `(?P<Count>\d+) (?P<Name>[^(]+) \((?P<Set>[^)]+)\)(?P<CollectorNumber> \d+)?`
-	)
-
-	var lines []string
+// GetCardByName yields the card with the exact name
+func (s *Service) GetCardByName(name string) (*scryfall.Card, error) {
+	return lookup(s, func(db *carddb.DB) (*scryfall.Card, error) {
+		return db.CardByName(name)
+	}, func() (*scryfall.Card, error) {
+		card, err := s.client.GetCardByName(context.Background(), name, true, scryfall.GetCardByNameOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("could not get card: %w", err)
+		}
 
-	lines = append(lines, strings.Split(list, "\n")...)
+		return &card, nil
+	})
+}
 
-	s.logger.Info().Msgf("processing cards...")
+// GetCardLegalities yields the legality of the card with the exact name by
+// format key, like {"brawl": "legal"}. Unlike the cards of the scryfall
+// client, these hold every format scryfall knows about.
+func (s *Service) GetCardLegalities(name string) (map[string]string, error) {
+	return lookup(s, func(db *carddb.DB) (map[string]string, error) {
+		return db.LegalitiesByName(name)
+	}, func() (map[string]string, error) {
+		data, err := s.download(context.Background(), s.baseURL()+"cards/named?exact="+url.QueryEscape(name))
+		if err != nil {
+			return nil, fmt.Errorf("could not get card: %w", err)
+		}
 
-	mtgArenaLineSplitter := regexp.MustCompile(regexSplitMtgArenaLine)
+		var card struct {
+			Legalities map[string]string `json:"legalities"`
+		}
 
-	for _, line := range lines {
-		line = strings.Trim(line, "\n\t ")
-		if line == "" {
-			continue
+		if err = json.Unmarshal(data, &card); err != nil {
+			return nil, fmt.Errorf("decoding card: %v", err)
 		}
 
-		match := mtgArenaLineSplitter.FindStringSubmatch(line)
+		return card.Legalities, nil
+	})
+}
 
-		data := make(map[string]string)
-		for i, name := range mtgArenaLineSplitter.SubexpNames() {
-			if i > 0 && i <= len(match) {
-				data[name] = match[i]
-			}
+// GetCardByPrinting yields the printing with a set code and collector number
+func (s *Service) GetCardByPrinting(set, collectorNumber string) (*scryfall.Card, error) {
+	return lookup(s, func(db *carddb.DB) (*scryfall.Card, error) {
+		return db.CardByPrinting(set, collectorNumber)
+	}, func() (*scryfall.Card, error) {
+		card, err := s.client.GetCardBySetCodeAndCollectorNumber(context.Background(), strings.ToLower(set), collectorNumber)
+		if err != nil {
+			return nil, fmt.Errorf("could not get card: %w", err)
 		}
 
-		name := data["Name"]
-		name = strings.Split(name, " // ")[0]
+		return &card, nil
+	})
+}
+
+// GetPrintings yields every printing of the card with an oracle ID
+func (s *Service) GetPrintings(oracleID string) ([]scryfall.Card, error) {
+	return lookup(s, func(db *carddb.DB) ([]scryfall.Card, error) {
+		return nonEmpty(db.Printings(oracleID))
+	}, func() ([]scryfall.Card, error) {
+		return s.searchAll(fmt.Sprintf("oracleid:%s", oracleID))
+	})
+}
+
+// GetSetCards yields every printing of a set, by set code
+func (s *Service) GetSetCards(set string) ([]scryfall.Card, error) {
+	return lookup(s, func(db *carddb.DB) ([]scryfall.Card, error) {
+		return nonEmpty(db.SetCards(set))
+	}, func() ([]scryfall.Card, error) {
+		return s.searchAll(fmt.Sprintf("e:%s", set))
+	})
+}
+
+// searchAll yields every printing the scryfall api finds, on every page
+func (s *Service) searchAll(query string) (cards []scryfall.Card, err error) {
+	sco := scryfall.SearchCardsOptions{
+		Unique: scryfall.UniqueModePrints,
+		Order:  scryfall.OrderSet,
+	}
 
-		result, err := s.Search(name)
+	for page := 1; ; page++ {
+		sco.Page = page
+
+		result, err := s.client.SearchCards(context.Background(), query, sco)
 		if err != nil {
-			s.logger.Error().Msgf("searching scryfall for %q: %v", name, err)
-			continue
+			return nil, fmt.Errorf("could not search cards: %w", err)
 		}
 
-		if len(result.Cards) < 1 {
-			s.logger.Warn().Msgf("no cards found for `%v`", name)
-			continue
+		cards = append(cards, result.Cards...)
+
+		if !result.HasMore {
+			return cards, nil
 		}
+	}
+}
 
-		cards = append(cards, result.Cards...)
+func (s *Service) SearchWithDeckList(list string) (cards []scryfall.Card) {
+	cards, _ = s.SearchWithDeckListContext(context.Background(), list)
+	return cards
+}
+
+// SearchWithDeckListContext yields the search results of every card of a
+// deck list, in the order of the list. Cards are searched concurrently, and
+// the search stops when the context is done.
+func (s *Service) SearchWithDeckListContext(ctx context.Context, list string) ([]scryfall.Card, error) {
+	return s.searchDeck(ctx, s.correctDeck(s.parseDeckList(list)))
+}
+
+// parseDeckList parses a deck list, lines which can not be parsed are
+// logged and skipped
+func (s *Service) parseDeckList(list string) *decklist.Deck {
+	deck, err := decklist.Parse(list)
+	if err != nil {
+		s.logger.Warn().Msgf("parsing deck list: %v", err)
 	}
 
-	return
+	return deck
 }
 
-func (s *Service) scryfallGetFirstMatchCardsFromDeckList(list string, cards []scryfall.Card) (result []scryfall.Card) {
-	lines := strings.Split(list, "\n")
+func (s *Service) searchDeck(ctx context.Context, deck *decklist.Deck) ([]scryfall.Card, error) {
+	s.logger.Info().Msgf("processing cards...")
 
-	mtgArenaLineSplitter := regexp.MustCompile(regexSplitMtgArenaLine)
+	names := make([]string, 0)
+	seen := make(map[string]bool)
 
-	for _, line := range lines {
-		line = strings.Trim(line, "\n\t ")
-		if line == "" {
-			continue
+	for _, entry := range deck.Entries() {
+		name := strings.Split(entry.Name, " // ")[0]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
 		}
+	}
 
-		match := mtgArenaLineSplitter.FindStringSubmatch(line)
+	results := make([][]scryfall.Card, len(names))
+
+	err := s.forEach(ctx, StageSearch, len(names), func(ctx context.Context, idx int) {
+		result, err := s.searchName(ctx, names[idx])
+		if err != nil {
+			s.logger.Error().Msgf("searching scryfall for %q: %v", names[idx], err)
+			return
+		}
 
-		deckListLineData := make(map[string]string)
-		for i, key := range mtgArenaLineSplitter.SubexpNames() {
-			if i > 0 && i <= len(match) {
-				deckListLineData[key] = match[i]
-			}
+		if len(result.Cards) < 1 {
+			s.logger.Warn().Msgf("no cards found for `%v`", names[idx])
+			return
 		}
 
-		name := deckListLineData["Name"]
-		name = strings.Split(name, " // ")[0]
+		results[idx] = result.Cards
+	})
+
+	cards := make([]scryfall.Card, 0)
+
+	for _, result := range results {
+		cards = append(cards, result...)
+	}
 
-		for _, card := range cards {
-			if strings.ToLower(card.Name) != strings.ToLower(deckListLineData["Name"]) {
-				continue
-			}
+	return cards, err
+}
 
-			if strings.ToLower(card.Set) != strings.ToLower(deckListLineData["Set"]) {
-				continue
-			}
+// firstMatch picks the printing of a deck entry from the search results.
+// Entries without a set get the first printing found with a matching name.
+func firstMatch(entry decklist.Entry, cards []scryfall.Card) (match scryfall.Card, found bool) {
+	for _, card := range cards {
+		if !sameName(card.Name, entry.Name) {
+			continue
+		}
 
-			//if deckListLineData["CollectorNumber"] != "" {
-			//	if strings.ToLower(card.CollectorNumber) != strings.ToLower(deckListLineData["CollectorNumber"]) {
-			//		continue
-			//	}
-			//}
+		if entry.Set != "" && !strings.EqualFold(card.Set, entry.Set) {
+			continue
+		}
 
-			result = append(result, card)
+		// an exact printing beats any other printing of the same set
+		if entry.CollectorNumber != "" && strings.EqualFold(card.CollectorNumber, entry.CollectorNumber) {
+			return card, true
+		}
 
-			break
+		if !found {
+			match, found = card, true
 		}
 	}
 
-	return
+	return match, found
+}
+
+// sameName tells if a deck entry names a card, the cards with several faces
+// can be named by their front face, like "Delver of Secrets"
+func sameName(cardName, entryName string) bool {
+	if strings.EqualFold(cardName, entryName) {
+		return true
+	}
+
+	return strings.EqualFold(strings.Split(cardName, " // ")[0], entryName)
 }
 
+// GetImagesFromCard yields the large images of a card, double-faced cards
+// have an image of each face. Images are cached on disk and in memory, when
+// offline only cached images are available.
 func (s *Service) GetImagesFromCard(card scryfall.Card) (images []image.Image, err error) {
-	collectorNumber := card.CollectorNumber
-	if collectorNumber != "" {
-		collectorNumber = fmt.Sprintf("_%s", collectorNumber)
+	return s.cardImages(context.Background(), card, DefaultImageOptions)
+}
+
+// GetImagesFromCardWithOptions yields the images of the selected faces of a
+// card, in a size and a preferred language. Only the small, normal and
+// large images get rounded corners, the png images have them already.
+func (s *Service) GetImagesFromCardWithOptions(card scryfall.Card, opts ImageOptions) ([]image.Image, error) {
+	return s.cardImages(context.Background(), card, opts)
+}
+
+func (s *Service) cardImages(ctx context.Context, card scryfall.Card, opts ImageOptions) (images []image.Image, err error) {
+	card = s.inLanguage(ctx, card, opts.Language)
+
+	type faceImage struct {
+		face int
+		url  string
 	}
 
-	if card.ImageURIs == nil {
+	urls := make([]faceImage, 0)
+	faces, uris := opts.faceURIs(card)
+
+	for idx := range uris {
+		url, errURL := opts.Size.url(uris[idx])
+		if errURL != nil {
+			return nil, errURL
+		}
+
+		if url != "" {
+			urls = append(urls, faceImage{face: faces[idx], url: url})
+		}
+	}
+
+	if len(urls) < 1 {
 		return nil, fmt.Errorf("no image URI's")
 	}
 
-	url := card.ImageURIs.Large
+	size := opts.Size
+	if size == "" {
+		size = ImageLarge
+	}
+
+	for _, face := range urls {
+		img, errGet := s.getImage(ctx, imageKey(card, face.face, string(size), face.url), face.url, size.roundCorners())
+		if errGet != nil {
+			return nil, errGet
+		}
+
+		if img != nil {
+			images = append(images, img)
+		}
+	}
+
+	return images, nil
+}
+
+// getImage yields an image from the memory cache, the disk cache or
+// downloaded, with its corners cut off when round is set. Images which can
+// not be decoded are nil.
+func (s *Service) getImage(ctx context.Context, key, url string, round bool) (image.Image, error) {
+	if img, found := s.decodedImage(key); found {
+		return img, nil
+	}
 
 	urlParts := strings.Split(url, ".")
 	extension := urlParts[len(urlParts)-1]
@@ -220,150 +474,127 @@ func (s *Service) GetImagesFromCard(card scryfall.Card) (images []image.Image, e
 		extension = strings.Split(extension, "?")[0]
 	}
 
-	imageData, err := download(url)
+	imageData, err := s.imageData(ctx, key, url)
 	if err != nil {
-		return nil, fmt.Errorf("could not download: %v", err)
+		return nil, err
 	}
 
+	var img image.Image
+
 	switch extension {
 	case "png":
-		if img, errDecode := png.Decode(bytes.NewReader(imageData)); errDecode == nil {
-			images = append(images, addRoundedCornersWithThreshold(img, 0.5))
-		}
+		img, err = png.Decode(bytes.NewReader(imageData))
 	case "jpg", "jpeg":
-		if img, errDecode := jpeg.Decode(bytes.NewReader(imageData)); errDecode == nil {
-			// Create a new RGBA image of the same size as the decoded image
-			bounds := img.Bounds()
-			rgbaImg := image.NewRGBA(bounds)
-
-			// Copy the pixels from the decoded image to the RGBA image
-			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-				for x := bounds.Min.X; x < bounds.Max.X; x++ {
-					rgbaImg.Set(x, y, img.At(x, y))
-				}
-			}
-
-			images = append(images, addRoundedCornersWithThreshold(rgbaImg, 0.5))
-		}
+		img, err = jpeg.Decode(bytes.NewReader(imageData))
 	}
 
-	return images, nil
-}
-
-func (s *Service) GetImagesFromDeckList(list string) (images []image.Image, err error) {
-	cards := s.SearchWithDeckList(list)
-	cards = s.scryfallGetFirstMatchCardsFromDeckList(list, cards)
-
-	for _, card := range cards {
-		cardImages, errGet := s.GetImagesFromCard(card)
-		if errGet != nil {
-			continue
-		}
+	if err != nil {
+		// images which can not be decoded are left out
+		return nil, nil
+	}
 
-		if len(cardImages) < 1 {
-			continue
-		}
+	if img != nil && round {
+		img = roundCorners(img)
+	}
 
-		images = append(images, cardImages[0])
+	if img != nil {
+		s.cacheDecodedImage(key, img)
 	}
 
-	return
+	return img, nil
 }
 
-func download(uri string) ([]byte, error) {
-	res, err := http.Get(uri)
+// GetImagesFromDeckList yields the image of the first face of every card of
+// a deck list
+func (s *Service) GetImagesFromDeckList(list string) (images []image.Image, err error) {
+	deckImages, err := s.GetDeckImages(s.parseDeckList(list))
 	if err != nil {
-		return nil, fmt.Errorf("issuing http request: %v", err)
+		return nil, err
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	d, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response body: %v", err)
+	for _, cardImages := range deckImages {
+		images = append(images, cardImages.Faces[0])
 	}
 
-	return d, err
+	return
 }
 
-func addRoundedCornersWithThreshold(img image.Image, normalizedThreshold float64) image.Image {
-	bounds := img.Bounds()
+// GetDeckImages yields the images of every face of the cards of a deck, by
+// deck entry. Misspelled names are corrected first, the entries have the
+// corrected names. Entries which can not be found or have no image are left
+// out.
+func (s *Service) GetDeckImages(deck *decklist.Deck) (images []CardImages, err error) {
+	return s.GetDeckImagesContext(context.Background(), deck)
+}
 
-	// Create a copy of the original image
-	result := image.NewRGBA(bounds)
-	draw.Draw(result, bounds, img, image.Point{}, draw.Src)
+// GetDeckImagesContext is GetDeckImages with a context. Cards are searched
+// and downloaded concurrently, the images stay in the order of the deck.
+// When the context is done, the images found so far are yielded with the
+// error of the context. Images are only downloaded once every card was
+// searched, so there are none when it is done while searching.
+func (s *Service) GetDeckImagesContext(ctx context.Context, deck *decklist.Deck) ([]CardImages, error) {
+	deck = s.correctDeck(deck)
 
-	// Define the flood-fill starting points (corners)
-	corners := []image.Point{
-		{0, 0},                             // Top-left corner
-		{bounds.Dx() - 1, 0},               // Top-right corner
-		{0, bounds.Dy() - 1},               // Bottom-left corner
-		{bounds.Dx() - 1, bounds.Dy() - 1}, // Bottom-right corner
+	cards, err := s.searchDeck(ctx, deck)
+	if err != nil {
+		return nil, err
 	}
 
-	// Flood-fill each corner
-	for _, corner := range corners {
-		floodFill(result, corner, normalizedThreshold)
-	}
+	entries := deck.Entries()
+	results := make([]*CardImages, len(entries))
 
-	return result
-}
+	err = s.forEach(ctx, StageImages, len(entries), func(ctx context.Context, idx int) {
+		entry := entries[idx]
 
-func floodFill(img *image.RGBA, start image.Point, normalizedThreshold float64) {
-	bounds := img.Bounds()
-	visited := make([][]bool, bounds.Dy())
-	for y := range visited {
-		visited[y] = make([]bool, bounds.Dx())
-	}
+		card, found := firstMatch(entry, cards)
+		if !found {
+			return
+		}
 
-	targetColor := img.At(start.X, start.Y).(color.RGBA)
+		faces, errGet := s.cardImages(ctx, card, DefaultImageOptions)
+		if errGet != nil {
+			s.logger.Warn().Msgf("getting images of %q: %v", entry.Name, errGet)
+			return
+		}
 
-	var queue []image.Point
-	queue = append(queue, start)
+		if len(faces) < 1 {
+			return
+		}
 
-	for len(queue) > 0 {
-		p := queue[len(queue)-1]
-		queue = queue[:len(queue)-1]
+		results[idx] = &CardImages{Entry: entry, Card: card, Faces: faces}
+	})
 
-		if visited[p.Y][p.X] {
-			continue
-		}
+	images := make([]CardImages, 0, len(results))
 
-		visited[p.Y][p.X] = true
-
-		currentColor := img.At(p.X, p.Y).(color.RGBA)
-		distance := colorDistanceNormalized(targetColor, currentColor)
-
-		if distance <= normalizedThreshold {
-			img.Set(p.X, p.Y, color.Transparent)
-
-			if p.X+1 < bounds.Dx() {
-				queue = append(queue, image.Point{p.X + 1, p.Y})
-			}
-			if p.X-1 >= 0 {
-				queue = append(queue, image.Point{p.X - 1, p.Y})
-			}
-			if p.Y+1 < bounds.Dy() {
-				queue = append(queue, image.Point{p.X, p.Y + 1})
-			}
-			if p.Y-1 >= 0 {
-				queue = append(queue, image.Point{p.X, p.Y - 1})
-			}
+	for _, result := range results {
+		if result != nil {
+			images = append(images, *result)
 		}
 	}
+
+	return images, err
 }
 
-func colorDistanceNormalized(a, b color.RGBA) float64 {
-	// Calculate the squared Euclidean distance in RGB color space
-	dR := float64(a.R) - float64(b.R)
-	dG := float64(a.G) - float64(b.G)
-	dB := float64(a.B) - float64(b.B)
-	distanceSquared := dR*dR + dG*dG + dB*dB
+func (s *Service) download(ctx context.Context, uri string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %v", err)
+	}
 
-	// Calculate the maximum possible distance (the diagonal of the RGB cube)
-	maxDistance := math.Sqrt(3 * 255 * 255)
+	res, err := s.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("issuing http request: %v", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", uri, res.Status)
+	}
 
-	// Normalize the distance by dividing by the maximum possible distance
-	normalizedDistance := distanceSquared / (maxDistance * maxDistance)
+	d, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %v", err)
+	}
 
-	return normalizedDistance
+	return d, err
 }
@@ -1,14 +1,31 @@
 package scryfall
 
 import (
+	"context"
 	"image"
 
 	"github.com/BlueMonday/go-scryfall"
 	"github.com/gravestench/runtime"
 
+	"github.com/gravestench/mtg/pkg/cardhash"
+	"github.com/gravestench/mtg/pkg/cardname"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/services/cacheManager"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service             = &Service{} // implement in`service.go`
+	_ runtime.HasLogger           = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies     = &Service{} // implement in`service.go`
+	_ cacheManager.HasCache       = &Service{} // implement in`cache_manager_integration.go`
+	_ runtime.HasGracefulShutdown = &Service{} // implement in`image_cache.go`
+	_ ScryfallClient              = &Service{} // implement in`service.go`
+)
+
 type Dependency = ScryfallClient
 
 type ScryfallClient interface {
@@ -16,8 +33,29 @@ type ScryfallClient interface {
 	runtime.HasLogger
 	runtime.HasDependencies
 	configFile.HasDefaultConfig
-	Search(name string) (*scryfall.CardListResponse, error)
+	Search(query string) (*scryfall.CardListResponse, error)
+	GetCardByName(name string) (*scryfall.Card, error)
+	GetCardLegalities(name string) (map[string]string, error)
+	GetCardByPrinting(set, collectorNumber string) (*scryfall.Card, error)
+	GetPrintings(oracleID string) ([]scryfall.Card, error)
+	GetSetCards(set string) ([]scryfall.Card, error)
 	SearchWithDeckList(list string) []scryfall.Card
 	GetImagesFromCard(card scryfall.Card) ([]image.Image, error)
+	GetImagesFromCardWithOptions(card scryfall.Card, opts ImageOptions) ([]image.Image, error)
 	GetImagesFromDeckList(list string) ([]image.Image, error)
+	GetDeckImages(deck *decklist.Deck) ([]CardImages, error)
+	GetDeckImagesContext(ctx context.Context, deck *decklist.Deck) ([]CardImages, error)
+	SearchWithDeckListContext(ctx context.Context, list string) ([]scryfall.Card, error)
+	ImportBulkData(path string) error
+	HashIndex() (*cardhash.Index, error)
+	BuildHashIndex(ctx context.Context, sets ...string) (*cardhash.Index, error)
+	ResolveName(name string) ([]cardname.Suggestion, error)
+	CorrectDeck(deck *decklist.Deck) (*decklist.Deck, []cardname.Correction, error)
+}
+
+// CardImages are the images of the faces of the card of a deck entry
+type CardImages struct {
+	Entry decklist.Entry
+	Card  scryfall.Card
+	Faces []image.Image
 }
@@ -0,0 +1,43 @@
+package setInfo
+
+import (
+	"sync"
+
+	"github.com/gravestench/runtime"
+)
+
+// Binding holds this service for the services which use it when it is
+// there. They bind every service of the runtime in their Init, and every
+// service added later in their OnServiceAdded.
+type Binding struct {
+	mux  sync.Mutex
+	sets Dependency
+}
+
+// Bind binds a service if it provides the set info, and yields whether it
+// was not bound before
+func (b *Binding) Bind(service runtime.Service) bool {
+	candidate, ok := service.(Dependency)
+	if !ok {
+		return false
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.sets == candidate {
+		return false
+	}
+
+	b.sets = candidate
+
+	return true
+}
+
+// Sets yields the set info, which is nil until it is bound
+func (b *Binding) Sets() Dependency {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	return b.sets
+}
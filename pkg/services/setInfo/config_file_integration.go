@@ -0,0 +1,62 @@
+package setInfo
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+const (
+	groupKeySetInfo    = "Set Info"
+	keyDirectory       = "directory"
+	keyScryfallURL     = "scryfall url"
+	keyProxy           = "proxy"
+	keyRefreshInterval = "refresh interval"
+)
+
+// new sets are announced every few weeks, new symbols hardly ever
+const defaultRefreshInterval = 24 * time.Hour
+
+func (s *Service) ConfigFileName() string {
+	return "set_info.json"
+}
+
+func (s *Service) DefaultConfig() (cfg configFile.Config) {
+	g := cfg.Group(groupKeySetInfo)
+
+	g.Set(keyDirectory, "sets")
+	g.Set(keyScryfallURL, defaultScryfallURL)
+	g.Set(keyProxy, "")
+	g.Set(keyRefreshInterval, defaultRefreshInterval.String())
+
+	return
+}
+
+// cacheDirectory yields the absolute path of the set info cache, relative
+// paths are relative to the config file directory
+func (s *Service) cacheDirectory() string {
+	path := s.cfg.Group(groupKeySetInfo).GetString(keyDirectory)
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return s.cfgManager.GetFilePath(path)
+}
+
+// refreshInterval yields how long the sets and the symbology are used before
+// they are fetched again, like "24h"
+func (s *Service) refreshInterval() time.Duration {
+	value := s.cfg.Group(groupKeySetInfo).GetString(keyRefreshInterval)
+	if value == "" {
+		return defaultRefreshInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		s.logger.Warn().Msgf("refresh interval: %v", err)
+		return defaultRefreshInterval
+	}
+
+	return interval
+}
@@ -0,0 +1,32 @@
+package setInfo
+
+import (
+	"net/http"
+
+	"github.com/gravestench/mtg/pkg/httplimit"
+)
+
+const (
+	defaultScryfallURL = "https://api.scryfall.com/"
+
+	// scryfall asks for 50 to 100 milliseconds between requests
+	requestsPerSecond = 10
+)
+
+// newHTTPClient creates the client of every request to scryfall
+func (s *Service) newHTTPClient() *http.Client {
+	proxy := s.cfg.Group(groupKeySetInfo).GetString(keyProxy)
+
+	client, err := httplimit.NewClient(s.Transport, proxy, requestsPerSecond)
+	if err != nil {
+		s.logger.Warn().Msgf("%v, connecting directly", err)
+	}
+
+	return client
+}
+
+// scryfallURL yields the configured url of scryfall, which always ends with
+// a slash
+func (s *Service) scryfallURL() string {
+	return httplimit.BaseURL(s.cfg.Group(groupKeySetInfo).GetString(keyScryfallURL), defaultScryfallURL)
+}
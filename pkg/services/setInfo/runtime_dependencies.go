@@ -0,0 +1,27 @@
+package setInfo
+
+import (
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/services/configFile"
+)
+
+// the following methods are invoked by the runtime
+// automatically in an endless loop. As soon as the
+// dependencies are resolved, the Init method is called.
+
+func (s *Service) DependenciesResolved() bool {
+	if s.cfgManager == nil {
+		return false
+	}
+
+	return true
+}
+
+func (s *Service) ResolveDependencies(r runtime.R) {
+	for _, service := range r.Services() {
+		if candidate, ok := service.(configFile.Dependency); ok {
+			s.cfgManager = candidate
+		}
+	}
+}
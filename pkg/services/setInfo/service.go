@@ -0,0 +1,431 @@
+package setInfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gravestench/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/gravestench/mtg/pkg/httplimit"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+// retryAfter is how long old sets and symbology are used after they could
+// not be fetched, before fetching them is tried again
+const retryAfter = time.Minute
+
+// what is fetched, for the fetching and failed bookkeeping
+const (
+	itemSets      = "sets"
+	itemSymbology = "symbology"
+	itemIcon      = "icon of "
+)
+
+// Service provides the sets and the symbology of scryfall. Both are cached
+// on disk and fetched again after the refresh interval. When they can not be
+// fetched, the cached ones are used even if they are old. Set icons are
+// cached when they are first used, and are never fetched again.
+type Service struct {
+	// Transport sends every request to scryfall, like a fake server in
+	// tests. When nil, a transport is created from the config file.
+	Transport http.RoundTripper
+
+	http       *http.Client
+	logger     *zerolog.Logger
+	cfgManager configFile.Dependency
+	cfg        *configFile.Config
+	cache      *setinfo.Cache
+
+	mux sync.Mutex
+
+	sets          setinfo.SetList
+	setsLoaded    bool
+	index         *setinfo.Index
+	symbols       setinfo.SymbolList
+	symbolsLoaded bool
+	symbology     *setinfo.Symbology
+	icons         map[string][]byte
+
+	// when the sets, the symbology and the icons last failed to be fetched
+	// and why, and which are being fetched, so that other calls use the old
+	// ones meanwhile, or wait for them when there are none
+	failed   map[string]time.Time
+	errs     map[string]error
+	fetching map[string]chan struct{}
+}
+
+func (s *Service) Init(rt runtime.Runtime) {
+	cfg, err := s.cfgManager.GetConfigByFileName(s.ConfigFileName())
+	if err != nil {
+		s.logger.Fatal().Msgf("loading config file: %v", err)
+	}
+
+	s.cfg = cfg
+	s.http = s.newHTTPClient()
+	s.cache = setinfo.NewCache(s.cacheDirectory())
+	s.icons = make(map[string][]byte)
+	s.failed = make(map[string]time.Time)
+	s.errs = make(map[string]error)
+	s.fetching = make(map[string]chan struct{})
+}
+
+func (s *Service) Name() string {
+	return "Set Info"
+}
+
+// the following methods are boilerplate, but they are used
+// by the runtime to enforce a standard logging format.
+
+func (s *Service) BindLogger(logger *zerolog.Logger) {
+	s.logger = logger
+}
+
+func (s *Service) Logger() *zerolog.Logger {
+	return s.logger
+}
+
+// Sets yields every set, from the oldest to the newest
+func (s *Service) Sets() ([]setinfo.Set, error) {
+	idx, err := s.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Sets(), nil
+}
+
+// Set yields a set by its code, like "m10"
+func (s *Service) Set(code string) (setinfo.Set, error) {
+	idx, err := s.Index()
+	if err != nil {
+		return setinfo.Set{}, err
+	}
+
+	return idx.Set(code)
+}
+
+// SetCode yields the code of a set by its code or its name, like "m10" or
+// "Magic 2010", for the collections imported with set names
+func (s *Service) SetCode(codeOrName string) (string, error) {
+	idx, err := s.Index()
+	if err != nil {
+		return "", err
+	}
+
+	set, err := idx.Set(codeOrName)
+	if errors.Is(err, setinfo.ErrUnknownSet) {
+		set, err = idx.SetByName(codeOrName)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return set.Code, nil
+}
+
+// Index yields the index of the sets, for looking up sets and their release
+// order
+func (s *Service) Index() (*setinfo.Index, error) {
+	err := s.ensure(itemSets, s.currentSets, s.refreshSets)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.index == nil {
+		return nil, err
+	}
+
+	if err != nil {
+		s.logger.Warn().Msgf("%v, using the sets fetched %s", err, s.sets.Fetched.Format(time.DateOnly))
+	}
+
+	return s.index, nil
+}
+
+// Symbols yields every symbol of the rules text and mana costs
+func (s *Service) Symbols() ([]setinfo.Symbol, error) {
+	symbology, err := s.Symbology()
+	if err != nil {
+		return nil, err
+	}
+
+	return symbology.Symbols(), nil
+}
+
+// Symbol yields a symbol, like "{W}" or "2/W"
+func (s *Service) Symbol(symbol string) (setinfo.Symbol, error) {
+	symbology, err := s.Symbology()
+	if err != nil {
+		return setinfo.Symbol{}, err
+	}
+
+	return symbology.Symbol(symbol)
+}
+
+// Symbology yields the table of symbols, for parsing mana costs
+func (s *Service) Symbology() (*setinfo.Symbology, error) {
+	err := s.ensure(itemSymbology, s.currentSymbology, s.refreshSymbology)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.symbology == nil {
+		return nil, err
+	}
+
+	if err != nil {
+		s.logger.Warn().Msgf("%v, using the symbology fetched %s", err, s.symbols.Fetched.Format(time.DateOnly))
+	}
+
+	return s.symbology, nil
+}
+
+// Refresh fetches the sets and the symbology regardless of the refresh
+// interval. What can not be fetched is kept as it was.
+func (s *Service) Refresh() error {
+	return errors.Join(s.refreshSets(), s.refreshSymbology())
+}
+
+// SetIconSVG yields the svg icon of a set
+func (s *Service) SetIconSVG(code string) ([]byte, error) {
+	set, err := s.Set(code)
+	if err != nil {
+		return nil, err
+	}
+
+	current := func() (found, stale bool) {
+		return s.currentIcon(set.Code), false
+	}
+
+	err = s.ensure(itemIcon+set.Code, current, func() error {
+		return s.refreshIcon(set)
+	})
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if svg, found := s.icons[set.Code]; found {
+		return svg, nil
+	}
+
+	return nil, err
+}
+
+// SetIcon yields the icon of a set in black, with a height in pixels, like
+// the set symbol of a card
+func (s *Service) SetIcon(code string, height int) (image.Image, error) {
+	svg, err := s.SetIconSVG(code)
+	if err != nil {
+		return nil, err
+	}
+
+	icon, err := setinfo.Icon(svg, height, color.Black)
+	if err != nil {
+		return nil, fmt.Errorf("drawing icon of %s: %w", code, err)
+	}
+
+	return icon, nil
+}
+
+// ensure makes sure that an item is there, fetching it with refresh when it
+// is missing or stale. current yields whether the item is there and whether
+// it is stale, with the lock held. An item which could not be fetched is not
+// fetched again before retryAfter, and one which is being fetched is not
+// waited for unless it is missing. The error is the one of fetching the item,
+// the item may still be there, only old. The lock must not be held.
+func (s *Service) ensure(item string, current func() (found, stale bool), refresh func() error) error {
+	s.mux.Lock()
+
+	for {
+		found, stale := current()
+		done, fetching := s.fetching[item]
+
+		switch {
+		case found && !stale:
+			s.mux.Unlock()
+			return nil
+		case !fetching && time.Since(s.failed[item]) >= retryAfter:
+			done = make(chan struct{})
+			s.fetching[item] = done
+			s.mux.Unlock()
+
+			err := refresh()
+
+			s.mux.Lock()
+			defer s.mux.Unlock()
+
+			delete(s.fetching, item)
+			close(done)
+
+			if err != nil {
+				s.failed[item], s.errs[item] = time.Now(), err
+			}
+
+			return err
+		case found:
+			// the old one is used meanwhile
+			s.mux.Unlock()
+			return nil
+		case fetching:
+			// the first fetch is still going on
+			s.mux.Unlock()
+			<-done
+			s.mux.Lock()
+		default:
+			err := s.errs[item]
+			s.mux.Unlock()
+
+			return err
+		}
+	}
+}
+
+// currentSets loads the sets cached on disk the first time the sets are
+// used, and yields whether there are sets and whether they are stale, the
+// lock must be held
+func (s *Service) currentSets() (found, stale bool) {
+	if !s.setsLoaded {
+		s.setsLoaded = true
+
+		cached, err := s.cache.LoadSets()
+
+		switch {
+		case err == nil:
+			s.sets, s.index = cached, setinfo.NewIndex(cached.Sets)
+		case !errors.Is(err, os.ErrNotExist):
+			s.logger.Warn().Msgf("%v", err)
+		}
+	}
+
+	return s.index != nil, s.sets.Stale(s.refreshInterval(), time.Now())
+}
+
+// currentSymbology loads the symbology cached on disk the first time the
+// symbology is used, and yields whether there is a symbology and whether it
+// is stale, the lock must be held
+func (s *Service) currentSymbology() (found, stale bool) {
+	if !s.symbolsLoaded {
+		s.symbolsLoaded = true
+
+		cached, err := s.cache.LoadSymbology()
+
+		switch {
+		case err == nil:
+			s.symbols, s.symbology = cached, setinfo.NewSymbology(cached.Symbols)
+		case !errors.Is(err, os.ErrNotExist):
+			s.logger.Warn().Msgf("%v", err)
+		}
+	}
+
+	return s.symbology != nil, s.symbols.Stale(s.refreshInterval(), time.Now())
+}
+
+// currentIcon loads the icon of a set cached on disk, and yields whether it
+// is there, the lock must be held
+func (s *Service) currentIcon(code string) bool {
+	if _, found := s.icons[code]; found {
+		return true
+	}
+
+	svg, err := s.cache.LoadIcon(code)
+
+	switch {
+	case err == nil:
+		s.icons[code] = svg
+		return true
+	case !errors.Is(err, os.ErrNotExist):
+		s.logger.Warn().Msgf("reading icon of %s: %v", code, err)
+	}
+
+	return false
+}
+
+// refreshSets fetches the sets and caches them, the lock must not be held as
+// it is only taken once the sets are fetched
+func (s *Service) refreshSets() error {
+	sets, err := setinfo.FetchSets(context.Background(), s.http, s.scryfallURL())
+	if err != nil {
+		return fmt.Errorf("fetching sets: %w", err)
+	}
+
+	if len(sets) == 0 {
+		return fmt.Errorf("fetching sets: there are none")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.sets = setinfo.SetList{Fetched: time.Now().UTC(), Sets: sets}
+	s.index = setinfo.NewIndex(sets)
+	s.setsLoaded = true
+
+	if err = s.cache.SaveSets(s.sets); err != nil {
+		s.logger.Warn().Msgf("%v", err)
+	}
+
+	s.logger.Info().Msgf("fetched %d sets", len(sets))
+
+	return nil
+}
+
+// refreshSymbology fetches the symbology and caches it, the lock must not be
+// held as it is only taken once the symbology is fetched
+func (s *Service) refreshSymbology() error {
+	symbols, err := setinfo.FetchSymbology(context.Background(), s.http, s.scryfallURL())
+	if err != nil {
+		return fmt.Errorf("fetching symbology: %w", err)
+	}
+
+	if len(symbols) == 0 {
+		return fmt.Errorf("fetching symbology: there are no symbols")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.symbols = setinfo.SymbolList{Fetched: time.Now().UTC(), Symbols: symbols}
+	s.symbology = setinfo.NewSymbology(symbols)
+	s.symbolsLoaded = true
+
+	if err = s.cache.SaveSymbology(s.symbols); err != nil {
+		s.logger.Warn().Msgf("%v", err)
+	}
+
+	s.logger.Info().Msgf("fetched %d symbols", len(symbols))
+
+	return nil
+}
+
+// refreshIcon fetches the icon of a set and caches it, the lock must not be
+// held as it is only taken once the icon is fetched
+func (s *Service) refreshIcon(set setinfo.Set) error {
+	if set.IconSVGURI == "" {
+		return fmt.Errorf("set %s has no icon", set.Code)
+	}
+
+	svg, err := httplimit.GetBytes(context.Background(), s.http, set.IconSVGURI)
+	if err != nil {
+		return fmt.Errorf("fetching icon of %s: %w", set.Code, err)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.icons[set.Code] = svg
+
+	if err = s.cache.SaveIcon(set.Code, svg); err != nil {
+		s.logger.Warn().Msgf("%v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package setInfo
+
+import (
+	"image"
+
+	"github.com/gravestench/runtime"
+
+	"github.com/gravestench/mtg/pkg/services/collection"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/services/webRouter"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+// these are static declarations that force a
+// compile-time error if the service does not
+// implement them.
+var (
+	_ runtime.Service              = &Service{} // implement in`service.go`
+	_ runtime.HasLogger            = &Service{} // implement in`service.go`
+	_ runtime.HasDependencies      = &Service{} // implement in`runtime_dependencies.go`
+	_ configFile.HasDefaultConfig  = &Service{} // implement in`config_file_integration.go`
+	_ webRouter.IsRouteInitializer = &Service{} // implement in`web_router_integration.go`
+	_ webRouter.HasRouteSlug       = &Service{} // implement in`web_router_integration.go`
+	_ ProvidesSetInfo              = &Service{} // implement in`service.go`
+	_ collection.ResolvesSetCodes  = &Service{} // implement in`service.go`
+)
+
+// this is an alias which can be used to make
+// the dependency resolution methods of other
+// services more coherent. It's just sugar.
+
+type Dependency = ProvidesSetInfo
+
+// Here is the declaration of our service as
+// an interface. This is all the dependent services
+// should know about this service.
+
+type ProvidesSetInfo interface {
+	Sets() ([]setinfo.Set, error)
+	Set(code string) (setinfo.Set, error)
+	SetCode(codeOrName string) (string, error)
+	Index() (*setinfo.Index, error)
+	Symbols() ([]setinfo.Symbol, error)
+	Symbol(symbol string) (setinfo.Symbol, error)
+	Symbology() (*setinfo.Symbology, error)
+	SetIconSVG(code string) ([]byte, error)
+	SetIcon(code string, height int) (image.Image, error)
+	Refresh() error
+}
@@ -0,0 +1,210 @@
+package setInfo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravestench/mtg/pkg/fakeapi"
+	"github.com/gravestench/mtg/pkg/httplimit"
+	"github.com/gravestench/mtg/pkg/internal/servicetest"
+	"github.com/gravestench/mtg/pkg/services/configFile"
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+// newTestService creates a service which fetches from a fake server, and
+// caches in a temporary directory
+func newTestService(t *testing.T, baseURL, dir string) *Service {
+	s := &Service{}
+	s.cfg = servicetest.Configure(s, groupKeySetInfo, map[string]any{
+		keyDirectory:   dir,
+		keyScryfallURL: baseURL,
+	})
+	s.http = s.newHTTPClient()
+	s.cache = setinfo.NewCache(s.cacheDirectory())
+	s.icons = make(map[string][]byte)
+	s.failed = make(map[string]time.Time)
+	s.errs = make(map[string]error)
+	s.fetching = make(map[string]chan struct{})
+
+	return s
+}
+
+func TestSetInfo(t *testing.T) {
+	server, err := fakeapi.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	dir := t.TempDir()
+	s := newTestService(t, server.URL, dir)
+
+	sets, err := s.Sets()
+	if err != nil || len(sets) != 4 || sets[0].Code != "m10" || sets[1].Code != "pm10" || sets[3].Code != "mkm" {
+		t.Fatalf("unexpected sets %v (%v)", sets, err)
+	}
+
+	set, err := s.Set("ISD")
+	if err != nil || set.Name != "Innistrad" || set.CardCount != 264 {
+		t.Fatalf("unexpected set %v (%v)", set, err)
+	}
+
+	if _, err = s.Set("xyz"); !errors.Is(err, setinfo.ErrUnknownSet) {
+		t.Fatalf("expected an unknown set, got %v", err)
+	}
+
+	for _, codeOrName := range []string{"ISD", "innistrad"} {
+		if code, errCode := s.SetCode(codeOrName); errCode != nil || code != "isd" {
+			t.Fatalf("expected the code of %s, got %q (%v)", codeOrName, code, errCode)
+		}
+	}
+
+	symbology, err := s.Symbology()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, errCost := symbology.ManaValue("{2/W}{R/P}{X}"); errCost != nil || value != 3 {
+		t.Fatalf("expected a mana value of 3, got %v (%v)", value, errCost)
+	}
+
+	icon, err := s.SetIcon("mkm", 32)
+	if err != nil || icon.Bounds().Dx() != 48 || icon.Bounds().Dy() != 32 {
+		t.Fatalf("unexpected icon %v (%v)", icon.Bounds(), err)
+	}
+
+	for _, name := range []string{"sets.json", "symbology.json", "icons/mkm.svg"} {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s to be cached: %v", name, err)
+		}
+	}
+
+	// everything which was cached is there offline, even when it is old
+	offline := newTestService(t, "http://127.0.0.1:1", dir)
+	offline.cfg.Group(groupKeySetInfo).Set(keyRefreshInterval, "1ns")
+
+	time.Sleep(time.Millisecond)
+
+	if sets, err = offline.Sets(); err != nil || len(sets) != 4 {
+		t.Fatalf("expected the cached sets, got %v (%v)", sets, err)
+	}
+
+	// icons are fetched from their own urls, which are not fetched again
+	requests := len(server.Requests())
+
+	if _, err = offline.SetIconSVG("MKM"); err != nil || len(server.Requests()) != requests {
+		t.Fatalf("expected the cached icon, got %d requests (%v)", len(server.Requests())-requests, err)
+	}
+
+	if err = offline.Refresh(); err == nil {
+		t.Fatal("expected refreshing to fail offline")
+	}
+
+	if _, err = offline.Symbol("{W/U}"); err != nil {
+		t.Fatalf("expected the symbology to be kept after failing to refresh, got %v", err)
+	}
+
+	// without a cache, there is nothing to go by
+	if _, err = newTestService(t, "http://127.0.0.1:1", t.TempDir()).Sets(); err == nil {
+		t.Fatal("expected no sets without scryfall and a cache")
+	}
+}
+
+// TestUnreachableScryfall fetches from a server which hangs until it fails,
+// like an api which is down, without a cache
+func TestUnreachableScryfall(t *testing.T) {
+	hang := make(chan struct{})
+	release := sync.OnceFunc(func() { close(hang) })
+	requests := make(chan string, 16)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r.URL.Path
+
+		if r.URL.Path == "/sets" {
+			<-hang
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	defer release()
+
+	s := newTestService(t, server.URL, t.TempDir())
+
+	// scryfall is down for good, retrying its requests would only be slower
+	s.http.Transport.(*httplimit.Transport).Retries = 0
+
+	done := make(chan error)
+
+	go func() {
+		_, err := s.Sets()
+		done <- err
+	}()
+
+	<-requests
+
+	// the symbology does not wait for the sets
+	symbology := make(chan error, 1)
+
+	go func() {
+		_, err := s.Symbology()
+		symbology <- err
+	}()
+
+	select {
+	case err := <-symbology:
+		if err == nil {
+			t.Fatal("expected no symbology")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out getting the symbology")
+	}
+
+	release()
+
+	if err := <-done; err == nil {
+		t.Fatal("expected no sets")
+	}
+
+	// what failed is not fetched again before retryAfter, even though there
+	// is nothing to use meanwhile
+	for len(requests) > 0 {
+		<-requests
+	}
+
+	if _, err := s.Sets(); err == nil {
+		t.Fatal("expected no sets")
+	}
+
+	if _, err := s.Symbols(); err == nil {
+		t.Fatal("expected no symbols")
+	}
+
+	if len(requests) != 0 {
+		t.Fatalf("expected no requests before retryAfter, got %d", len(requests))
+	}
+}
+
+func TestBinding(t *testing.T) {
+	var b Binding
+
+	if b.Bind(&configFile.Service{}) || b.Sets() != nil {
+		t.Fatal("expected only the set info service to be bound")
+	}
+
+	s := &Service{}
+
+	if !b.Bind(s) || b.Sets() != Dependency(s) {
+		t.Fatal("expected the set info service to be bound")
+	}
+
+	if b.Bind(s) {
+		t.Fatal("expected the set info service to be bound once")
+	}
+}
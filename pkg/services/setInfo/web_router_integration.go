@@ -0,0 +1,127 @@
+package setInfo
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gravestench/mtg/pkg/setinfo"
+)
+
+const (
+	defaultIconSize = 64
+	maxIconSize     = 1024
+)
+
+func (s *Service) Slug() string {
+	return "sets"
+}
+
+func (s *Service) InitRoutes(group *gin.RouterGroup) {
+	group.GET("", s.handleGetSets)
+	group.GET("symbology", s.handleGetSymbology)
+	group.GET(":code", s.handleGetSet)
+	group.GET(":code/icon.svg", s.handleGetIconSVG)
+	group.GET(":code/icon.png", s.handleGetIconPNG)
+}
+
+// handleGetSets yields every set from the oldest to the newest, or from the
+// newest to the oldest with ?order=newest
+func (s *Service) handleGetSets(c *gin.Context) {
+	idx, err := s.Index()
+	if err != nil {
+		c.String(http.StatusBadGateway, "%v", err)
+		return
+	}
+
+	if c.Query("order") == "newest" {
+		c.JSON(http.StatusOK, idx.Newest(nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, idx.Sets())
+}
+
+// handleGetSymbology yields every symbol, or the symbols and the mana value
+// of a mana cost with ?cost={2}{W}{W}
+func (s *Service) handleGetSymbology(c *gin.Context) {
+	symbology, err := s.Symbology()
+	if err != nil {
+		c.String(http.StatusBadGateway, "%v", err)
+		return
+	}
+
+	cost := c.Query("cost")
+	if cost == "" {
+		c.JSON(http.StatusOK, symbology.Symbols())
+		return
+	}
+
+	symbols, err := symbology.ParseCost(cost)
+	if err != nil {
+		c.String(http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	value, _ := symbology.ManaValue(cost)
+
+	c.JSON(http.StatusOK, gin.H{"symbols": symbols, "mana_value": value})
+}
+
+func (s *Service) handleGetSet(c *gin.Context) {
+	set, err := s.Set(c.Param("code"))
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+func (s *Service) handleGetIconSVG(c *gin.Context) {
+	svg, err := s.SetIconSVG(c.Param("code"))
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
+// handleGetIconPNG yields the icon of a set with a height of ?size=64 pixels
+func (s *Service) handleGetIconPNG(c *gin.Context) {
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultIconSize)))
+	if err != nil || size <= 0 || size > maxIconSize {
+		c.String(http.StatusBadRequest, "expected a size of 1 to %d pixels", maxIconSize)
+		return
+	}
+
+	icon, err := s.SetIcon(c.Param("code"), size)
+	if err != nil {
+		s.respondError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, icon); err != nil {
+		c.String(http.StatusInternalServerError, "encoding icon: %v", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", buf.Bytes())
+}
+
+func (s *Service) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, setinfo.ErrUnknownSet):
+		c.String(http.StatusNotFound, "%v", err)
+	case errors.Is(err, setinfo.ErrInvalidSVG):
+		c.String(http.StatusInternalServerError, "%v", err)
+	default:
+		c.String(http.StatusBadGateway, "%v", err)
+	}
+}
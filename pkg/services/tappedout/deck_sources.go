@@ -0,0 +1,44 @@
+package tappedout
+
+import (
+	"strings"
+
+	"github.com/gravestench/mtg/pkg/decksource"
+)
+
+// newRegistry creates the sources of decks, tappedout decks are fetched
+// from the configured site
+func (s *Service) newRegistry() *decksource.Registry {
+	sources := decksource.Default(s.http)
+	sources.Register(&decksource.TappedOut{Client: s.http, BaseURL: s.baseURL()})
+
+	return sources
+}
+
+// deckID yields the ID of the history of the deck at a url. The histories
+// of urls which no source knows are named after the last segment of the
+// url.
+func (s *Service) deckID(uri string) string {
+	source, err := s.sources.Source(uri)
+	if err != nil {
+		return slugFromURI(uri)
+	}
+
+	id, err := source.ID(uri)
+	if err != nil {
+		return slugFromURI(uri)
+	}
+
+	return historyID(source.Name(), id)
+}
+
+// historyID yields the ID of the history of a deck of a source. The decks
+// of the other sources are prefixed with the source, tappedout decks keep
+// their slugs like before there were other sources.
+func historyID(source, id string) string {
+	if id = safeID(id); id == "" || source == (&decksource.TappedOut{}).Name() {
+		return id
+	}
+
+	return strings.ToLower(safeID(source)) + "-" + id
+}
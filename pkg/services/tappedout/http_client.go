@@ -0,0 +1,44 @@
+package tappedout
+
+import (
+	"net/http"
+
+	"github.com/gravestench/mtg/pkg/httplimit"
+)
+
+const (
+	groupKeyTappedOut = "tappedout"
+	keyHistory        = "history directory"
+	keyBaseURL        = "base url"
+	keyProxy          = "proxy"
+
+	defaultBaseURL = "https://tappedout.net"
+	defaultHistory = "deck_history"
+
+	// the deck sites do not say how many requests they take, a deck is
+	// fetched with a request or two
+	requestsPerSecond = 4
+)
+
+// newHTTPClient creates the client of every request to tappedout and the
+// other deck sites
+func (s *Service) newHTTPClient() *http.Client {
+	proxy := s.cfg.Group(groupKeyTappedOut).GetString(keyProxy)
+
+	client, err := httplimit.NewClient(s.Transport, proxy, requestsPerSecond)
+	if err != nil {
+		s.logger.Warn().Msgf("%v, connecting directly", err)
+	}
+
+	return client
+}
+
+// baseURL yields the url of the configured tappedout site
+func (s *Service) baseURL() string {
+	base := s.cfg.Group(groupKeyTappedOut).GetString(keyBaseURL)
+	if base == "" {
+		return defaultBaseURL
+	}
+
+	return base
+}
@@ -1,22 +1,34 @@
 package tappedout
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/BlueMonday/go-scryfall"
 	"github.com/gravestench/runtime"
 	"github.com/rs/zerolog"
 
+	"github.com/gravestench/mtg/pkg/deckhistory"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/decksource"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
+// Service fetches decks from tappedout, the other deck sites and local
+// files, and keeps the history of every fetched deck
 type Service struct {
+	// Transport sends every request to tappedout, like a recording or a
+	// fake server in tests. When nil, a transport is created from the
+	// config file.
+	Transport http.RoundTripper
+
+	http       *http.Client
+	sources    *decksource.Registry
 	client     *scryfall.Client
 	logger     *zerolog.Logger
 	cfgManager configFile.Dependency
@@ -53,6 +65,8 @@ func (s *Service) Init(rt runtime.Runtime) {
 	}
 
 	s.cfg = cfg
+	s.http = s.newHTTPClient()
+	s.sources = s.newRegistry()
 }
 
 func (s *Service) Name() string {
@@ -72,70 +86,143 @@ func (s *Service) ConfigFileName() string {
 }
 
 func (s *Service) DefaultConfig() (cfg configFile.Config) {
-	cfg.Group("tappedout").Set("directory", "/tmp")
+	g := cfg.Group(groupKeyTappedOut)
+
+	g.Set(keyHistory, defaultHistory)
+	g.Set(keyBaseURL, defaultBaseURL)
+	g.Set(keyProxy, "")
 
 	return
 }
 
+// GetDeckList fetches a deck from any deck site or file, and yields it as
+// an MTG Arena deck list
 func (s *Service) GetDeckList(uri string) (string, error) {
-	const decksEndpoint = "https://tappedout.net/mtg-decks"
+	deck, err := s.FetchDeck(uri)
+	if err != nil {
+		return "", err
+	}
 
-	uri = strings.Trim(uri, "/ ")
-	uri = strings.ReplaceAll(uri, "http://", "https://")
+	return decklist.Export(decklist.FormatArena, deck.Deck)
+}
 
-	if !strings.HasPrefix(uri, decksEndpoint) {
-		uri = strings.Join([]string{decksEndpoint, uri}, "/")
+// FetchDeck fetches a deck from the source of its url, like tappedout,
+// moxfield or a local file. Every fetched deck is saved as a version of its
+// history, the deck library keeps the decks themselves.
+func (s *Service) FetchDeck(uri string) (*decksource.Deck, error) {
+	deck, err := s.sources.Fetch(context.Background(), uri)
+	if deck == nil {
+		return nil, fmt.Errorf("fetching deck: %w", err)
 	}
 
-	xmlBytes, err := getXML(uri)
 	if err != nil {
-		return "", fmt.Errorf("failed to get XML: %v", err)
+		s.logger.Warn().Msgf("parsing deck list from %s: %v", deck.Source, err)
 	}
 
-	const regexGrabDeckList = `<textarea id="mtga-textarea">(([^<])+\n?\t*)+`
-	r := regexp.MustCompile(regexGrabDeckList)
+	list, err := decklist.Export(decklist.FormatArena, deck.Deck)
+	if err != nil {
+		return nil, err
+	}
 
-	matches := r.FindStringSubmatch(string(xmlBytes))
+	id := historyID(deck.Source, deck.ID)
+	if id == "" {
+		return nil, fmt.Errorf("deck from %s has no ID", deck.Source)
+	}
 
-	if len(matches) < 2 {
-		return "", fmt.Errorf("could not find deck list on page")
+	if _, _, err = s.history().Save(id, list, time.Now()); err != nil {
+		return nil, fmt.Errorf("saving deck list version: %v", err)
 	}
 
-	listBytes := matches[1]
+	return deck, nil
+}
 
-	dstDir := s.cfg.Group("tappedout").GetString("directory")
-	uriParts := strings.Split(uri, "/")
-	fileName := uriParts[len(uriParts)-1]
-	decklistPath := filepath.Join(dstDir, fmt.Sprintf("%s.txt", fileName))
+// DeckVersions yields every fetched version of a deck, oldest first
+func (s *Service) DeckVersions(uri string) ([]deckhistory.Version, error) {
+	return s.history().Versions(s.deckID(uri))
+}
 
-	if _, err = os.Stat(dstDir); os.IsNotExist(err) {
-		err = os.Mkdir(dstDir, 0755)
-		// TODO: handle error
-	}
+// DeckChangelog yields what changed between every fetched version of a deck
+func (s *Service) DeckChangelog(uri string) ([]deckhistory.ChangelogEntry, error) {
+	return s.history().Changelog(s.deckID(uri))
+}
 
-	err = os.WriteFile(decklistPath, []byte(listBytes), 0777)
+// Decks yields the latest fetched version of every deck, by slug
+func (s *Service) Decks() (map[string]*decklist.Deck, error) {
+	store := s.history()
+
+	slugs, err := store.Decks()
 	if err != nil {
-		return "", fmt.Errorf("writing deck list from tappedout.net: %v", err)
+		return nil, err
+	}
+
+	decks := make(map[string]*decklist.Deck)
+
+	for _, slug := range slugs {
+		version, err := store.Latest(slug)
+		if err != nil {
+			return nil, err
+		}
+
+		deck, err := store.Load(version)
+		if err != nil {
+			return nil, err
+		}
+
+		decks[slug] = deck
 	}
 
-	return listBytes, nil
+	return decks, nil
 }
 
-func getXML(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return []byte{}, fmt.Errorf("GET error: %v", err)
+// history stores every version of the fetched deck lists, in the history
+// directory
+func (s *Service) history() *deckhistory.Store {
+	return deckhistory.New(HistoryDirectory(s.cfgManager, s.cfg))
+}
+
+// HistoryDirectory yields the history directory of the config file of this
+// service, which may be nil. Relative paths are relative to the config file
+// directory.
+func HistoryDirectory(cfgManager configFile.Dependency, cfg *configFile.Config) string {
+	dir := ""
+	if cfg != nil {
+		dir = cfg.Group(groupKeyTappedOut).GetString(keyHistory)
+	}
+
+	if dir == "" {
+		dir = defaultHistory
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return []byte{}, fmt.Errorf("Status error: %v", resp.StatusCode)
+	if !filepath.IsAbs(dir) {
+		dir = cfgManager.GetFilePath(dir)
 	}
 
-	data, err := ioutil.ReadAll(resp.Body)
+	return dir
+}
+
+// slugFromURI yields the slug of a deck, like "a-slow-painful-death" for
+// https://tappedout.net/mtg-decks/a-slow-painful-death/
+func slugFromURI(uri string) string {
+	uriParts := strings.Split(strings.Trim(uri, "/ "), "/")
+	return safeID(uriParts[len(uriParts)-1])
+}
+
+var regexUnsafeID = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// safeID reduces the ID of a history to letters, digits, underscores and
+// dashes, as it is the name of a file and a directory of the history. The
+// IDs of some sites are case-sensitive, so the case is kept.
+func safeID(id string) string {
+	return strings.Trim(regexUnsafeID.ReplaceAllString(id, "-"), "-")
+}
+
+// GetDeck fetches a deck and parses it, so that it can be exported to
+// other clients with decklist.Export
+func (s *Service) GetDeck(uri string) (*decklist.Deck, error) {
+	deck, err := s.FetchDeck(uri)
 	if err != nil {
-		return []byte{}, fmt.Errorf("Read body: %v", err)
+		return nil, err
 	}
 
-	return data, nil
+	return deck.Deck, nil
 }
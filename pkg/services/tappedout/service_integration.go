@@ -3,6 +3,9 @@ package tappedout
 import (
 	"github.com/gravestench/runtime"
 
+	"github.com/gravestench/mtg/pkg/deckhistory"
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/decksource"
 	"github.com/gravestench/mtg/pkg/services/configFile"
 )
 
@@ -14,4 +17,9 @@ type TappedoutClient interface {
 	runtime.HasDependencies
 	configFile.HasDefaultConfig
 	GetDeckList(uri string) (string, error)
+	GetDeck(uri string) (*decklist.Deck, error)
+	FetchDeck(uri string) (*decksource.Deck, error)
+	DeckVersions(uri string) ([]deckhistory.Version, error)
+	DeckChangelog(uri string) ([]deckhistory.ChangelogEntry, error)
+	Decks() (map[string]*decklist.Deck, error)
 }
@@ -0,0 +1,75 @@
+package tappedout
+
+import (
+	"testing"
+
+	"github.com/gravestench/mtg/pkg/decklist"
+	"github.com/gravestench/mtg/pkg/fakeapi"
+	"github.com/gravestench/mtg/pkg/internal/servicetest"
+)
+
+// newTestService creates a service which fetches decks from a fake server,
+// and stores them in a temporary directory
+func newTestService(t *testing.T, server *fakeapi.Server) *Service {
+	s := &Service{}
+	s.cfg = servicetest.Configure(s, groupKeyTappedOut, map[string]any{
+		keyBaseURL: server.URL,
+		keyHistory: t.TempDir(),
+	})
+	s.http = s.newHTTPClient()
+	s.sources = s.newRegistry()
+
+	return s
+}
+
+func TestGetDeck(t *testing.T) {
+	server, err := fakeapi.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	s := newTestService(t, server)
+
+	// the url of the real site is fetched from the configured one
+	deck, err := s.GetDeck("https://tappedout.net/mtg-decks/izzet-delver/")
+	if err != nil {
+		t.Fatalf("getting deck: %v", err)
+	}
+
+	if count := deck.Count(decklist.SectionMain); count != 28 {
+		t.Fatalf("expected 28 cards, got %d", count)
+	}
+
+	if entry := deck.Section(decklist.SectionMain)[1]; entry.Name != "Delver of Secrets" || entry.Set != "ISD" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	if versions, _ := s.DeckVersions("izzet-delver"); len(versions) != 1 {
+		t.Fatalf("expected a single version, got %d", len(versions))
+	}
+
+	if _, err = s.GetDeck("no-such-deck"); err == nil {
+		t.Fatal("expected an error for a missing deck")
+	}
+}
+
+func TestSlugFromURI(t *testing.T) {
+	tests := map[string]string{
+		"https://tappedout.net/mtg-decks/a-slow-painful-death/": "a-slow-painful-death",
+		"Izzet Delver!":            "Izzet-Delver",
+		`..\..\etc\passwd`:         "etc-passwd",
+		"https://tappedout.net/..": "",
+		"moxfield-AbC_12":          "moxfield-AbC_12",
+	}
+
+	for uri, expected := range tests {
+		if slug := slugFromURI(uri); slug != expected {
+			t.Errorf("%s: expected %q, got %q", uri, expected, slug)
+		}
+	}
+	// moxfield ids are case-sensitive
+	if id := historyID("Moxfield", "AbC_12"); id != "moxfield-AbC_12" {
+		t.Errorf("expected the case of the id to be kept, got %q", id)
+	}
+}
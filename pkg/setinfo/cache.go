@@ -0,0 +1,131 @@
+package setinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	setsFileName      = "sets.json"
+	symbologyFileName = "symbology.json"
+	iconDirectory     = "icons"
+)
+
+// SetList is the cached sets, and when they were fetched
+type SetList struct {
+	Fetched time.Time `json:"fetched"`
+	Sets    []Set     `json:"sets"`
+}
+
+// Stale yields whether the sets are older than a refresh interval
+func (l SetList) Stale(interval time.Duration, now time.Time) bool {
+	return stale(l.Fetched, interval, now)
+}
+
+// SymbolList is the cached symbology, and when it was fetched
+type SymbolList struct {
+	Fetched time.Time `json:"fetched"`
+	Symbols []Symbol  `json:"symbols"`
+}
+
+// Stale yields whether the symbology is older than a refresh interval
+func (l SymbolList) Stale(interval time.Duration, now time.Time) bool {
+	return stale(l.Fetched, interval, now)
+}
+
+func stale(fetched time.Time, interval time.Duration, now time.Time) bool {
+	return fetched.IsZero() || now.Sub(fetched) > interval
+}
+
+// Cache keeps the sets, the symbology and the set icons in a directory:
+// <dir>/sets.json, <dir>/symbology.json and <dir>/icons/<code>.svg
+type Cache struct {
+	dir string
+}
+
+// NewCache creates a cache in a directory, which is created when something
+// is saved
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// LoadSets reads the cached sets, sets which were never saved yield
+// os.ErrNotExist
+func (c *Cache) LoadSets() (SetList, error) {
+	var list SetList
+	return list, c.load(setsFileName, &list)
+}
+
+// SaveSets writes the sets
+func (c *Cache) SaveSets(list SetList) error {
+	return c.save(setsFileName, list)
+}
+
+// LoadSymbology reads the cached symbology, a symbology which was never saved
+// yields os.ErrNotExist
+func (c *Cache) LoadSymbology() (SymbolList, error) {
+	var list SymbolList
+	return list, c.load(symbologyFileName, &list)
+}
+
+// SaveSymbology writes the symbology
+func (c *Cache) SaveSymbology(list SymbolList) error {
+	return c.save(symbologyFileName, list)
+}
+
+// LoadIcon reads the cached svg icon of a set, an icon which was never saved
+// yields os.ErrNotExist
+func (c *Cache) LoadIcon(code string) ([]byte, error) {
+	return os.ReadFile(c.iconPath(code))
+}
+
+// SaveIcon writes the svg icon of a set
+func (c *Cache) SaveIcon(code string, svg []byte) error {
+	if err := os.MkdirAll(filepath.Join(c.dir, iconDirectory), 0755); err != nil {
+		return fmt.Errorf("creating icon directory: %v", err)
+	}
+
+	if err := os.WriteFile(c.iconPath(code), svg, 0644); err != nil {
+		return fmt.Errorf("writing icon of %s: %v", code, err)
+	}
+
+	return nil
+}
+
+func (c *Cache) load(name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding cached %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func (c *Cache) save(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %v", name, err)
+	}
+
+	if err = os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("creating set info directory: %v", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(c.dir, name), data, 0644); err != nil {
+		return fmt.Errorf("writing cached %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func (c *Cache) iconPath(code string) string {
+	return filepath.Join(c.dir, iconDirectory, strings.ToLower(filepath.Base(code))+".svg")
+}
@@ -0,0 +1,50 @@
+package setinfo
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gravestench/mtg/pkg/httplimit"
+)
+
+// scryfallList is a page of a list of scryfall, lists of sets and of
+// symbols fit in a single page but may not always
+type scryfallList[T any] struct {
+	Data     []T    `json:"data"`
+	HasMore  bool   `json:"has_more"`
+	NextPage string `json:"next_page"`
+}
+
+// FetchSets fetches every set from the scryfall api at a base url, which
+// ends with a slash
+func FetchSets(ctx context.Context, client *http.Client, scryfallURL string) ([]Set, error) {
+	return fetchList[Set](ctx, client, scryfallURL+"sets")
+}
+
+// FetchSymbology fetches every symbol from the scryfall api at a base url,
+// which ends with a slash
+func FetchSymbology(ctx context.Context, client *http.Client, scryfallURL string) ([]Symbol, error) {
+	return fetchList[Symbol](ctx, client, scryfallURL+"symbology")
+}
+
+// fetchList fetches every page of a list
+func fetchList[T any](ctx context.Context, client *http.Client, uri string) ([]T, error) {
+	values := make([]T, 0)
+
+	for uri != "" {
+		var page scryfallList[T]
+
+		if err := httplimit.GetJSON(ctx, client, uri, &page); err != nil {
+			return nil, err
+		}
+
+		values = append(values, page.Data...)
+
+		uri = ""
+		if page.HasMore {
+			uri = page.NextPage
+		}
+	}
+
+	return values, nil
+}
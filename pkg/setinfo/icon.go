@@ -0,0 +1,296 @@
+package setinfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidSVG = errors.New("invalid svg")
+
+const (
+	// every pixel is sampled this many times in each direction
+	samples = 4
+
+	curveSegments = 16
+)
+
+// Icon rasterizes the svg icon of a set in a color, with a height in pixels
+// and the width of its view box
+func Icon(svg []byte, height int, c color.Color) (*image.NRGBA, error) {
+	mask, err := Rasterize(svg, height)
+	if err != nil {
+		return nil, err
+	}
+
+	icon := image.NewNRGBA(mask.Bounds())
+	draw.DrawMask(icon, icon.Bounds(), image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Src)
+
+	return icon, nil
+}
+
+// Rasterize yields the coverage of the shapes of an svg, with a height in
+// pixels and the width of its view box. Set icons are single color, so only
+// the geometry of the paths, polygons, rectangles, circles and ellipses is
+// drawn; colors, strokes and transforms are ignored.
+func Rasterize(svg []byte, height int) (*image.Alpha, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("%w: the height is %d", ErrInvalidSVG, height)
+	}
+
+	doc, err := parseSVG(svg)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := float64(height) / doc.viewBox[3]
+	width := max(1, int(math.Ceil(doc.viewBox[2]*scale)))
+	mask := image.NewAlpha(image.Rect(0, 0, width, height))
+
+	for _, shape := range doc.shapes {
+		for _, polygon := range shape {
+			for idx, p := range polygon {
+				polygon[idx] = point{(p.x - doc.viewBox[0]) * scale, (p.y - doc.viewBox[1]) * scale}
+			}
+		}
+
+		fill(mask, shape)
+	}
+
+	return mask, nil
+}
+
+type point struct {
+	x, y float64
+}
+
+// shape is the closed polygons of an element, which are filled together
+type shape [][]point
+
+type svgDocument struct {
+	viewBox [4]float64
+	shapes  []shape
+}
+
+func parseSVG(data []byte) (*svgDocument, error) {
+	doc := &svgDocument{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	hasRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		attrs := make(map[string]string, len(element.Attr))
+		for _, attr := range element.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+
+		switch element.Name.Local {
+		case "svg":
+			if !hasRoot {
+				hasRoot = true
+
+				if doc.viewBox, err = viewBox(attrs); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		case "defs", "clipPath", "mask", "symbol", "style", "title", "desc":
+			// nothing in there is drawn where it is declared
+			if err = decoder.Skip(); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+			}
+
+			continue
+		}
+
+		if attrs["fill"] == "none" || attrs["display"] == "none" {
+			continue
+		}
+
+		s, err := elementShape(element.Name.Local, attrs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSVG, element.Name.Local, err)
+		}
+
+		if len(s) > 0 {
+			doc.shapes = append(doc.shapes, s)
+		}
+	}
+
+	if !hasRoot {
+		return nil, fmt.Errorf("%w: no svg element", ErrInvalidSVG)
+	}
+
+	return doc, nil
+}
+
+// viewBox yields the view box of the svg element, or its width and height
+func viewBox(attrs map[string]string) ([4]float64, error) {
+	var box [4]float64
+
+	if values := numbers(attrs["viewBox"]); len(values) == 4 {
+		copy(box[:], values)
+	} else {
+		box[2] = length(attrs["width"])
+		box[3] = length(attrs["height"])
+	}
+
+	if box[2] <= 0 || box[3] <= 0 {
+		return box, fmt.Errorf("%w: the svg has no size", ErrInvalidSVG)
+	}
+
+	return box, nil
+}
+
+func elementShape(name string, attrs map[string]string) (shape, error) {
+	switch name {
+	case "path":
+		return parsePath(attrs["d"])
+	case "polygon", "polyline":
+		values := numbers(attrs["points"])
+
+		polygon := make([]point, 0, len(values)/2)
+		for idx := 0; idx+1 < len(values); idx += 2 {
+			polygon = append(polygon, point{values[idx], values[idx+1]})
+		}
+
+		return shape{polygon}, nil
+	case "rect":
+		x, y := length(attrs["x"]), length(attrs["y"])
+		w, h := length(attrs["width"]), length(attrs["height"])
+
+		return shape{{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}}, nil
+	case "circle":
+		r := length(attrs["r"])
+		return shape{ellipse(length(attrs["cx"]), length(attrs["cy"]), r, r)}, nil
+	case "ellipse":
+		return shape{ellipse(length(attrs["cx"]), length(attrs["cy"]), length(attrs["rx"]), length(attrs["ry"]))}, nil
+	}
+
+	return nil, nil
+}
+
+func ellipse(cx, cy, rx, ry float64) []point {
+	polygon := make([]point, 0, 4*curveSegments)
+
+	for idx := 0; idx < 4*curveSegments; idx++ {
+		angle := 2 * math.Pi * float64(idx) / (4 * curveSegments)
+		polygon = append(polygon, point{cx + rx*math.Cos(angle), cy + ry*math.Sin(angle)})
+	}
+
+	return polygon
+}
+
+// length parses a length like "12" or "12px", units are ignored
+func length(value string) float64 {
+	value = strings.TrimRight(strings.TrimSpace(value), "abcdefghijklmnopqrstuvwxyz%")
+
+	f, _ := strconv.ParseFloat(value, 64)
+
+	return f
+}
+
+// numbers parses a list of numbers separated by spaces or commas
+func numbers(value string) []float64 {
+	s := &pathScanner{s: value}
+	values := make([]float64, 0)
+
+	for s.hasNumber() {
+		f, err := s.number()
+		if err != nil {
+			break
+		}
+
+		values = append(values, f)
+	}
+
+	return values
+}
+
+// fill adds the coverage of a shape to a mask, filling with the nonzero rule
+func fill(mask *image.Alpha, s shape) {
+	bounds := mask.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	coverage := make([]uint8, width*height)
+
+	type crossing struct {
+		x         float64
+		direction int
+	}
+
+	crossings := make([]crossing, 0)
+
+	for row := 0; row < height*samples; row++ {
+		y := (float64(row) + 0.5) / samples
+		crossings = crossings[:0]
+
+		for _, polygon := range s {
+			for idx := range polygon {
+				a, b := polygon[idx], polygon[(idx+1)%len(polygon)]
+				if a.y == b.y {
+					continue
+				}
+
+				direction := 1
+				if a.y > b.y {
+					a, b, direction = b, a, -1
+				}
+
+				if y < a.y || y >= b.y {
+					continue
+				}
+
+				x := a.x + (y-a.y)*(b.x-a.x)/(b.y-a.y)
+				crossings = append(crossings, crossing{x, direction})
+			}
+		}
+
+		sort.Slice(crossings, func(i, j int) bool {
+			return crossings[i].x < crossings[j].x
+		})
+
+		winding := 0
+
+		for idx := 0; idx+1 < len(crossings); idx++ {
+			winding += crossings[idx].direction
+			if winding == 0 {
+				continue
+			}
+
+			// the samples whose centers are in the span
+			first := max(0, int(math.Ceil(crossings[idx].x*samples-0.5)))
+			last := min(width*samples, int(math.Ceil(crossings[idx+1].x*samples-0.5)))
+
+			for column := first; column < last; column++ {
+				coverage[(row/samples)*width+column/samples]++
+			}
+		}
+	}
+
+	for idx, count := range coverage {
+		alpha := uint8(int(count) * 255 / (samples * samples))
+		if alpha > mask.Pix[idx/width*mask.Stride+idx%width] {
+			mask.Pix[idx/width*mask.Stride+idx%width] = alpha
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package setinfo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parsePath flattens the path data of a path element, like "M0 0L10 0 5 8z",
+// into polygons. Curves and arcs become line segments.
+func parsePath(data string) (shape, error) {
+	s := &pathScanner{s: data}
+	p := &pathBuilder{}
+
+	var command byte
+
+	for {
+		if next, ok := s.command(); ok {
+			command = next
+		} else if !s.hasNumber() {
+			if s.idx < len(s.s) {
+				return nil, fmt.Errorf("unexpected %q in the path", s.s[s.idx])
+			}
+
+			break
+		} else if command == 0 || command == 'Z' || command == 'z' {
+			return nil, fmt.Errorf("numbers without a command in the path")
+		}
+
+		if err := p.apply(s, command); err != nil {
+			return nil, err
+		}
+
+		// coordinates after a move are lines
+		switch command {
+		case 'M':
+			command = 'L'
+		case 'm':
+			command = 'l'
+		}
+	}
+
+	p.close()
+
+	return p.polygons, nil
+}
+
+type pathBuilder struct {
+	polygons shape
+	current  []point
+
+	position, start point
+
+	// the last control point of a curve, reflected by the smooth curves
+	control     point
+	lastCommand byte
+}
+
+func (p *pathBuilder) apply(s *pathScanner, command byte) error {
+	relative := command >= 'a'
+	upper := command &^ 0x20
+
+	var values []float64
+	var err error
+
+	if upper == 'A' {
+		values, err = s.arcArguments()
+	} else {
+		values, err = s.numbers(argumentCount[upper])
+	}
+
+	if err != nil {
+		return fmt.Errorf("%c: %v", command, err)
+	}
+
+	// makes the points of the arguments absolute
+	at := func(x, y float64) point {
+		if relative {
+			return point{p.position.x + x, p.position.y + y}
+		}
+
+		return point{x, y}
+	}
+
+	switch upper {
+	case 'M':
+		p.close()
+		p.position = at(values[0], values[1])
+		p.start = p.position
+		p.current = []point{p.position}
+	case 'L':
+		p.lineTo(at(values[0], values[1]))
+	case 'H':
+		x := values[0]
+		if relative {
+			x += p.position.x
+		}
+
+		p.lineTo(point{x, p.position.y})
+	case 'V':
+		y := values[0]
+		if relative {
+			y += p.position.y
+		}
+
+		p.lineTo(point{p.position.x, y})
+	case 'C':
+		p.cubicTo(at(values[0], values[1]), at(values[2], values[3]), at(values[4], values[5]))
+	case 'S':
+		p.cubicTo(p.reflection('C', 'S'), at(values[0], values[1]), at(values[2], values[3]))
+	case 'Q':
+		p.quadTo(at(values[0], values[1]), at(values[2], values[3]))
+	case 'T':
+		p.quadTo(p.reflection('Q', 'T'), at(values[0], values[1]))
+	case 'A':
+		end := at(values[5], values[6])
+		for _, pt := range arc(p.position, values[0], values[1], values[2], values[3] != 0, values[4] != 0, end) {
+			p.lineTo(pt)
+		}
+
+		p.position = end
+	case 'Z':
+		p.close()
+		p.position = p.start
+		p.current = []point{p.start}
+	default:
+		return fmt.Errorf("unknown path command %q", command)
+	}
+
+	p.lastCommand = upper
+
+	return nil
+}
+
+// argumentCount is how many numbers each command takes
+var argumentCount = map[byte]int{
+	'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0,
+}
+
+func (p *pathBuilder) lineTo(pt point) {
+	if len(p.current) == 0 {
+		p.current = []point{p.position}
+	}
+
+	p.current = append(p.current, pt)
+	p.position = pt
+}
+
+func (p *pathBuilder) cubicTo(c1, c2, end point) {
+	start := p.position
+
+	for idx := 1; idx <= curveSegments; idx++ {
+		t := float64(idx) / curveSegments
+		u := 1 - t
+
+		p.lineTo(point{
+			u*u*u*start.x + 3*u*u*t*c1.x + 3*u*t*t*c2.x + t*t*t*end.x,
+			u*u*u*start.y + 3*u*u*t*c1.y + 3*u*t*t*c2.y + t*t*t*end.y,
+		})
+	}
+
+	p.control = c2
+}
+
+func (p *pathBuilder) quadTo(c, end point) {
+	start := p.position
+
+	for idx := 1; idx <= curveSegments; idx++ {
+		t := float64(idx) / curveSegments
+		u := 1 - t
+
+		p.lineTo(point{
+			u*u*start.x + 2*u*t*c.x + t*t*end.x,
+			u*u*start.y + 2*u*t*c.y + t*t*end.y,
+		})
+	}
+
+	p.control = c
+}
+
+// reflection yields the first control point of a smooth curve, which is the
+// last control point of the curve before reflected about the current point,
+// or the current point when the command before was not a curve of the kind
+func (p *pathBuilder) reflection(curve, smooth byte) point {
+	if p.lastCommand != curve && p.lastCommand != smooth {
+		return p.position
+	}
+
+	return point{2*p.position.x - p.control.x, 2*p.position.y - p.control.y}
+}
+
+// close ends the current polygon, polygons are always filled as if closed
+func (p *pathBuilder) close() {
+	if len(p.current) > 2 {
+		p.polygons = append(p.polygons, p.current)
+	}
+
+	p.current = nil
+}
+
+// arc yields the points of an elliptical arc after its start, following
+// the endpoint to center conversion of the svg specification
+func arc(from point, rx, ry, rotation float64, large, sweep bool, to point) []point {
+	if from == to {
+		return nil
+	}
+
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []point{to}
+	}
+
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+
+	dx, dy := (from.x-to.x)/2, (from.y-to.y)/2
+	x1, y1 := cos*dx+sin*dy, -sin*dx+cos*dy
+
+	// radii which are too small are scaled up until the arc fits
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	numerator := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	denominator := rx*rx*y1*y1 + ry*ry*x1*x1
+
+	coefficient := 0.0
+	if numerator > 0 && denominator > 0 {
+		coefficient = math.Sqrt(numerator / denominator)
+	}
+
+	if large == sweep {
+		coefficient = -coefficient
+	}
+
+	cx1, cy1 := coefficient*rx*y1/ry, -coefficient*ry*x1/rx
+	cx, cy := cos*cx1-sin*cy1+(from.x+to.x)/2, sin*cx1+cos*cy1+(from.y+to.y)/2
+
+	theta := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	delta := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx) - theta
+
+	switch {
+	case sweep && delta < 0:
+		delta += 2 * math.Pi
+	case !sweep && delta > 0:
+		delta -= 2 * math.Pi
+	}
+
+	count := max(4, int(math.Ceil(math.Abs(delta)/(math.Pi/18))))
+	points := make([]point, 0, count)
+
+	for idx := 1; idx < count; idx++ {
+		angle := theta + delta*float64(idx)/float64(count)
+		x, y := rx*math.Cos(angle), ry*math.Sin(angle)
+
+		points = append(points, point{cx + cos*x - sin*y, cy + sin*x + cos*y})
+	}
+
+	return append(points, to)
+}
+
+// pathScanner reads the commands and numbers of path data, which may be
+// written without separators like "M1.5.5-2e1"
+type pathScanner struct {
+	s   string
+	idx int
+}
+
+func (s *pathScanner) skipSeparators() {
+	for s.idx < len(s.s) && strings.IndexByte(" ,\t\r\n", s.s[s.idx]) >= 0 {
+		s.idx++
+	}
+}
+
+func (s *pathScanner) command() (byte, bool) {
+	s.skipSeparators()
+
+	if s.idx < len(s.s) && strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", s.s[s.idx]) >= 0 {
+		s.idx++
+		return s.s[s.idx-1], true
+	}
+
+	return 0, false
+}
+
+func (s *pathScanner) hasNumber() bool {
+	s.skipSeparators()
+	return s.idx < len(s.s) && strings.IndexByte("0123456789+-.", s.s[s.idx]) >= 0
+}
+
+func (s *pathScanner) numbers(count int) ([]float64, error) {
+	values := make([]float64, count)
+
+	for idx := range values {
+		if !s.hasNumber() {
+			return nil, fmt.Errorf("expected %d numbers", count)
+		}
+
+		f, err := s.number()
+		if err != nil {
+			return nil, err
+		}
+
+		values[idx] = f
+	}
+
+	return values, nil
+}
+
+// arcArguments reads the arguments of an arc, whose flags are single digits
+// which may be written without separators like "a5 5 0 015 5"
+func (s *pathScanner) arcArguments() ([]float64, error) {
+	radii, err := s.numbers(3)
+	if err != nil {
+		return nil, err
+	}
+
+	values := append(radii, 0, 0)
+
+	for idx := 3; idx < 5; idx++ {
+		s.skipSeparators()
+
+		if s.idx >= len(s.s) || (s.s[s.idx] != '0' && s.s[s.idx] != '1') {
+			return nil, fmt.Errorf("expected an arc flag")
+		}
+
+		values[idx] = float64(s.s[s.idx] - '0')
+		s.idx++
+	}
+
+	end, err := s.numbers(2)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(values, end...), nil
+}
+
+func (s *pathScanner) number() (float64, error) {
+	s.skipSeparators()
+
+	start := s.idx
+
+	if s.idx < len(s.s) && (s.s[s.idx] == '+' || s.s[s.idx] == '-') {
+		s.idx++
+	}
+
+	s.digits()
+
+	if s.idx < len(s.s) && s.s[s.idx] == '.' {
+		s.idx++
+		s.digits()
+	}
+
+	if s.idx < len(s.s) && (s.s[s.idx] == 'e' || s.s[s.idx] == 'E') {
+		s.idx++
+
+		if s.idx < len(s.s) && (s.s[s.idx] == '+' || s.s[s.idx] == '-') {
+			s.idx++
+		}
+
+		s.digits()
+	}
+
+	f, err := strconv.ParseFloat(s.s[start:s.idx], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s.s[start:s.idx])
+	}
+
+	return f, nil
+}
+
+func (s *pathScanner) digits() {
+	for s.idx < len(s.s) && s.s[s.idx] >= '0' && s.s[s.idx] <= '9' {
+		s.idx++
+	}
+}
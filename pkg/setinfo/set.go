@@ -0,0 +1,156 @@
+package setinfo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrUnknownSet = errors.New("unknown set")
+
+// the set types of scryfall whose sets are opened in booster packs
+var boosterSetTypes = map[string]bool{
+	"core":             true,
+	"expansion":        true,
+	"masters":          true,
+	"draft_innovation": true,
+}
+
+// Set is a set of cards, as described by scryfall
+type Set struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Released   string `json:"released_at,omitempty"`
+	Type       string `json:"set_type"`
+	Parent     string `json:"parent_set_code,omitempty"`
+	Block      string `json:"block,omitempty"`
+	CardCount  int    `json:"card_count"`
+	Digital    bool   `json:"digital,omitempty"`
+	IconSVGURI string `json:"icon_svg_uri,omitempty"`
+}
+
+// ReleaseDate yields the day the set was released, which is zero for sets
+// without a release date
+func (s Set) ReleaseDate() time.Time {
+	released, err := time.Parse(time.DateOnly, s.Released)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return released
+}
+
+// HasBoosters yields whether the set is opened in booster packs, which
+// promos, commander decks and the like are not
+func (s Set) HasBoosters() bool {
+	return boosterSetTypes[s.Type] && !s.Digital
+}
+
+// SortByRelease sorts sets from the oldest to the newest. Sets released on
+// the same day are sorted by code, with a set before the sets it is the
+// parent of, like "m10" before "pm10". Sets without a release date go last.
+func SortByRelease(sets []Set) {
+	sort.SliceStable(sets, func(i, j int) bool {
+		return releasedBefore(sets[i], sets[j])
+	})
+}
+
+func releasedBefore(a, b Set) bool {
+	switch {
+	case a.Released == b.Released:
+	case a.Released == "":
+		return false
+	case b.Released == "":
+		return true
+	default:
+		return a.Released < b.Released
+	}
+
+	if strings.EqualFold(b.Parent, a.Code) {
+		return true
+	}
+
+	if strings.EqualFold(a.Parent, b.Code) {
+		return false
+	}
+
+	return a.Code < b.Code
+}
+
+// Index looks up sets by code or name, regardless of case, and knows the
+// order in which they were released
+type Index struct {
+	sets   []Set
+	byCode map[string]int
+	byName map[string]int
+}
+
+// NewIndex creates an index of sets
+func NewIndex(sets []Set) *Index {
+	idx := &Index{
+		sets:   append([]Set(nil), sets...),
+		byCode: make(map[string]int, len(sets)),
+		byName: make(map[string]int, len(sets)),
+	}
+
+	SortByRelease(idx.sets)
+
+	for position, set := range idx.sets {
+		idx.byCode[strings.ToLower(set.Code)] = position
+
+		// the oldest set of a name wins, the sets after it are reprints
+		if _, found := idx.byName[strings.ToLower(set.Name)]; !found {
+			idx.byName[strings.ToLower(set.Name)] = position
+		}
+	}
+
+	return idx
+}
+
+// Sets yields every set, from the oldest to the newest
+func (idx *Index) Sets() []Set {
+	return append([]Set(nil), idx.sets...)
+}
+
+// Set yields the set of a code, like "m10" or "M10"
+func (idx *Index) Set(code string) (Set, error) {
+	position, found := idx.byCode[strings.ToLower(strings.TrimSpace(code))]
+	if !found {
+		return Set{}, fmt.Errorf("%w: %s", ErrUnknownSet, code)
+	}
+
+	return idx.sets[position], nil
+}
+
+// SetByName yields the set of a name, like "Magic 2010"
+func (idx *Index) SetByName(name string) (Set, error) {
+	position, found := idx.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !found {
+		return Set{}, fmt.Errorf("%w: %s", ErrUnknownSet, name)
+	}
+
+	return idx.sets[position], nil
+}
+
+// ReleaseOrder yields the position of a set when sorted from the oldest to
+// the newest, and false for unknown sets
+func (idx *Index) ReleaseOrder(code string) (int, bool) {
+	position, found := idx.byCode[strings.ToLower(strings.TrimSpace(code))]
+	return position, found
+}
+
+// Newest yields the sets which match a filter, from the newest to the
+// oldest, every set if the filter is nil
+func (idx *Index) Newest(filter func(Set) bool) []Set {
+	sets := make([]Set, 0, len(idx.sets))
+
+	for position := len(idx.sets) - 1; position >= 0; position-- {
+		if filter == nil || filter(idx.sets[position]) {
+			sets = append(sets, idx.sets[position])
+		}
+	}
+
+	return sets
+}
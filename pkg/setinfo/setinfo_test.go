@@ -0,0 +1,161 @@
+package setinfo
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSets() []Set {
+	return []Set{
+		{Code: "mkm", Name: "Murders at Karlov Manor", Released: "2024-02-09", Type: "expansion"},
+		{Code: "pm10", Name: "Magic 2010 Promos", Released: "2009-07-17", Type: "promo", Parent: "m10"},
+		{Code: "sld", Name: "Secret Lair Drop", Type: "box"},
+		{Code: "m10", Name: "Magic 2010", Released: "2009-07-17", Type: "core"},
+		{Code: "isd", Name: "Innistrad", Released: "2011-09-30", Type: "expansion"},
+	}
+}
+
+func TestIndex(t *testing.T) {
+	idx := NewIndex(testSets())
+
+	order := make([]string, 0)
+	for _, set := range idx.Sets() {
+		order = append(order, set.Code)
+	}
+
+	if expected := "m10 pm10 isd mkm sld"; strings.Join(order, " ") != expected {
+		t.Fatalf("expected release order %q, got %q", expected, strings.Join(order, " "))
+	}
+
+	set, err := idx.Set("MKM")
+	if err != nil || !set.ReleaseDate().Equal(time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected set %v (%v)", set, err)
+	}
+
+	if _, err = idx.Set("xyz"); !errors.Is(err, ErrUnknownSet) {
+		t.Fatalf("expected an unknown set, got %v", err)
+	}
+
+	if set, err = idx.SetByName("magic 2010"); err != nil || set.Code != "m10" {
+		t.Fatalf("expected magic 2010 by name, got %v (%v)", set, err)
+	}
+
+	if position, found := idx.ReleaseOrder("isd"); !found || position != 2 {
+		t.Fatalf("expected innistrad to be the third set, got %d", position)
+	}
+
+	boosters := idx.Newest(Set.HasBoosters)
+	if len(boosters) != 3 || boosters[0].Code != "mkm" || boosters[2].Code != "m10" {
+		t.Fatalf("unexpected booster sets %v", boosters)
+	}
+}
+
+func TestSymbology(t *testing.T) {
+	s := NewSymbology([]Symbol{
+		{Symbol: "{2/W}", ManaValue: 2, Colors: []string{"W"}, Hybrid: true},
+		{Symbol: "{R}", ManaValue: 1, Colors: []string{"R"}},
+		{Symbol: "{1}", ManaValue: 1},
+		{Symbol: "{T}", English: "tap this permanent"},
+	})
+
+	if symbol, err := s.Symbol("2/w"); err != nil || !symbol.Hybrid {
+		t.Fatalf("unexpected symbol %v (%v)", symbol, err)
+	}
+
+	if value, err := s.ManaValue("{1}{R}{R}"); err != nil || value != 3 {
+		t.Fatalf("expected a mana value of 3, got %v (%v)", value, err)
+	}
+
+	if _, err := s.ParseCost("{1}{Q}"); !errors.Is(err, ErrUnknownSymbol) {
+		t.Fatalf("expected an unknown symbol, got %v", err)
+	}
+
+	if _, err := s.ParseCost("{1}R"); !errors.Is(err, ErrUnknownSymbol) {
+		t.Fatalf("expected a symbol without braces to be unknown, got %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(t.TempDir())
+
+	if _, err := c.LoadSets(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no cached sets, got %v", err)
+	}
+
+	fetched := time.Now().UTC().Truncate(time.Second)
+
+	if err := c.SaveSets(SetList{Fetched: fetched, Sets: testSets()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SaveIcon("M10", []byte("<svg/>")); err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := c.LoadSets()
+	if err != nil || len(sets.Sets) != 5 || !sets.Fetched.Equal(fetched) {
+		t.Fatalf("unexpected cached sets %v (%v)", sets, err)
+	}
+
+	if sets.Stale(time.Hour, fetched.Add(time.Minute)) || !sets.Stale(time.Hour, fetched.Add(2*time.Hour)) {
+		t.Fatal("expected the sets to be stale after the refresh interval")
+	}
+
+	if icon, err := c.LoadIcon("m10"); err != nil || string(icon) != "<svg/>" {
+		t.Fatalf("unexpected cached icon %q (%v)", icon, err)
+	}
+}
+
+func TestRasterize(t *testing.T) {
+	// a square with a square hole, a triangle written relatively with an
+	// implicit line, and a circle made of two arcs
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 40 10">
+		<defs><path d="M0 0h40v10H0z"/></defs>
+		<path d="M0,0 L10,0 10,10 0,10 Z M3 3 V7 H7 V3 Z"/>
+		<path d="m10 10l5-10 5 10z"/>
+		<path d="M25 5a5 5 0 1010 0a5 5 0 10-10 0"/>
+		<rect x="0" y="0" width="40" height="10" fill="none"/>
+	</svg>`
+
+	mask, err := Rasterize([]byte(svg), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mask.Bounds().Dx() != 80 || mask.Bounds().Dy() != 20 {
+		t.Fatalf("expected an 80x20 mask, got %v", mask.Bounds())
+	}
+
+	tests := []struct {
+		x, y   int
+		filled bool
+	}{
+		{2, 2, true},    // the square
+		{10, 10, false}, // its hole
+		{30, 16, true},  // the triangle
+		{22, 2, false},  // beside the triangle
+		{60, 10, true},  // the circle
+		{52, 1, false},  // beside the circle
+	}
+
+	for _, test := range tests {
+		if filled := mask.AlphaAt(test.x, test.y).A == 255; filled != test.filled {
+			t.Errorf("expected %d,%d to be filled: %v, got %d", test.x, test.y, test.filled, mask.AlphaAt(test.x, test.y).A)
+		}
+	}
+
+	icon, err := Icon([]byte(svg), 10, color.Black)
+	if err != nil || icon.NRGBAAt(1, 1) != (color.NRGBA{A: 255}) {
+		t.Fatalf("expected a black icon, got %v (%v)", icon.NRGBAAt(1, 1), err)
+	}
+
+	for _, invalid := range []string{`<svg viewBox="0 0 0 0"/>`, `<svg width="10" height="10"><path d="10 10"/></svg>`, `<html/>`} {
+		if _, err = Rasterize([]byte(invalid), 10); !errors.Is(err, ErrInvalidSVG) {
+			t.Errorf("expected %s to be invalid, got %v", invalid, err)
+		}
+	}
+}
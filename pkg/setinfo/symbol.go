@@ -0,0 +1,101 @@
+package setinfo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownSymbol = errors.New("unknown symbol")
+
+// Symbol is a symbol of the rules text and mana costs, like "{W}" or "{T}",
+// as described by scryfall
+type Symbol struct {
+	Symbol             string   `json:"symbol"`
+	English            string   `json:"english"`
+	ManaValue          float64  `json:"mana_value"`
+	Colors             []string `json:"colors"`
+	RepresentsMana     bool     `json:"represents_mana"`
+	AppearsInManaCosts bool     `json:"appears_in_mana_costs"`
+	Hybrid             bool     `json:"hybrid,omitempty"`
+	Phyrexian          bool     `json:"phyrexian,omitempty"`
+	SVGURI             string   `json:"svg_uri,omitempty"`
+}
+
+// Symbology is the table of every symbol
+type Symbology struct {
+	symbols  []Symbol
+	bySymbol map[string]int
+}
+
+// NewSymbology creates the table of symbols
+func NewSymbology(symbols []Symbol) *Symbology {
+	s := &Symbology{
+		symbols:  append([]Symbol(nil), symbols...),
+		bySymbol: make(map[string]int, len(symbols)),
+	}
+
+	for idx, symbol := range s.symbols {
+		s.bySymbol[strings.ToUpper(symbol.Symbol)] = idx
+	}
+
+	return s
+}
+
+// Symbols yields every symbol, in the order of scryfall
+func (s *Symbology) Symbols() []Symbol {
+	return append([]Symbol(nil), s.symbols...)
+}
+
+// Symbol yields a symbol, which is written with or without the braces, like
+// "{2/W}" or "2/w"
+func (s *Symbology) Symbol(symbol string) (Symbol, error) {
+	key := strings.ToUpper(strings.TrimSpace(symbol))
+	if !strings.HasPrefix(key, "{") {
+		key = "{" + key + "}"
+	}
+
+	idx, found := s.bySymbol[key]
+	if !found {
+		return Symbol{}, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	return s.symbols[idx], nil
+}
+
+// ParseCost yields the symbols of a mana cost, like "{2}{W}{W}"
+func (s *Symbology) ParseCost(cost string) ([]Symbol, error) {
+	symbols := make([]Symbol, 0)
+
+	for rest := strings.TrimSpace(cost); rest != ""; rest = strings.TrimSpace(rest) {
+		end := strings.Index(rest, "}")
+		if !strings.HasPrefix(rest, "{") || end < 0 {
+			return nil, fmt.Errorf("%w: %q in %q", ErrUnknownSymbol, rest, cost)
+		}
+
+		symbol, err := s.Symbol(rest[:end+1])
+		if err != nil {
+			return nil, err
+		}
+
+		symbols = append(symbols, symbol)
+		rest = rest[end+1:]
+	}
+
+	return symbols, nil
+}
+
+// ManaValue yields the mana value of a mana cost, like 3 for "{1}{R}{R}"
+func (s *Symbology) ManaValue(cost string) (float64, error) {
+	symbols, err := s.ParseCost(cost)
+	if err != nil {
+		return 0, err
+	}
+
+	value := 0.0
+	for _, symbol := range symbols {
+		value += symbol.ManaValue
+	}
+
+	return value, nil
+}